@@ -9,6 +9,7 @@ import (
 	"github.com/loongkirin/gdk/logger"
 	"github.com/loongkirin/go-family-finance/internal/api/router"
 	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/jobs"
 	"github.com/loongkirin/go-family-finance/internal/migrations"
 )
 
@@ -38,6 +39,10 @@ func main() {
 	apiRouter := router.NewRouter()
 	apiRouter.InitRouter()
 
+	app.AppContext.APP_LOGGER.Info("Start scheduler...", logger.Fields{})
+	jobs.RegisterJobs(app.AppContext.APP_SCHEDULER)
+	app.AppContext.APP_SCHEDULER.Start()
+
 	app.AppContext.APP_LOGGER.Info("Start server...", logger.Fields{})
 	// 启动服务器
 	go func() {
@@ -52,4 +57,6 @@ func main() {
 	<-quit
 
 	app.AppContext.APP_LOGGER.Info("Shutting down server...", logger.Fields{})
+	app.AppContext.APP_SCHEDULER.Stop()
+	app.AppContext.APP_NOTIFIER.Stop()
 }
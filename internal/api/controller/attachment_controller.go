@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/attachment"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+)
+
+type AttachmentController struct {
+	attachmentService attachment.AttachmentService
+	members           auth.MemberDirectory
+}
+
+func NewAttachmentController() *AttachmentController {
+	return &AttachmentController{
+		attachmentService: attachment.NewAttachmentService(
+			repository.NewRepository[attachment.Attachment](app.AppContext.APP_DbContext.GetMasterDb()),
+			app.AppContext.APP_BLOBSTORE,
+			app.AppContext.APP_CONFIG.AttachmentConfig,
+		),
+		members: auth.NewMemberDirectory(repository.NewRepository[auth.User](app.AppContext.APP_DbContext.GetMasterDb())),
+	}
+}
+
+func (t *AttachmentController) Upload(c *gin.Context) {
+	userId, tenantId, err := caller(c, t.members)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	// 预留 1MB 给表单其他字段
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, t.attachmentService.MaxSize()+1<<20)
+
+	var l attachment.UploadAttachmentDTO
+	if err := c.ShouldBind(&l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.BadRequest(c, attachment.ErrFileTooLarge.Error(), map[string]interface{}{})
+			return
+		}
+		response.BadRequest(c, attachment.ErrFileRequired.Error(), map[string]interface{}{})
+		return
+	}
+	if fileHeader.Size > t.attachmentService.MaxSize() {
+		response.BadRequest(c, attachment.ErrFileTooLarge.Error(), map[string]interface{}{})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	defer file.Close()
+	if l.Data, err = io.ReadAll(file); err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.FileName = fileHeader.Filename
+	l.TenantId = tenantId
+	l.UploadedBy = userId
+
+	r, err := t.attachmentService.Upload(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "上传成功", r)
+}
+
+func (t *AttachmentController) List(c *gin.Context) {
+	_, tenantId, err := caller(c, t.members)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.attachmentService.ListAttachments(c, tenantId, c.Query("owner_type"), c.Query("owner_id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *AttachmentController) Download(c *gin.Context) {
+	t.download(c, t.attachmentService.Download)
+}
+
+func (t *AttachmentController) Thumbnail(c *gin.Context) {
+	t.download(c, t.attachmentService.DownloadThumbnail)
+}
+
+func (t *AttachmentController) download(c *gin.Context, fetch func(ctx context.Context, tenantId string, id string) (*attachment.DownloadDTO, error)) {
+	_, tenantId, err := caller(c, t.members)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	file, err := fetch(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	defer file.Reader.Close()
+
+	headers := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": file.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	}
+	if file.Size >= 0 {
+		headers["Content-Length"] = strconv.FormatInt(file.Size, 10)
+	}
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, file.Reader, headers)
+}
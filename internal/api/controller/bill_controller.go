@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/bill"
+)
+
+type BillController struct {
+	billService bill.BillService
+	members     auth.MemberDirectory
+}
+
+func NewBillController() *BillController {
+	return &BillController{
+		billService: newBillService(),
+		members:     auth.NewMemberDirectory(repository.NewRepository[auth.User](app.AppContext.APP_DbContext.GetMasterDb())),
+	}
+}
+
+func newBillService() bill.BillService {
+	return bill.NewBillService(
+		repository.NewRepository[bill.Bill](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[bill.CalendarFeed](app.AppContext.APP_DbContext.GetMasterDb()),
+		app.AppContext.APP_NOTIFIER,
+		auth.NewMemberDirectory(repository.NewRepository[auth.User](app.AppContext.APP_DbContext.GetMasterDb())),
+	)
+}
+
+func (t *BillController) List(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.billService.ListBills(c, tenantId, c.Query("status"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *BillController) Upcoming(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+	days, _ := strconv.Atoi(c.Query("days"))
+
+	r, err := t.billService.ListUpcoming(c, tenantId, days)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *BillController) Overdue(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.billService.ListOverdue(c, tenantId)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *BillController) Create(c *gin.Context) {
+	var l request.DataRequest[bill.CreateBillDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.billService.CreateBill(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *BillController) Update(c *gin.Context) {
+	var l request.DataRequest[bill.UpdateBillDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.billService.UpdateBill(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *BillController) Pay(c *gin.Context) {
+	var l request.DataRequest[bill.PayBillDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.billService.PayBill(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "支付成功", r)
+}
+
+// CreateCalendarFeed 为当前登录用户创建日历订阅链接
+func (t *BillController) CreateCalendarFeed(c *gin.Context) {
+	userId, tenantId, err := caller(c, t.members)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l := request.DataRequest[bill.CreateCalendarFeedDTO]{
+		Data: bill.CreateCalendarFeedDTO{TenantId: tenantId, UserId: userId},
+	}
+
+	r, err := t.billService.CreateCalendarFeed(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+// Calendar 日历客户端直接订阅，链接中的私密 token 即为凭证
+func (t *BillController) Calendar(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("feed"), ".ics")
+	data, err := t.billService.RenderCalendar(c, token)
+	if err != nil {
+		c.String(http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
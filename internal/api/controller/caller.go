@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/oauth"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+)
+
+// claimsKey 令牌校验中间件写入上下文的声明键
+const claimsKey = "claims"
+
+// caller 从访问令牌中取当前用户并查出其所属租户，不信任请求中携带的租户和用户
+func caller(c *gin.Context, members auth.MemberDirectory) (userId string, tenantId string, err error) {
+	value, _ := c.Get(claimsKey)
+	claims, ok := value.(*oauth.Claims)
+	if !ok || claims == nil || len(claims.UserId) == 0 {
+		return "", "", errUnauthorized
+	}
+	tenantId, err = members.TenantOf(c, claims.UserId)
+	if err != nil {
+		return "", "", errUnauthorized
+	}
+	return claims.UserId, tenantId, nil
+}
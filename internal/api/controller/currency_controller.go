@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/currency"
+)
+
+type CurrencyController struct {
+	currencyService currency.CurrencyService
+}
+
+func NewCurrencyController() *CurrencyController {
+	return &CurrencyController{
+		currencyService: newCurrencyService(),
+	}
+}
+
+func newCurrencyService() currency.CurrencyService {
+	return currency.NewCurrencyService(
+		repository.NewRepository[currency.ExchangeRate](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[currency.CurrencySetting](app.AppContext.APP_DbContext.GetMasterDb()),
+	)
+}
+
+func (t *CurrencyController) ListRates(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.currencyService.ListExchangeRates(c, tenantId, c.Query("from"), c.Query("to"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *CurrencyController) SaveRate(c *gin.Context) {
+	var l request.DataRequest[currency.SaveExchangeRateDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.currencyService.SaveExchangeRate(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "保存成功", r)
+}
+
+func (t *CurrencyController) ImportRates(c *gin.Context) {
+	var l currency.ImportExchangeRatesDTO
+	if err := c.ShouldBind(&l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	defer file.Close()
+	if l.Data, err = io.ReadAll(file); err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.currencyService.ImportExchangeRates(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "导入成功", r)
+}
+
+func (t *CurrencyController) GetSetting(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.currencyService.GetSetting(c, tenantId)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *CurrencyController) SaveSetting(c *gin.Context) {
+	var l request.DataRequest[currency.CurrencySettingDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.currencyService.SaveSetting(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "保存成功", r)
+}
+
+func (t *CurrencyController) Convert(c *gin.Context) {
+	var l request.DataRequest[currency.ConvertDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.currencyService.Convert(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "折算成功", r)
+}
+
+func (t *CurrencyController) Revalue(c *gin.Context) {
+	var l request.DataRequest[currency.RevalueDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.currencyService.Revalue(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "重估成功", r)
+}
@@ -0,0 +1,9 @@
+package controller
+
+import "errors"
+
+var (
+	errTenantIdRequired    = errors.New("租户ID不能为空")
+	errUnauthorized        = errors.New("未登录或登录已失效")
+	errExportFormatInvalid = errors.New("导出格式无效")
+)
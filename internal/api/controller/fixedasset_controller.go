@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/fixedasset"
+)
+
+type FixedAssetController struct {
+	fixedAssetService fixedasset.FixedAssetService
+}
+
+func NewFixedAssetController() *FixedAssetController {
+	return &FixedAssetController{
+		fixedAssetService: newFixedAssetService(),
+	}
+}
+
+func newFixedAssetService() fixedasset.FixedAssetService {
+	return fixedasset.NewFixedAssetService(
+		repository.NewRepository[fixedasset.FixedAsset](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[fixedasset.AssetValuation](app.AppContext.APP_DbContext.GetMasterDb()),
+	)
+}
+
+func (t *FixedAssetController) List(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.fixedAssetService.ListAssets(c, tenantId, c.Query("include_disposed") == "true")
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *FixedAssetController) Get(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.fixedAssetService.GetAsset(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *FixedAssetController) Schedule(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.fixedAssetService.GetSchedule(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *FixedAssetController) Create(c *gin.Context) {
+	var l request.DataRequest[fixedasset.CreateFixedAssetDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.fixedAssetService.CreateAsset(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *FixedAssetController) Update(c *gin.Context) {
+	var l request.DataRequest[fixedasset.UpdateFixedAssetDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.fixedAssetService.UpdateAsset(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *FixedAssetController) AddValuation(c *gin.Context) {
+	var l request.DataRequest[fixedasset.CreateValuationDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.AssetId = c.Param("id")
+
+	r, err := t.fixedAssetService.AddValuation(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *FixedAssetController) Dispose(c *gin.Context) {
+	var l request.DataRequest[fixedasset.DisposeFixedAssetDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.fixedAssetService.DisposeAsset(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "处置成功", r)
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/goal"
+)
+
+type GoalController struct {
+	goalService goal.GoalService
+}
+
+func NewGoalController() *GoalController {
+	return &GoalController{
+		goalService: goal.NewGoalService(
+			repository.NewRepository[goal.Goal](app.AppContext.APP_DbContext.GetMasterDb()),
+			repository.NewRepository[goal.GoalContribution](app.AppContext.APP_DbContext.GetMasterDb()),
+		),
+	}
+}
+
+func (t *GoalController) List(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.goalService.ListGoals(c, tenantId, c.Query("status"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *GoalController) Get(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.goalService.GetGoal(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *GoalController) Create(c *gin.Context) {
+	var l request.DataRequest[goal.CreateGoalDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.goalService.CreateGoal(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *GoalController) Update(c *gin.Context) {
+	var l request.DataRequest[goal.UpdateGoalDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.goalService.UpdateGoal(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *GoalController) Contribute(c *gin.Context) {
+	var l request.DataRequest[goal.CreateContributionDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.GoalId = c.Param("id")
+
+	r, err := t.goalService.AddContribution(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "存入成功", r)
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/insurance"
+)
+
+type InsuranceController struct {
+	insuranceService insurance.InsuranceService
+}
+
+func NewInsuranceController() *InsuranceController {
+	return &InsuranceController{
+		insuranceService: insurance.NewInsuranceService(
+			repository.NewRepository[insurance.Policy](app.AppContext.APP_DbContext.GetMasterDb()),
+			repository.NewRepository[insurance.PolicyClaim](app.AppContext.APP_DbContext.GetMasterDb()),
+			newBillService(),
+			newReimbursementService(),
+			app.AppContext.APP_NOTIFIER,
+			auth.NewMemberDirectory(repository.NewRepository[auth.User](app.AppContext.APP_DbContext.GetMasterDb())),
+		),
+	}
+}
+
+func (t *InsuranceController) List(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.insuranceService.ListPolicies(c, tenantId, c.Query("insured_user_id"), c.Query("status"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InsuranceController) Get(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.insuranceService.GetPolicy(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InsuranceController) Summary(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.insuranceService.GetPremiumSummary(c, tenantId)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InsuranceController) Create(c *gin.Context) {
+	var l request.DataRequest[insurance.CreatePolicyDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.insuranceService.CreatePolicy(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *InsuranceController) Update(c *gin.Context) {
+	var l request.DataRequest[insurance.UpdatePolicyDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.insuranceService.UpdatePolicy(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *InsuranceController) Renew(c *gin.Context) {
+	var l request.DataRequest[insurance.RenewPolicyDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.insuranceService.RenewPolicy(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "续保成功", r)
+}
+
+func (t *InsuranceController) CreateClaim(c *gin.Context) {
+	var l request.DataRequest[insurance.CreatePolicyClaimDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.PolicyId = c.Param("id")
+
+	r, err := t.insuranceService.CreateClaim(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
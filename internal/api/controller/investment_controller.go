@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/investment"
+)
+
+type InvestmentController struct {
+	investmentService investment.InvestmentService
+}
+
+func NewInvestmentController() *InvestmentController {
+	return &InvestmentController{
+		investmentService: newInvestmentService(),
+	}
+}
+
+func newInvestmentService() investment.InvestmentService {
+	return investment.NewInvestmentService(
+		repository.NewRepository[investment.Portfolio](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[investment.Security](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[investment.InvestmentTransaction](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[investment.SecurityPrice](app.AppContext.APP_DbContext.GetMasterDb()),
+		newCurrencyService(),
+	)
+}
+
+func (t *InvestmentController) ListPortfolios(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.ListPortfolios(c, tenantId)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InvestmentController) CreatePortfolio(c *gin.Context) {
+	var l request.DataRequest[investment.CreatePortfolioDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.CreatePortfolio(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *InvestmentController) UpdatePortfolio(c *gin.Context) {
+	var l request.DataRequest[investment.UpdatePortfolioDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.investmentService.UpdatePortfolio(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *InvestmentController) Holdings(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.GetHoldings(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InvestmentController) ListTransactions(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.ListTransactions(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InvestmentController) AddTransaction(c *gin.Context) {
+	var l request.DataRequest[investment.CreateTransactionDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.PortfolioId = c.Param("id")
+
+	r, err := t.investmentService.AddTransaction(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *InvestmentController) ListSecurities(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.ListSecurities(c, tenantId)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InvestmentController) CreateSecurity(c *gin.Context) {
+	var l request.DataRequest[investment.CreateSecurityDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.CreateSecurity(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *InvestmentController) ListPrices(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.ListPrices(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InvestmentController) SavePrice(c *gin.Context) {
+	var l request.DataRequest[investment.SavePriceDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.SavePrice(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "保存成功", r)
+}
+
+func (t *InvestmentController) ImportPrices(c *gin.Context) {
+	var l investment.ImportPricesDTO
+	if err := c.ShouldBind(&l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	defer file.Close()
+	if l.Data, err = io.ReadAll(file); err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.ImportPrices(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "导入成功", r)
+}
+
+func (t *InvestmentController) Valuation(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.GetValuation(c, tenantId, c.Query("portfolio_id"), c.Query("from"), c.Query("to"), c.Query("granularity"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *InvestmentController) Allocation(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.investmentService.GetAllocation(c, tenantId, c.Query("portfolio_id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/loan"
+)
+
+type LoanController struct {
+	loanService loan.LoanService
+}
+
+func NewLoanController() *LoanController {
+	return &LoanController{
+		loanService: newLoanService(),
+	}
+}
+
+func newLoanService() loan.LoanService {
+	return loan.NewLoanService(
+		repository.NewRepository[loan.Loan](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[loan.LoanRateChange](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[loan.LoanPayment](app.AppContext.APP_DbContext.GetMasterDb()),
+	)
+}
+
+func (t *LoanController) List(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.loanService.ListLoans(c, tenantId, c.Query("status"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *LoanController) Get(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.loanService.GetLoan(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *LoanController) Schedule(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.loanService.GetSchedule(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *LoanController) Create(c *gin.Context) {
+	var l request.DataRequest[loan.CreateLoanDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.loanService.CreateLoan(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *LoanController) Update(c *gin.Context) {
+	var l request.DataRequest[loan.UpdateLoanDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.loanService.UpdateLoan(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *LoanController) AddRateChange(c *gin.Context) {
+	var l request.DataRequest[loan.CreateRateChangeDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.LoanId = c.Param("id")
+
+	r, err := t.loanService.AddRateChange(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *LoanController) RecordPayment(c *gin.Context) {
+	var l request.DataRequest[loan.CreatePaymentDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.LoanId = c.Param("id")
+
+	r, err := t.loanService.RecordPayment(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "还款成功", r)
+}
+
+func (t *LoanController) RecordPrepayment(c *gin.Context) {
+	var l request.DataRequest[loan.CreatePrepaymentDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.LoanId = c.Param("id")
+
+	r, err := t.loanService.RecordPrepayment(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "还款成功", r)
+}
+
+func (t *LoanController) PreviewPrepayment(c *gin.Context) {
+	var l request.DataRequest[loan.CreatePrepaymentDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.LoanId = c.Param("id")
+
+	r, err := t.loanService.PreviewPrepayment(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "测算成功", r)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/notification"
+)
+
+type NotificationController struct {
+	notificationService notification.NotificationService
+}
+
+func NewNotificationController() *NotificationController {
+	return &NotificationController{
+		notificationService: notification.NewNotificationService(
+			repository.NewRepository[notification.Notification](app.AppContext.APP_DbContext.GetMasterDb()),
+			repository.NewRepository[notification.Subscription](app.AppContext.APP_DbContext.GetMasterDb()),
+		),
+	}
+}
+
+func (t *NotificationController) List(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.notificationService.ListNotifications(c, tenantId, c.Query("user_id"), c.Query("unread") == "true")
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *NotificationController) MarkRead(c *gin.Context) {
+	var l request.DataRequest[notification.MarkReadDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.notificationService.MarkRead(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "标记成功", r)
+}
+
+func (t *NotificationController) ListSubscriptions(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.notificationService.ListSubscriptions(c, tenantId, c.Query("user_id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *NotificationController) SaveSubscription(c *gin.Context) {
+	var l request.DataRequest[notification.SaveSubscriptionDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.notificationService.SaveSubscription(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "保存成功", r)
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/recurring"
+)
+
+type RecurringController struct {
+	recurringService recurring.RecurringService
+}
+
+func NewRecurringController() *RecurringController {
+	return &RecurringController{
+		recurringService: recurring.NewRecurringService(
+			repository.NewRepository[recurring.RecurringTemplate](app.AppContext.APP_DbContext.GetMasterDb()),
+			repository.NewRepository[recurring.RecurringOccurrence](app.AppContext.APP_DbContext.GetMasterDb()),
+		),
+	}
+}
+
+func (t *RecurringController) ListTemplates(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.recurringService.ListTemplates(c, tenantId)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *RecurringController) CreateTemplate(c *gin.Context) {
+	var l request.DataRequest[recurring.CreateTemplateDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.recurringService.CreateTemplate(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *RecurringController) UpdateTemplate(c *gin.Context) {
+	var l request.DataRequest[recurring.UpdateTemplateDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.recurringService.UpdateTemplate(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *RecurringController) PreviewTemplate(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+	count, _ := strconv.Atoi(c.Query("count"))
+
+	r, err := t.recurringService.PreviewTemplate(c, tenantId, c.Param("id"), count)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *RecurringController) PreviewSchedule(c *gin.Context) {
+	var l request.DataRequest[recurring.PreviewScheduleDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.recurringService.PreviewSchedule(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *RecurringController) ListOccurrences(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.recurringService.ListOccurrences(c, tenantId, c.Query("status"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *RecurringController) HandleOccurrence(c *gin.Context) {
+	var l request.DataRequest[recurring.HandleOccurrenceDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.recurringService.HandleOccurrence(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "处理成功", r)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/reimbursement"
+)
+
+type ReimbursementController struct {
+	reimbursementService reimbursement.ReimbursementService
+}
+
+func NewReimbursementController() *ReimbursementController {
+	return &ReimbursementController{
+		reimbursementService: newReimbursementService(),
+	}
+}
+
+func newReimbursementService() reimbursement.ReimbursementService {
+	return reimbursement.NewReimbursementService(
+		repository.NewRepository[reimbursement.Claim](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[reimbursement.Receipt](app.AppContext.APP_DbContext.GetMasterDb()),
+	)
+}
+
+func (t *ReimbursementController) List(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.reimbursementService.ListClaims(c, tenantId, c.Query("user_id"), c.Query("status"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *ReimbursementController) Get(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.reimbursementService.GetClaim(c, tenantId, c.Param("id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *ReimbursementController) Outstanding(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.reimbursementService.GetOutstanding(c, tenantId, c.Query("user_id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *ReimbursementController) Create(c *gin.Context) {
+	var l request.DataRequest[reimbursement.CreateClaimDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.reimbursementService.CreateClaim(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *ReimbursementController) Update(c *gin.Context) {
+	var l request.DataRequest[reimbursement.UpdateClaimDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.reimbursementService.UpdateClaim(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *ReimbursementController) Submit(c *gin.Context) {
+	var l request.DataRequest[reimbursement.SubmitClaimDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.reimbursementService.SubmitClaim(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "提交成功", r)
+}
+
+func (t *ReimbursementController) RecordReceipt(c *gin.Context) {
+	var l request.DataRequest[reimbursement.CreateReceiptDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.ClaimId = c.Param("id")
+
+	r, err := t.reimbursementService.RecordReceipt(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "保存成功", r)
+}
+
+func (t *ReimbursementController) Close(c *gin.Context) {
+	var l request.DataRequest[reimbursement.CloseClaimDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.reimbursementService.CloseClaim(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/tax"
+)
+
+type TaxController struct {
+	taxService tax.TaxService
+}
+
+func NewTaxController() *TaxController {
+	return &TaxController{
+		taxService: tax.NewTaxService(
+			repository.NewRepository[tax.DeductionRecord](app.AppContext.APP_DbContext.GetMasterDb()),
+			repository.NewRepository[tax.DeductionRule](app.AppContext.APP_DbContext.GetMasterDb()),
+			auth.NewMemberDirectory(repository.NewRepository[auth.User](app.AppContext.APP_DbContext.GetMasterDb())),
+		),
+	}
+}
+
+func (t *TaxController) ListRecords(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+	year, _ := strconv.Atoi(c.Query("year"))
+
+	r, err := t.taxService.ListRecords(c, tenantId, year, c.Query("user_id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *TaxController) CreateRecord(c *gin.Context) {
+	var l request.DataRequest[tax.CreateDeductionRecordDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.taxService.CreateRecord(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "创建成功", r)
+}
+
+func (t *TaxController) UpdateRecord(c *gin.Context) {
+	var l request.DataRequest[tax.UpdateDeductionRecordDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+	l.Data.Id = c.Param("id")
+
+	r, err := t.taxService.UpdateRecord(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "更新成功", r)
+}
+
+func (t *TaxController) ListRules(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+	year, _ := strconv.Atoi(c.Query("year"))
+
+	r, err := t.taxService.ListRules(c, tenantId, year)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *TaxController) SaveRule(c *gin.Context) {
+	var l request.DataRequest[tax.SaveDeductionRuleDTO]
+	if err := middleware.ValidateRequest(c, &l); err != nil {
+		response.BadRequest(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	r, err := t.taxService.SaveRule(c, &l)
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "保存成功", r)
+}
+
+func (t *TaxController) Summary(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+	year, _ := strconv.Atoi(c.Query("year"))
+
+	r, err := t.taxService.GetSummary(c, tenantId, year, c.Query("user_id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	response.Ok(c, "查询成功", r)
+}
+
+func (t *TaxController) ExportSummary(c *gin.Context) {
+	tenantId := c.Query("tenant_id")
+	if len(tenantId) == 0 {
+		response.BadRequest(c, errTenantIdRequired.Error(), map[string]interface{}{})
+		return
+	}
+	year, _ := strconv.Atoi(c.Query("year"))
+
+	format := c.DefaultQuery("format", "csv")
+	export, contentType := t.taxService.ExportSummaryCSV, "text/csv; charset=utf-8"
+	switch format {
+	case "csv":
+	case "pdf":
+		export, contentType = t.taxService.ExportSummaryPDF, "application/pdf"
+	default:
+		response.BadRequest(c, errExportFormatInvalid.Error(), map[string]interface{}{})
+		return
+	}
+
+	data, err := export(c, tenantId, year, c.Query("user_id"))
+	if err != nil {
+		response.Fail(c, err.Error(), map[string]interface{}{})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-deductions-%s.%s"`, c.DefaultQuery("year", "current"), format))
+	c.Data(http.StatusOK, contentType, data)
+}
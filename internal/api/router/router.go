@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/loongkirin/gdk/net/http/gin/middleware"
+	"github.com/loongkirin/gdk/oauth"
 	"github.com/loongkirin/gdk/telemetry"
 	"github.com/loongkirin/gdk/util"
 	"github.com/loongkirin/go-family-finance/internal/api/controller"
@@ -74,6 +75,21 @@ func (r *Router) InitRouter() {
 	}
 	v1 := r.engine.Group("/api/v1")
 	initAuthorityRouter(v1)
+	initPayeeRouter(v1)
+	initAttachmentRouter(v1)
+	initCurrencyRouter(v1)
+	initNotificationRouter(v1)
+	initRecurringRouter(v1)
+	initBillRouter(v1)
+	initGoalRouter(v1)
+	initLoanRouter(v1)
+	initIouRouter(v1)
+	initInvestmentRouter(v1)
+	initNetWorthRouter(v1)
+	initFixedAssetRouter(v1)
+	initReimbursementRouter(v1)
+	initInsuranceRouter(v1)
+	initTaxRouter(v1)
 }
 
 func initAuthorityRouter(router *gin.RouterGroup) (R gin.IRoutes) {
@@ -90,6 +106,213 @@ func initAuthorityRouter(router *gin.RouterGroup) (R gin.IRoutes) {
 	return authRouter
 }
 
+func initPayeeRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	payeeRouter := router.Group("payees")
+	payeeApi := controller.NewPayeeController()
+	payeeRouter.GET("", payeeApi.List)
+	payeeRouter.POST("", payeeApi.Create)
+	payeeRouter.POST("match", payeeApi.Match)
+	payeeRouter.GET(":id", payeeApi.Get)
+	payeeRouter.PUT(":id", payeeApi.Update)
+	return payeeRouter
+}
+
+func initAttachmentRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	attachmentRouter := router.Group("attachments")
+	attachmentRouter.Use(authRequired())
+	attachmentApi := controller.NewAttachmentController()
+	attachmentRouter.GET("", attachmentApi.List)
+	attachmentRouter.POST("", attachmentApi.Upload)
+	attachmentRouter.GET(":id/download", attachmentApi.Download)
+	attachmentRouter.GET(":id/thumbnail", attachmentApi.Thumbnail)
+	return attachmentRouter
+}
+
+func initCurrencyRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	currencyRouter := router.Group("currency")
+	currencyApi := controller.NewCurrencyController()
+	currencyRouter.GET("rates", currencyApi.ListRates)
+	currencyRouter.POST("rates", currencyApi.SaveRate)
+	currencyRouter.POST("rates/import", currencyApi.ImportRates)
+	currencyRouter.GET("settings", currencyApi.GetSetting)
+	currencyRouter.PUT("settings", currencyApi.SaveSetting)
+	currencyRouter.POST("convert", currencyApi.Convert)
+	currencyRouter.POST("revalue", currencyApi.Revalue)
+	return currencyRouter
+}
+
+func initNotificationRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	notificationRouter := router.Group("notifications")
+	notificationApi := controller.NewNotificationController()
+	notificationRouter.GET("", notificationApi.List)
+	notificationRouter.POST("read", notificationApi.MarkRead)
+	notificationRouter.GET("subscriptions", notificationApi.ListSubscriptions)
+	notificationRouter.PUT("subscriptions", notificationApi.SaveSubscription)
+	return notificationRouter
+}
+
+func initRecurringRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	recurringRouter := router.Group("recurring")
+	recurringApi := controller.NewRecurringController()
+	recurringRouter.GET("templates", recurringApi.ListTemplates)
+	recurringRouter.POST("templates", recurringApi.CreateTemplate)
+	recurringRouter.PUT("templates/:id", recurringApi.UpdateTemplate)
+	recurringRouter.GET("templates/:id/preview", recurringApi.PreviewTemplate)
+	recurringRouter.POST("preview", recurringApi.PreviewSchedule)
+	recurringRouter.GET("occurrences", recurringApi.ListOccurrences)
+	recurringRouter.POST("occurrences/:id/handle", recurringApi.HandleOccurrence)
+	return recurringRouter
+}
+
+func initBillRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	billRouter := router.Group("bills")
+	billApi := controller.NewBillController()
+	billRouter.GET("", billApi.List)
+	billRouter.POST("", billApi.Create)
+	billRouter.GET("upcoming", billApi.Upcoming)
+	billRouter.GET("overdue", billApi.Overdue)
+	billRouter.PUT(":id", billApi.Update)
+	billRouter.POST(":id/pay", billApi.Pay)
+	billRouter.POST("calendar-feeds", authRequired(), billApi.CreateCalendarFeed)
+	billRouter.GET("calendar/:feed", billApi.Calendar)
+	return billRouter
+}
+
+func initGoalRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	goalRouter := router.Group("goals")
+	goalApi := controller.NewGoalController()
+	goalRouter.GET("", goalApi.List)
+	goalRouter.POST("", goalApi.Create)
+	goalRouter.GET(":id", goalApi.Get)
+	goalRouter.PUT(":id", goalApi.Update)
+	goalRouter.POST(":id/contributions", goalApi.Contribute)
+	return goalRouter
+}
+
+func initLoanRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	loanRouter := router.Group("loans")
+	loanApi := controller.NewLoanController()
+	loanRouter.GET("", loanApi.List)
+	loanRouter.POST("", loanApi.Create)
+	loanRouter.GET(":id", loanApi.Get)
+	loanRouter.PUT(":id", loanApi.Update)
+	loanRouter.GET(":id/schedule", loanApi.Schedule)
+	loanRouter.POST(":id/rate-changes", loanApi.AddRateChange)
+	loanRouter.POST(":id/payments", loanApi.RecordPayment)
+	loanRouter.POST(":id/prepayments", loanApi.RecordPrepayment)
+	loanRouter.POST(":id/prepayments/preview", loanApi.PreviewPrepayment)
+	return loanRouter
+}
+
+func initIouRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	iouApi := controller.NewIouController()
+	iouRouter := router.Group("ious")
+	iouRouter.GET("", iouApi.List)
+	iouRouter.POST("", iouApi.Create)
+	iouRouter.GET("balances", iouApi.Balances)
+	iouRouter.POST(":id/repay", iouApi.Repay)
+
+	sharedRouter := router.Group("shared-expenses")
+	sharedRouter.GET("", iouApi.ListSharedExpenses)
+	sharedRouter.POST("", iouApi.CreateSharedExpense)
+	sharedRouter.GET("balances", iouApi.SharedBalances)
+	sharedRouter.GET("settlements", iouApi.ListSettlements)
+	sharedRouter.POST("settlements", iouApi.CreateSettlement)
+	return iouRouter
+}
+
+func initInvestmentRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	investmentRouter := router.Group("investments")
+	investmentApi := controller.NewInvestmentController()
+	investmentRouter.GET("portfolios", investmentApi.ListPortfolios)
+	investmentRouter.POST("portfolios", investmentApi.CreatePortfolio)
+	investmentRouter.PUT("portfolios/:id", investmentApi.UpdatePortfolio)
+	investmentRouter.GET("portfolios/:id/holdings", investmentApi.Holdings)
+	investmentRouter.GET("portfolios/:id/transactions", investmentApi.ListTransactions)
+	investmentRouter.POST("portfolios/:id/transactions", investmentApi.AddTransaction)
+	investmentRouter.GET("securities", investmentApi.ListSecurities)
+	investmentRouter.POST("securities", investmentApi.CreateSecurity)
+	investmentRouter.GET("securities/:id/prices", investmentApi.ListPrices)
+	investmentRouter.POST("prices", investmentApi.SavePrice)
+	investmentRouter.POST("prices/import", investmentApi.ImportPrices)
+	investmentRouter.GET("valuation", investmentApi.Valuation)
+	investmentRouter.GET("allocation", investmentApi.Allocation)
+	return investmentRouter
+}
+
+func initNetWorthRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	netWorthRouter := router.Group("networth")
+	netWorthApi := controller.NewNetWorthController()
+	netWorthRouter.GET("", netWorthApi.History)
+	netWorthRouter.GET("current", netWorthApi.Current)
+	netWorthRouter.POST("snapshots", netWorthApi.TakeSnapshot)
+	netWorthRouter.GET("settings", netWorthApi.GetSetting)
+	netWorthRouter.PUT("settings", netWorthApi.SaveSetting)
+	return netWorthRouter
+}
+
+func initFixedAssetRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	fixedAssetRouter := router.Group("fixed-assets")
+	fixedAssetApi := controller.NewFixedAssetController()
+	fixedAssetRouter.GET("", fixedAssetApi.List)
+	fixedAssetRouter.POST("", fixedAssetApi.Create)
+	fixedAssetRouter.GET(":id", fixedAssetApi.Get)
+	fixedAssetRouter.PUT(":id", fixedAssetApi.Update)
+	fixedAssetRouter.GET(":id/schedule", fixedAssetApi.Schedule)
+	fixedAssetRouter.POST(":id/valuations", fixedAssetApi.AddValuation)
+	fixedAssetRouter.POST(":id/dispose", fixedAssetApi.Dispose)
+	return fixedAssetRouter
+}
+
+func initReimbursementRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	reimbursementRouter := router.Group("reimbursements")
+	reimbursementApi := controller.NewReimbursementController()
+	reimbursementRouter.GET("", reimbursementApi.List)
+	reimbursementRouter.POST("", reimbursementApi.Create)
+	reimbursementRouter.GET("outstanding", reimbursementApi.Outstanding)
+	reimbursementRouter.GET(":id", reimbursementApi.Get)
+	reimbursementRouter.PUT(":id", reimbursementApi.Update)
+	reimbursementRouter.POST(":id/submit", reimbursementApi.Submit)
+	reimbursementRouter.POST(":id/receipts", reimbursementApi.RecordReceipt)
+	reimbursementRouter.POST(":id/close", reimbursementApi.Close)
+	return reimbursementRouter
+}
+
+func initInsuranceRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	insuranceRouter := router.Group("insurance")
+	insuranceApi := controller.NewInsuranceController()
+	insuranceRouter.GET("policies", insuranceApi.List)
+	insuranceRouter.POST("policies", insuranceApi.Create)
+	insuranceRouter.GET("policies/:id", insuranceApi.Get)
+	insuranceRouter.PUT("policies/:id", insuranceApi.Update)
+	insuranceRouter.POST("policies/:id/renew", insuranceApi.Renew)
+	insuranceRouter.POST("policies/:id/claims", insuranceApi.CreateClaim)
+	insuranceRouter.GET("summary", insuranceApi.Summary)
+	return insuranceRouter
+}
+
+func initTaxRouter(router *gin.RouterGroup) (R gin.IRoutes) {
+	taxRouter := router.Group("tax")
+	taxApi := controller.NewTaxController()
+	taxRouter.GET("deductions", taxApi.ListRecords)
+	taxRouter.POST("deductions", taxApi.CreateRecord)
+	taxRouter.PUT("deductions/:id", taxApi.UpdateRecord)
+	taxRouter.GET("rules", taxApi.ListRules)
+	taxRouter.PUT("rules", taxApi.SaveRule)
+	taxRouter.GET("summary", taxApi.Summary)
+	taxRouter.GET("summary/export", taxApi.ExportSummary)
+	return taxRouter
+}
+
+// authRequired 校验访问令牌，并把令牌声明写入上下文供控制器确定当前用户和租户
+func authRequired() gin.HandlerFunc {
+	oauthMaker, err := oauth.NewPasetoMaker(app.AppContext.APP_CONFIG.OAuthConfig)
+	if err != nil {
+		panic(err)
+	}
+	return middleware.OAuth(oauthMaker)
+}
+
 func (r *Router) Run(addr string) error {
 	return r.engine.Run(addr)
 }
@@ -7,8 +7,12 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/loongkirin/gdk/cache/redis"
 	gdkgorm "github.com/loongkirin/gdk/database/gorm"
+	"github.com/loongkirin/gdk/database/gorm/repository"
 	"github.com/loongkirin/gdk/logger"
 	"github.com/loongkirin/gdk/telemetry"
+	"github.com/loongkirin/go-family-finance/internal/blobstore"
+	"github.com/loongkirin/go-family-finance/internal/domain/notification"
+	"github.com/loongkirin/go-family-finance/internal/scheduler"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
@@ -24,6 +28,9 @@ type appContext struct {
 	APP_LOGGER                 logger.Logger
 	APP_TRACER                 trace.Tracer
 	APP_METRICS                metric.Meter
+	APP_BLOBSTORE              blobstore.BlobStore
+	APP_NOTIFIER               *notification.Dispatcher
+	APP_SCHEDULER              *scheduler.Scheduler
 }
 
 var AppContext appContext
@@ -38,6 +45,9 @@ func InitAppContext() {
 	AppContext.initMetrics()
 	AppContext.initRedis()
 	AppContext.initDbContext()
+	AppContext.initBlobStore()
+	AppContext.initNotifier()
+	AppContext.initScheduler()
 }
 
 func (ctx *appContext) initViper() {
@@ -109,3 +119,33 @@ func (ctx *appContext) initDbContext() {
 	dbContext := gdkgorm.CreateDbContext(&ctx.APP_CONFIG.DbConfig)
 	ctx.APP_DbContext = dbContext
 }
+
+func (ctx *appContext) initBlobStore() {
+	store, err := blobstore.NewBlobStore(&ctx.APP_CONFIG.BlobStoreConfig)
+	if err != nil {
+		panic(fmt.Errorf("fatal error when init blob store: %s", err))
+	}
+	ctx.APP_BLOBSTORE = store
+}
+
+func (ctx *appContext) initNotifier() {
+	dispatcher := notification.NewDispatcher(
+		repository.NewRepository[notification.Notification](ctx.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[notification.Subscription](ctx.APP_DbContext.GetMasterDb()),
+		ctx.APP_CONFIG.NotificationConfig,
+		ctx.APP_LOGGER,
+	)
+	dispatcher.Start()
+	ctx.APP_NOTIFIER = dispatcher
+}
+
+func (ctx *appContext) initScheduler() {
+	var locker scheduler.Locker
+	if ctx.APP_REDIS != nil {
+		locker = scheduler.NewRedisLocker(ctx.APP_REDIS.GetMasterDb())
+	} else {
+		fmt.Println("redis is not available, scheduler falls back to in-process lock")
+		locker = scheduler.NewLocalLocker()
+	}
+	ctx.APP_SCHEDULER = scheduler.NewScheduler(locker, ctx.APP_LOGGER)
+}
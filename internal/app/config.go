@@ -7,6 +7,9 @@ import (
 	"github.com/loongkirin/gdk/logger"
 	"github.com/loongkirin/gdk/oauth"
 	"github.com/loongkirin/gdk/telemetry"
+	"github.com/loongkirin/go-family-finance/internal/blobstore"
+	"github.com/loongkirin/go-family-finance/internal/domain/attachment"
+	"github.com/loongkirin/go-family-finance/internal/domain/notification"
 )
 
 type ServerConfig struct {
@@ -15,11 +18,14 @@ type ServerConfig struct {
 }
 
 type AppConfig struct {
-	CaptchaConfig   captcha.CaptchaConfig     `mapstructure:"captchaconfig" json:"captchaconfig" yaml:"captchaconfig"`
-	OAuthConfig     oauth.OAuthConfig         `mapstructure:"oauthconfig" json:"oauthconfig" yaml:"oauthconfig"`
-	RedisConfig     redis.RedisConfig         `mapstructure:"redisconfig" json:"redisconfig" yaml:"redisconfig"`
-	DbConfig        database.DbConfig         `mapstructure:"dbconfig" json:"dbconfig" yaml:"dbconfig"`
-	ServerConfig    ServerConfig              `mapstructure:"serverconfig" json:"serverconfig" yaml:"serverconfig"`
-	LoggerConfig    logger.LoggerConfig       `mapstructure:"loggerconfig" json:"loggerconfig" yaml:"loggerconfig"`
-	TelemetryConfig telemetry.TelemetryConfig `mapstructure:"telemetryconfig" json:"telemetryconfig" yaml:"telemetryconfig"`
+	CaptchaConfig      captcha.CaptchaConfig           `mapstructure:"captchaconfig" json:"captchaconfig" yaml:"captchaconfig"`
+	OAuthConfig        oauth.OAuthConfig               `mapstructure:"oauthconfig" json:"oauthconfig" yaml:"oauthconfig"`
+	RedisConfig        redis.RedisConfig               `mapstructure:"redisconfig" json:"redisconfig" yaml:"redisconfig"`
+	DbConfig           database.DbConfig               `mapstructure:"dbconfig" json:"dbconfig" yaml:"dbconfig"`
+	ServerConfig       ServerConfig                    `mapstructure:"serverconfig" json:"serverconfig" yaml:"serverconfig"`
+	LoggerConfig       logger.LoggerConfig             `mapstructure:"loggerconfig" json:"loggerconfig" yaml:"loggerconfig"`
+	TelemetryConfig    telemetry.TelemetryConfig       `mapstructure:"telemetryconfig" json:"telemetryconfig" yaml:"telemetryconfig"`
+	BlobStoreConfig    blobstore.BlobStoreConfig       `mapstructure:"blobstoreconfig" json:"blobstoreconfig" yaml:"blobstoreconfig"`
+	AttachmentConfig   attachment.AttachmentConfig     `mapstructure:"attachmentconfig" json:"attachmentconfig" yaml:"attachmentconfig"`
+	NotificationConfig notification.NotificationConfig `mapstructure:"notificationconfig" json:"notificationconfig" yaml:"notificationconfig"`
 }
@@ -0,0 +1,57 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	StoreTypeLocal = "local"
+	StoreTypeS3    = "s3"
+)
+
+var (
+	ErrBlobNotFound     = errors.New("文件不存在")
+	ErrBlobKeyInvalid   = errors.New("文件路径无效")
+	ErrStoreTypeInvalid = errors.New("不支持的存储类型")
+)
+
+// BlobStore 文件存储接口，key 为以 / 分隔的相对路径
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type LocalConfig struct {
+	RootDir string `mapstructure:"root_dir" json:"root_dir" yaml:"root_dir"`
+}
+
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint" json:"endpoint" yaml:"endpoint"`
+	Region    string `mapstructure:"region" json:"region" yaml:"region"`
+	Bucket    string `mapstructure:"bucket" json:"bucket" yaml:"bucket"`
+	AccessKey string `mapstructure:"access_key" json:"access_key" yaml:"access_key"`
+	SecretKey string `mapstructure:"secret_key" json:"secret_key" yaml:"secret_key"`
+	UseSSL    bool   `mapstructure:"use_ssl" json:"use_ssl" yaml:"use_ssl"`
+}
+
+type BlobStoreConfig struct {
+	StoreType string      `mapstructure:"store_type" json:"store_type" yaml:"store_type"`
+	Local     LocalConfig `mapstructure:"local" json:"local" yaml:"local"`
+	S3        S3Config    `mapstructure:"s3" json:"s3" yaml:"s3"`
+}
+
+// NewBlobStore 根据配置创建文件存储
+func NewBlobStore(cfg *BlobStoreConfig) (BlobStore, error) {
+	switch cfg.StoreType {
+	case StoreTypeLocal, "":
+		return NewLocalStore(cfg.Local)
+	case StoreTypeS3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrStoreTypeInvalid, cfg.StoreType)
+	}
+}
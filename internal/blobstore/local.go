@@ -0,0 +1,72 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStore struct {
+	rootDir string
+}
+
+// NewLocalStore 创建本地文件系统存储
+func NewLocalStore(cfg LocalConfig) (BlobStore, error) {
+	rootDir := cfg.RootDir
+	if len(rootDir) == 0 {
+		rootDir = "data/blobs"
+	}
+	if err := os.MkdirAll(rootDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &localStore{rootDir: rootDir}, nil
+}
+
+func (s *localStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return f, err
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStore) path(key string) (string, error) {
+	if len(key) == 0 || strings.HasPrefix(key, "/") || !fs.ValidPath(key) {
+		return "", ErrBlobKeyInvalid
+	}
+	return filepath.Join(s.rootDir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,27 @@
+package attachment
+
+type AttachmentConfig struct {
+	MaxSize       int64    `mapstructure:"max_size" json:"max_size" yaml:"max_size"`
+	AllowedTypes  []string `mapstructure:"allowed_types" json:"allowed_types" yaml:"allowed_types"`
+	ThumbnailSize int      `mapstructure:"thumbnail_size" json:"thumbnail_size" yaml:"thumbnail_size"`
+}
+
+const (
+	defaultMaxSize       = 10 << 20
+	defaultThumbnailSize = 256
+)
+
+var defaultAllowedTypes = []string{"image/jpeg", "image/png", "image/gif", "application/pdf"}
+
+func (cfg AttachmentConfig) withDefaults() AttachmentConfig {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = defaultMaxSize
+	}
+	if len(cfg.AllowedTypes) == 0 {
+		cfg.AllowedTypes = defaultAllowedTypes
+	}
+	if cfg.ThumbnailSize <= 0 {
+		cfg.ThumbnailSize = defaultThumbnailSize
+	}
+	return cfg
+}
@@ -0,0 +1,31 @@
+package attachment
+
+import "io"
+
+type AttachmentDTO struct {
+	Id           string `json:"id"`
+	TenantId     string `json:"tenant_id"`
+	OwnerType    string `json:"owner_type"`
+	OwnerId      string `json:"owner_id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	HasThumbnail bool   `json:"has_thumbnail"`
+	UploadedBy   string `json:"uploaded_by"`
+}
+
+type UploadAttachmentDTO struct {
+	TenantId   string `form:"-"`
+	OwnerType  string `form:"owner_type" binding:"required"`
+	OwnerId    string `form:"owner_id" binding:"required"`
+	UploadedBy string `form:"-"`
+	FileName   string `form:"-"`
+	Data       []byte `form:"-"`
+}
+
+type DownloadDTO struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Reader      io.ReadCloser
+}
@@ -0,0 +1,21 @@
+package attachment
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+type Attachment struct {
+	model.TenantBaseModel
+	OwnerType    string `json:"owner_type" gorm:"size:50;index:idx_attachment_owner"`
+	OwnerId      string `json:"owner_id" gorm:"size:32;index:idx_attachment_owner"`
+	FileName     string `json:"file_name" gorm:"size:255;not null"`
+	ContentType  string `json:"content_type" gorm:"size:100;not null"`
+	Size         int64  `json:"size"`
+	StorageKey   string `json:"storage_key" gorm:"size:255;not null"`
+	ThumbnailKey string `json:"thumbnail_key" gorm:"size:255"`
+	UploadedBy   string `json:"uploaded_by" gorm:"size:32"`
+}
+
+func (entity *Attachment) TableName() string {
+	return "finance_attachment"
+}
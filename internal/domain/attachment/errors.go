@@ -0,0 +1,13 @@
+package attachment
+
+import "errors"
+
+var (
+	ErrAttachmentNotFound    = errors.New("附件不存在")
+	ErrFileRequired          = errors.New("请选择要上传的文件")
+	ErrFileTooLarge          = errors.New("文件大小超出限制")
+	ErrContentTypeNotAllowed = errors.New("不支持的文件类型")
+	ErrOwnerRequired         = errors.New("附件所属对象不能为空")
+	ErrThumbnailNotFound     = errors.New("缩略图不存在")
+	ErrImageTooLarge         = errors.New("图片尺寸过大，不生成缩略图")
+)
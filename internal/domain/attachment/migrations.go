@@ -0,0 +1,17 @@
+package attachment
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建附件表
+	if err := db.AutoMigrate(&Attachment{}); err != nil {
+		fmt.Println("创建附件表失败", err)
+	}
+
+	fmt.Println("Attachment模块迁移完成")
+}
@@ -0,0 +1,232 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+	"github.com/loongkirin/go-family-finance/internal/blobstore"
+)
+
+type AttachmentService interface {
+	Upload(ctx context.Context, req *UploadAttachmentDTO) (*response.DataResponse[AttachmentDTO], error)
+	ListAttachments(ctx context.Context, tenantId string, ownerType string, ownerId string) (*response.DataResponse[[]AttachmentDTO], error)
+	Download(ctx context.Context, tenantId string, id string) (*DownloadDTO, error)
+	DownloadThumbnail(ctx context.Context, tenantId string, id string) (*DownloadDTO, error)
+	MaxSize() int64
+}
+
+type service struct {
+	attachmentRepo repository.Repository[Attachment]
+	store          blobstore.BlobStore
+	cfg            AttachmentConfig
+}
+
+func NewAttachmentService(
+	attachmentRepo repository.Repository[Attachment],
+	store blobstore.BlobStore,
+	cfg AttachmentConfig,
+) AttachmentService {
+	return &service{
+		attachmentRepo: attachmentRepo,
+		store:          store,
+		cfg:            cfg.withDefaults(),
+	}
+}
+
+var fileExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+func (s *service) MaxSize() int64 {
+	return s.cfg.MaxSize
+}
+
+func (s *service) Upload(ctx context.Context, req *UploadAttachmentDTO) (*response.DataResponse[AttachmentDTO], error) {
+	if len(req.OwnerType) == 0 || len(req.OwnerId) == 0 {
+		return nil, ErrOwnerRequired
+	}
+	if len(req.Data) == 0 {
+		return nil, ErrFileRequired
+	}
+	if int64(len(req.Data)) > s.cfg.MaxSize {
+		return nil, ErrFileTooLarge
+	}
+	// 以文件内容识别类型，不信任客户端上报的 Content-Type
+	contentType, _, _ := strings.Cut(http.DetectContentType(req.Data), ";")
+	if !slices.Contains(s.cfg.AllowedTypes, contentType) {
+		return nil, ErrContentTypeNotAllowed
+	}
+
+	id := util.GenerateId()
+	prefix := fmt.Sprintf("%s/%s/%s", req.TenantId, time.Now().Format("200601"), id)
+	attachment := &Attachment{
+		OwnerType:       req.OwnerType,
+		OwnerId:         req.OwnerId,
+		FileName:        sanitizeFileName(req.FileName, contentType),
+		ContentType:     contentType,
+		Size:            int64(len(req.Data)),
+		StorageKey:      prefix + fileExtensions[contentType],
+		UploadedBy:      req.UploadedBy,
+		TenantBaseModel: model.NewTenantBaseModel(req.TenantId, id),
+	}
+
+	if err := s.store.Put(ctx, attachment.StorageKey, req.Data, contentType); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(contentType, "image/") {
+		// 缩略图生成失败不影响原文件上传
+		if thumb, err := makeThumbnail(req.Data, s.cfg.ThumbnailSize); err == nil {
+			thumbKey := prefix + "_thumb.jpg"
+			if err := s.store.Put(ctx, thumbKey, thumb, "image/jpeg"); err == nil {
+				attachment.ThumbnailKey = thumbKey
+			}
+		}
+	}
+
+	attachment, err := s.attachmentRepo.Add(ctx, attachment)
+	if err != nil {
+		s.removeBlobs(ctx, prefix+fileExtensions[contentType], prefix+"_thumb.jpg")
+		return nil, err
+	}
+
+	return &response.DataResponse[AttachmentDTO]{
+		Data: toAttachmentDTO(attachment),
+	}, nil
+}
+
+func (s *service) ListAttachments(ctx context.Context, tenantId string, ownerType string, ownerId string) (*response.DataResponse[[]AttachmentDTO], error) {
+	if len(ownerType) == 0 || len(ownerId) == 0 {
+		return nil, ErrOwnerRequired
+	}
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("owner_type", []interface{}{ownerType}, query.EQ, "String"),
+		query.NewDbQueryFilter("owner_id", []interface{}{ownerId}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    100,
+		PageNumber:  1,
+	}
+	attachments, err := s.attachmentRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]AttachmentDTO, 0, len(attachments))
+	for i := range attachments {
+		dtos = append(dtos, toAttachmentDTO(&attachments[i]))
+	}
+	return &response.DataResponse[[]AttachmentDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) Download(ctx context.Context, tenantId string, id string) (*DownloadDTO, error) {
+	attachment, err := s.findAttachmentById(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadDTO{
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Reader:      reader,
+	}, nil
+}
+
+func (s *service) DownloadThumbnail(ctx context.Context, tenantId string, id string) (*DownloadDTO, error) {
+	attachment, err := s.findAttachmentById(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachment.ThumbnailKey) == 0 {
+		return nil, ErrThumbnailNotFound
+	}
+	reader, err := s.store.Get(ctx, attachment.ThumbnailKey)
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadDTO{
+		FileName:    strings.TrimSuffix(attachment.FileName, filepath.Ext(attachment.FileName)) + "_thumb.jpg",
+		ContentType: "image/jpeg",
+		Size:        -1,
+		Reader:      reader,
+	}, nil
+}
+
+// findAttachmentById 按租户查询附件，其他租户的附件视为不存在
+func (s *service) findAttachmentById(ctx context.Context, tenantId string, id string) (*Attachment, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	attachments, err := s.attachmentRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, ErrAttachmentNotFound
+	}
+	return &attachments[0], nil
+}
+
+func (s *service) removeBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			fmt.Println("删除附件文件失败", key, err)
+		}
+	}
+}
+
+func sanitizeFileName(name string, contentType string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if len(name) == 0 || name == "." || name == "/" {
+		name = "attachment" + fileExtensions[contentType]
+	}
+	if len(name) > 255 {
+		ext := filepath.Ext(name)
+		name = strings.ToValidUTF8(name[:255-len(ext)], "") + ext
+	}
+	return name
+}
+
+func toAttachmentDTO(attachment *Attachment) AttachmentDTO {
+	return AttachmentDTO{
+		Id:           attachment.Id,
+		TenantId:     attachment.TenantId,
+		OwnerType:    attachment.OwnerType,
+		OwnerId:      attachment.OwnerId,
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		HasThumbnail: len(attachment.ThumbnailKey) > 0,
+		UploadedBy:   attachment.UploadedBy,
+	}
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+)
+
+const memberPageSize = 500
+
+// MemberDirectory 查询家庭（租户）成员，供其他模块确定通知对象等
+type MemberDirectory interface {
+	TenantUserIds(ctx context.Context, tenantId string) ([]string, error)
+	// TenantIds 返回有已激活用户的全部租户 ID，供后台任务遍历
+	TenantIds(ctx context.Context) ([]string, error)
+	// MemberNames 返回租户下用户 ID 到姓名的映射，包括未激活的用户
+	MemberNames(ctx context.Context, tenantId string) (map[string]string, error)
+	// TenantOf 返回已激活用户所属的租户 ID，用于从登录凭证确定当前家庭
+	TenantOf(ctx context.Context, userId string) (string, error)
+}
+
+type memberDirectory struct {
+	userRepo repository.Repository[User]
+}
+
+func NewMemberDirectory(userRepo repository.Repository[User]) MemberDirectory {
+	return &memberDirectory{
+		userRepo: userRepo,
+	}
+}
+
+// TenantUserIds 返回租户下所有已激活用户的 ID
+func (d *memberDirectory) TenantUserIds(ctx context.Context, tenantId string) ([]string, error) {
+	ids := []string{}
+	err := d.eachUser(ctx, tenantId, func(user *User) {
+		if user.Active {
+			ids = append(ids, user.Id)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (d *memberDirectory) MemberNames(ctx context.Context, tenantId string) (map[string]string, error) {
+	names := map[string]string{}
+	err := d.eachUser(ctx, tenantId, func(user *User) {
+		names[user.Id] = user.Name
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func (d *memberDirectory) TenantIds(ctx context.Context) ([]string, error) {
+	seen := map[string]bool{}
+	ids := []string{}
+	err := d.eachUser(ctx, "", func(user *User) {
+		if user.Active && !seen[user.TenantId] {
+			seen[user.TenantId] = true
+			ids = append(ids, user.TenantId)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (d *memberDirectory) TenantOf(ctx context.Context, userId string) (string, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("id", []interface{}{userId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	users, err := d.userRepo.Query(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	if len(users) == 0 {
+		return "", ErrUserNotFound
+	}
+	if !users[0].Active {
+		return "", ErrUserNotActive
+	}
+	return users[0].TenantId, nil
+}
+
+// eachUser 分页遍历租户下的用户，tenantId 为空时遍历全部租户
+func (d *memberDirectory) eachUser(ctx context.Context, tenantId string, fn func(user *User)) error {
+	wheres := []query.DbQueryWhere{}
+	if len(tenantId) > 0 {
+		filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+		wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	}
+	for page := 1; ; page++ {
+		query := &query.DbQuery{
+			QueryWheres: wheres,
+			OrderBys:    []query.DbQueryOrderBy{query.NewDbQueryOrderBy("id", false)},
+			PageSize:    memberPageSize,
+			PageNumber:  page,
+		}
+		users, err := d.userRepo.Query(ctx, query)
+		if err != nil {
+			return err
+		}
+		for i := range users {
+			fn(&users[i])
+		}
+		if len(users) < memberPageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package bill
+
+type BillDTO struct {
+	Id               string   `json:"id"`
+	TenantId         string   `json:"tenant_id"`
+	Name             string   `json:"name"`
+	Category         string   `json:"category"`
+	PayeeId          string   `json:"payee_id"`
+	ExpectedAmount   int64    `json:"expected_amount"`
+	Currency         string   `json:"currency"`
+	DueDate          string   `json:"due_date"`
+	Status           string   `json:"status"`
+	PaidAmount       int64    `json:"paid_amount"`
+	PaidDate         string   `json:"paid_date"`
+	Memo             string   `json:"memo"`
+	RemindDaysBefore int      `json:"remind_days_before"`
+	NotifyUserIds    []string `json:"notify_user_ids"`
+	SourceType       string   `json:"source_type"`
+	SourceId         string   `json:"source_id"`
+	Overdue          bool     `json:"overdue"`
+	DaysUntilDue     int      `json:"days_until_due"`
+}
+
+type CreateBillDTO struct {
+	TenantId         string   `json:"tenant_id" binding:"required"`
+	Name             string   `json:"name" binding:"required,max_len=200"`
+	Category         string   `json:"category" binding:"required,oneof=credit_card property_fee utility insurance other"`
+	PayeeId          string   `json:"payee_id" binding:"omitempty"`
+	ExpectedAmount   int64    `json:"expected_amount" binding:"omitempty,min=0"`
+	Currency         string   `json:"currency" binding:"omitempty,len=3"`
+	DueDate          string   `json:"due_date" binding:"required"`
+	Memo             string   `json:"memo" binding:"omitempty,max_len=500"`
+	RemindDaysBefore int      `json:"remind_days_before" binding:"omitempty,min=0,max=60"`
+	NotifyUserIds    []string `json:"notify_user_ids" binding:"omitempty"`
+	// 同一来源同一到期日只生成一张账单
+	SourceType string `json:"source_type" binding:"omitempty,max_len=50"`
+	SourceId   string `json:"source_id" binding:"omitempty"`
+}
+
+type UpdateBillDTO struct {
+	TenantId         string   `json:"tenant_id" binding:"required"`
+	Id               string   `json:"id"`
+	Name             string   `json:"name" binding:"omitempty,max_len=200"`
+	ExpectedAmount   int64    `json:"expected_amount" binding:"omitempty,min=0"`
+	DueDate          string   `json:"due_date" binding:"omitempty"`
+	Memo             string   `json:"memo" binding:"omitempty,max_len=500"`
+	RemindDaysBefore *int     `json:"remind_days_before" binding:"omitempty,min=0,max=60"`
+	NotifyUserIds    []string `json:"notify_user_ids" binding:"omitempty"`
+}
+
+type PayBillDTO struct {
+	TenantId   string `json:"tenant_id" binding:"required"`
+	Id         string `json:"id"`
+	PaidAmount int64  `json:"paid_amount" binding:"omitempty,min=0"`
+	// 为空时为今天
+	PaidDate string `json:"paid_date" binding:"omitempty"`
+}
+
+// CreateCalendarFeedDTO 租户和用户取自登录凭证，不接受请求参数
+type CreateCalendarFeedDTO struct {
+	TenantId string `json:"-"`
+	UserId   string `json:"-"`
+}
+
+type CalendarFeedDTO struct {
+	UserId string `json:"user_id"`
+	Token  string `json:"token"`
+	Path   string `json:"path"`
+}
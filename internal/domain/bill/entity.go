@@ -0,0 +1,56 @@
+package bill
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	BillCreditCard  = "credit_card"
+	BillPropertyFee = "property_fee"
+	BillUtility     = "utility"
+	BillInsurance   = "insurance"
+	BillOther       = "other"
+
+	BillStatusUnpaid = "unpaid"
+	BillStatusPaid   = "paid"
+
+	DateLayout = "2006-01-02"
+)
+
+type Bill struct {
+	model.TenantBaseModel
+	Name           string `json:"name" gorm:"size:200;not null"`
+	Category       string `json:"category" gorm:"size:30;not null"`
+	PayeeId        string `json:"payee_id" gorm:"size:32"`
+	ExpectedAmount int64  `json:"expected_amount"`
+	Currency       string `json:"currency" gorm:"size:3"`
+	DueDate        string `json:"due_date" gorm:"size:10;not null;index"`
+	Status         string `json:"status" gorm:"size:20;not null;index"`
+	PaidAmount     int64  `json:"paid_amount"`
+	PaidDate       string `json:"paid_date" gorm:"size:10"`
+	Memo           string `json:"memo" gorm:"size:500"`
+	// 提前几天提醒，0 表示到期当天提醒
+	RemindDaysBefore int      `json:"remind_days_before"`
+	NotifyUserIds    []string `json:"notify_user_ids" gorm:"type:text;serializer:json"`
+	// 已发送提醒的日期，避免重复提醒
+	RemindedDate  string `json:"reminded_date" gorm:"size:10"`
+	OverdueNotice bool   `json:"overdue_notice" gorm:"default:false"`
+	// 自动生成账单的来源，如 insurance_policy 及其 ID
+	SourceType string `json:"source_type" gorm:"size:50"`
+	SourceId   string `json:"source_id" gorm:"size:32"`
+}
+
+func (entity *Bill) TableName() string {
+	return "finance_bill"
+}
+
+// CalendarFeed 用户订阅账单日历的私密链接
+type CalendarFeed struct {
+	model.TenantBaseModel
+	UserId string `json:"user_id" gorm:"size:32;not null;index"`
+	Token  string `json:"token" gorm:"size:64;not null;uniqueIndex"`
+}
+
+func (entity *CalendarFeed) TableName() string {
+	return "finance_bill_calendar_feed"
+}
@@ -0,0 +1,13 @@
+package bill
+
+import "errors"
+
+var (
+	ErrBillNotFound         = errors.New("账单不存在")
+	ErrBillAlreadyPaid      = errors.New("账单已支付")
+	ErrDueDateInvalid       = errors.New("到期日格式无效，应为YYYY-MM-DD")
+	ErrPaidDateInvalid      = errors.New("支付日期格式无效，应为YYYY-MM-DD")
+	ErrCalendarFeedNotFound = errors.New("日历订阅不存在")
+	ErrUserNotMember        = errors.New("用户不是该家庭的成员")
+	ErrSourceIncomplete     = errors.New("账单来源类型和ID需同时填写")
+)
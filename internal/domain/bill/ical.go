@@ -0,0 +1,94 @@
+package bill
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// renderCalendar 将账单输出为 iCalendar（RFC 5545），每张账单为到期日当天的全天事件
+func renderCalendar(bills []Bill, now time.Time) []byte {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//go-family-finance//bills//CN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText("家庭账单"))
+	writeLine(&b, "X-WR-TIMEZONE:Asia/Shanghai")
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, bill := range bills {
+		due, err := time.Parse(DateLayout, bill.DueDate)
+		if err != nil {
+			continue
+		}
+		summary := bill.Name
+		if bill.Status == BillStatusPaid {
+			summary = "[已付] " + summary
+		}
+		description := fmt.Sprintf("应付金额: %s %s", formatAmount(bill.ExpectedAmount), bill.Currency)
+		if len(bill.Memo) > 0 {
+			description += "\n" + bill.Memo
+		}
+
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+bill.Id+"@go-family-finance")
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, "DTSTART;VALUE=DATE:"+due.Format("20060102"))
+		writeLine(&b, "DTEND;VALUE=DATE:"+due.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(&b, "SUMMARY:"+escapeText(summary))
+		writeLine(&b, "DESCRIPTION:"+escapeText(description))
+		writeLine(&b, "CATEGORIES:"+escapeText(bill.Category))
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		if bill.Status == BillStatusUnpaid {
+			// 在提醒当天上午 9 点弹出
+			writeLine(&b, "BEGIN:VALARM")
+			writeLine(&b, "ACTION:DISPLAY")
+			writeLine(&b, "DESCRIPTION:"+escapeText(bill.Name+" 即将到期"))
+			if bill.RemindDaysBefore > 0 {
+				writeLine(&b, fmt.Sprintf("TRIGGER:-PT%dH", bill.RemindDaysBefore*24-9))
+			} else {
+				writeLine(&b, "TRIGGER:PT9H")
+			}
+			writeLine(&b, "END:VALARM")
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// writeLine 写入一行并按 75 字节折行，不拆分 UTF-8 字符
+func writeLine(b *strings.Builder, line string) {
+	// 续行以空格开头，占用一个字节
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line + "\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+	).Replace(s)
+}
+
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%.2f", float64(amount)/100)
+}
@@ -0,0 +1,26 @@
+package bill
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建账单表
+	if err := db.AutoMigrate(&Bill{}); err != nil {
+		fmt.Println("创建账单表失败", err)
+	}
+	// 同一来源同一到期日只保留一张自动生成的账单
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_bill_source_due ON finance_bill (tenant_id, source_type, source_id, due_date) WHERE source_id <> ''").Error; err != nil {
+		fmt.Println("创建账单来源唯一索引失败", err)
+	}
+
+	// 创建账单日历订阅表
+	if err := db.AutoMigrate(&CalendarFeed{}); err != nil {
+		fmt.Println("创建账单日历订阅表失败", err)
+	}
+
+	fmt.Println("Bill模块迁移完成")
+}
@@ -0,0 +1,509 @@
+package bill
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/notification"
+)
+
+const (
+	NotificationCategory = "bill_reminder"
+
+	CalendarPathPrefix = "/api/v1/bills/calendar/"
+
+	// 日历中保留已支付账单的天数
+	calendarHistoryDays = 90
+
+	billPageSize = 500
+)
+
+type BillService interface {
+	CreateBill(ctx context.Context, req *request.DataRequest[CreateBillDTO]) (*response.DataResponse[BillDTO], error)
+	UpdateBill(ctx context.Context, req *request.DataRequest[UpdateBillDTO]) (*response.DataResponse[BillDTO], error)
+	PayBill(ctx context.Context, req *request.DataRequest[PayBillDTO]) (*response.DataResponse[BillDTO], error)
+	ListBills(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]BillDTO], error)
+	ListUpcoming(ctx context.Context, tenantId string, days int) (*response.DataResponse[[]BillDTO], error)
+	ListOverdue(ctx context.Context, tenantId string) (*response.DataResponse[[]BillDTO], error)
+	CreateCalendarFeed(ctx context.Context, req *request.DataRequest[CreateCalendarFeedDTO]) (*response.DataResponse[CalendarFeedDTO], error)
+	RenderCalendar(ctx context.Context, token string) ([]byte, error)
+	// SendReminders 发送到期提醒和逾期通知，可重复执行
+	SendReminders(ctx context.Context, asOf time.Time) (int, error)
+}
+
+type service struct {
+	billRepo repository.Repository[Bill]
+	feedRepo repository.Repository[CalendarFeed]
+	notifier notification.Notifier
+	members  auth.MemberDirectory
+}
+
+func NewBillService(
+	billRepo repository.Repository[Bill],
+	feedRepo repository.Repository[CalendarFeed],
+	notifier notification.Notifier,
+	members auth.MemberDirectory,
+) BillService {
+	return &service{
+		billRepo: billRepo,
+		feedRepo: feedRepo,
+		notifier: notifier,
+		members:  members,
+	}
+}
+
+// CreateBill 创建账单，指定来源时同一来源同一到期日的账单已存在则直接返回
+func (s *service) CreateBill(ctx context.Context, req *request.DataRequest[CreateBillDTO]) (*response.DataResponse[BillDTO], error) {
+	if !validDate(req.Data.DueDate) {
+		return nil, ErrDueDateInvalid
+	}
+	if (len(req.Data.SourceType) == 0) != (len(req.Data.SourceId) == 0) {
+		return nil, ErrSourceIncomplete
+	}
+	if len(req.Data.SourceId) > 0 {
+		existing, err := s.findSourceBill(ctx, req.Data.TenantId, req.Data.SourceType, req.Data.SourceId, req.Data.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return &response.DataResponse[BillDTO]{
+				Data: toBillDTO(existing, today()),
+			}, nil
+		}
+	}
+	bill := &Bill{
+		Name:             strings.TrimSpace(req.Data.Name),
+		Category:         req.Data.Category,
+		PayeeId:          req.Data.PayeeId,
+		ExpectedAmount:   req.Data.ExpectedAmount,
+		Currency:         strings.ToUpper(req.Data.Currency),
+		DueDate:          req.Data.DueDate,
+		Status:           BillStatusUnpaid,
+		Memo:             req.Data.Memo,
+		RemindDaysBefore: req.Data.RemindDaysBefore,
+		NotifyUserIds:    req.Data.NotifyUserIds,
+		SourceType:       req.Data.SourceType,
+		SourceId:         req.Data.SourceId,
+		TenantBaseModel:  model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+
+	bill, err := s.billRepo.Add(ctx, bill)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[BillDTO]{
+		Data: toBillDTO(bill, today()),
+	}, nil
+}
+
+func (s *service) UpdateBill(ctx context.Context, req *request.DataRequest[UpdateBillDTO]) (*response.DataResponse[BillDTO], error) {
+	bill, err := s.findBillById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		bill.Name = name
+	}
+	if req.Data.ExpectedAmount > 0 {
+		bill.ExpectedAmount = req.Data.ExpectedAmount
+	}
+	if len(req.Data.Memo) > 0 {
+		bill.Memo = req.Data.Memo
+	}
+	if req.Data.NotifyUserIds != nil {
+		bill.NotifyUserIds = req.Data.NotifyUserIds
+	}
+	if req.Data.RemindDaysBefore != nil {
+		bill.RemindDaysBefore = *req.Data.RemindDaysBefore
+		bill.RemindedDate = ""
+	}
+	if len(req.Data.DueDate) > 0 && req.Data.DueDate != bill.DueDate {
+		if !validDate(req.Data.DueDate) {
+			return nil, ErrDueDateInvalid
+		}
+		// 到期日变更后重新提醒
+		bill.DueDate = req.Data.DueDate
+		bill.RemindedDate = ""
+		bill.OverdueNotice = false
+	}
+
+	bill, err = s.billRepo.Update(ctx, bill)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[BillDTO]{
+		Data: toBillDTO(bill, today()),
+	}, nil
+}
+
+func (s *service) PayBill(ctx context.Context, req *request.DataRequest[PayBillDTO]) (*response.DataResponse[BillDTO], error) {
+	bill, err := s.findBillById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if bill.Status == BillStatusPaid {
+		return nil, ErrBillAlreadyPaid
+	}
+	paidDate := req.Data.PaidDate
+	if len(paidDate) == 0 {
+		paidDate = today().Format(DateLayout)
+	} else if !validDate(paidDate) {
+		return nil, ErrPaidDateInvalid
+	}
+
+	bill.Status = BillStatusPaid
+	bill.PaidDate = paidDate
+	bill.PaidAmount = req.Data.PaidAmount
+	if bill.PaidAmount == 0 {
+		bill.PaidAmount = bill.ExpectedAmount
+	}
+
+	bill, err = s.billRepo.Update(ctx, bill)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[BillDTO]{
+		Data: toBillDTO(bill, today()),
+	}, nil
+}
+
+func (s *service) ListBills(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]BillDTO], error) {
+	return s.listBills(ctx, tenantId, func(bill *Bill, now time.Time) bool {
+		return len(status) == 0 || bill.Status == status
+	})
+}
+
+func (s *service) ListUpcoming(ctx context.Context, tenantId string, days int) (*response.DataResponse[[]BillDTO], error) {
+	if days <= 0 {
+		days = 30
+	}
+	return s.listBills(ctx, tenantId, func(bill *Bill, now time.Time) bool {
+		due := daysUntil(bill.DueDate, now)
+		return bill.Status == BillStatusUnpaid && due >= 0 && due <= days
+	})
+}
+
+func (s *service) ListOverdue(ctx context.Context, tenantId string) (*response.DataResponse[[]BillDTO], error) {
+	return s.listBills(ctx, tenantId, func(bill *Bill, now time.Time) bool {
+		return bill.Status == BillStatusUnpaid && daysUntil(bill.DueDate, now) < 0
+	})
+}
+
+// CreateCalendarFeed 创建或重置用户的日历订阅链接，重置后旧链接失效
+func (s *service) CreateCalendarFeed(ctx context.Context, req *request.DataRequest[CreateCalendarFeedDTO]) (*response.DataResponse[CalendarFeedDTO], error) {
+	userIds, err := s.members.TenantUserIds(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(userIds, req.Data.UserId) {
+		return nil, ErrUserNotMember
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	feeds, err := s.findFeeds(ctx, "user_id", req.Data.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var feed *CalendarFeed
+	for i := range feeds {
+		if feeds[i].TenantId == req.Data.TenantId {
+			feed = &feeds[i]
+			break
+		}
+	}
+	if feed == nil {
+		feed = &CalendarFeed{
+			UserId:          req.Data.UserId,
+			Token:           token,
+			TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+		}
+		feed, err = s.feedRepo.Add(ctx, feed)
+	} else {
+		feed.Token = token
+		feed, err = s.feedRepo.Update(ctx, feed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[CalendarFeedDTO]{
+		Data: CalendarFeedDTO{
+			UserId: feed.UserId,
+			Token:  feed.Token,
+			Path:   CalendarPathPrefix + feed.Token + ".ics",
+		},
+	}, nil
+}
+
+func (s *service) RenderCalendar(ctx context.Context, token string) ([]byte, error) {
+	if len(token) == 0 {
+		return nil, ErrCalendarFeedNotFound
+	}
+	feeds, err := s.findFeeds(ctx, "token", token)
+	if err != nil {
+		return nil, err
+	}
+	if len(feeds) == 0 {
+		return nil, ErrCalendarFeedNotFound
+	}
+	feed := feeds[0]
+
+	bills, err := s.findBills(ctx, "tenant_id", feed.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	visible := []Bill{}
+	for _, bill := range bills {
+		if len(bill.NotifyUserIds) > 0 && !slices.Contains(bill.NotifyUserIds, feed.UserId) {
+			continue
+		}
+		if bill.Status == BillStatusPaid && daysUntil(bill.DueDate, now) < -calendarHistoryDays {
+			continue
+		}
+		visible = append(visible, bill)
+	}
+	sort.Slice(visible, func(i, j int) bool {
+		return visible[i].DueDate < visible[j].DueDate
+	})
+
+	return renderCalendar(visible, time.Now()), nil
+}
+
+func (s *service) SendReminders(ctx context.Context, asOf time.Time) (int, error) {
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+
+	sent := 0
+	for page := 1; ; page++ {
+		bills, err := s.findBillPage(ctx, "status", BillStatusUnpaid, page)
+		if err != nil {
+			return sent, err
+		}
+		for i := range bills {
+			reminded, err := s.remind(ctx, &bills[i], asOf)
+			if err != nil {
+				return sent, err
+			}
+			if reminded {
+				sent++
+			}
+		}
+		if len(bills) < billPageSize {
+			return sent, nil
+		}
+	}
+}
+
+// remind 按到期情况发送一次提醒或逾期通知，已提醒过的账单不再重复发送
+func (s *service) remind(ctx context.Context, bill *Bill, asOf time.Time) (bool, error) {
+	due := daysUntil(bill.DueDate, asOf)
+
+	var title, content string
+	switch {
+	case due < 0 && !bill.OverdueNotice:
+		title = "账单逾期：" + bill.Name
+		content = fmt.Sprintf("%s 已于 %s 到期，应付 %s %s，请尽快处理。", bill.Name, bill.DueDate, formatAmount(bill.ExpectedAmount), bill.Currency)
+		bill.OverdueNotice = true
+	case due >= 0 && due <= bill.RemindDaysBefore && len(bill.RemindedDate) == 0:
+		title = "账单提醒：" + bill.Name
+		content = fmt.Sprintf("%s 将于 %s 到期，应付 %s %s。", bill.Name, bill.DueDate, formatAmount(bill.ExpectedAmount), bill.Currency)
+		bill.RemindedDate = asOf.Format(DateLayout)
+	default:
+		return false, nil
+	}
+
+	userIds := bill.NotifyUserIds
+	if len(userIds) == 0 {
+		var err error
+		if userIds, err = s.members.TenantUserIds(ctx, bill.TenantId); err != nil {
+			return false, err
+		}
+	}
+	// 先记录提醒状态再发送，避免重复执行时重复提醒
+	if _, err := s.billRepo.Update(ctx, bill); err != nil {
+		return false, err
+	}
+	s.notifier.Notify(notification.Message{
+		TenantId: bill.TenantId,
+		UserIds:  userIds,
+		Category: NotificationCategory,
+		Title:    title,
+		Content:  content,
+	})
+	return true, nil
+}
+
+func (s *service) listBills(ctx context.Context, tenantId string, filter func(bill *Bill, now time.Time) bool) (*response.DataResponse[[]BillDTO], error) {
+	bills, err := s.findBills(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(bills, func(i, j int) bool {
+		return bills[i].DueDate < bills[j].DueDate
+	})
+
+	now := today()
+	dtos := []BillDTO{}
+	for i := range bills {
+		if filter(&bills[i], now) {
+			dtos = append(dtos, toBillDTO(&bills[i], now))
+		}
+	}
+	return &response.DataResponse[[]BillDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func toBillDTO(bill *Bill, now time.Time) BillDTO {
+	due := daysUntil(bill.DueDate, now)
+	return BillDTO{
+		Id:               bill.Id,
+		TenantId:         bill.TenantId,
+		Name:             bill.Name,
+		Category:         bill.Category,
+		PayeeId:          bill.PayeeId,
+		ExpectedAmount:   bill.ExpectedAmount,
+		Currency:         bill.Currency,
+		DueDate:          bill.DueDate,
+		Status:           bill.Status,
+		PaidAmount:       bill.PaidAmount,
+		PaidDate:         bill.PaidDate,
+		Memo:             bill.Memo,
+		RemindDaysBefore: bill.RemindDaysBefore,
+		NotifyUserIds:    bill.NotifyUserIds,
+		SourceType:       bill.SourceType,
+		SourceId:         bill.SourceId,
+		Overdue:          bill.Status == BillStatusUnpaid && due < 0,
+		DaysUntilDue:     due,
+	}
+}
+
+func daysUntil(date string, now time.Time) int {
+	d, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return 0
+	}
+	return int(d.Sub(now).Hours() / 24)
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (s *service) findBillById(ctx context.Context, tenantId string, id string) (*Bill, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	bills, err := s.billRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(bills) == 0 {
+		return nil, ErrBillNotFound
+	}
+	return &bills[0], nil
+}
+
+func (s *service) findBills(ctx context.Context, field string, value string) ([]Bill, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.billRepo.Query(ctx, query)
+}
+
+// findBillPage 按 id 顺序分页查询，提醒过程中更新账单不会打乱分页
+func (s *service) findBillPage(ctx context.Context, field string, value string, page int) ([]Bill, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		OrderBys:    []query.DbQueryOrderBy{query.NewDbQueryOrderBy("id", false)},
+		PageSize:    billPageSize,
+		PageNumber:  page,
+	}
+	return s.billRepo.Query(ctx, query)
+}
+
+func (s *service) findSourceBill(ctx context.Context, tenantId string, sourceType string, sourceId string, dueDate string) (*Bill, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("source_type", []interface{}{sourceType}, query.EQ, "String"),
+		query.NewDbQueryFilter("source_id", []interface{}{sourceId}, query.EQ, "String"),
+		query.NewDbQueryFilter("due_date", []interface{}{dueDate}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	bills, err := s.billRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(bills) == 0 {
+		return nil, nil
+	}
+	return &bills[0], nil
+}
+
+func (s *service) findFeeds(ctx context.Context, field string, value string) ([]CalendarFeed, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10,
+		PageNumber:  1,
+	}
+	return s.feedRepo.Query(ctx, query)
+}
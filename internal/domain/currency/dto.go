@@ -0,0 +1,70 @@
+package currency
+
+type ExchangeRateDTO struct {
+	Id           string  `json:"id"`
+	FromCurrency string  `json:"from_currency"`
+	ToCurrency   string  `json:"to_currency"`
+	RateDate     string  `json:"rate_date"`
+	Rate         float64 `json:"rate"`
+	Source       string  `json:"source"`
+}
+
+type SaveExchangeRateDTO struct {
+	TenantId     string  `json:"tenant_id" binding:"required"`
+	FromCurrency string  `json:"from_currency" binding:"required,len=3"`
+	ToCurrency   string  `json:"to_currency" binding:"required,len=3"`
+	RateDate     string  `json:"rate_date" binding:"required"`
+	Rate         float64 `json:"rate" binding:"required,gt=0"`
+}
+
+type ImportExchangeRatesDTO struct {
+	TenantId string `form:"tenant_id" binding:"required"`
+	Format   string `form:"format" binding:"omitempty,oneof=csv json"`
+	Data     []byte `form:"-"`
+}
+
+type ImportResultDTO struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+type CurrencySettingDTO struct {
+	TenantId     string `json:"tenant_id" binding:"required"`
+	BaseCurrency string `json:"base_currency" binding:"required,len=3"`
+}
+
+type ConvertDTO struct {
+	TenantId     string `json:"tenant_id" binding:"required"`
+	Amount       int64  `json:"amount"`
+	FromCurrency string `json:"from_currency" binding:"required,len=3"`
+	ToCurrency   string `json:"to_currency" binding:"omitempty,len=3"`
+	// 为空时使用最新汇率
+	Date string `json:"date" binding:"omitempty"`
+}
+
+type ConvertResultDTO struct {
+	Amount          int64   `json:"amount"`
+	FromCurrency    string  `json:"from_currency"`
+	ToCurrency      string  `json:"to_currency"`
+	ConvertedAmount int64   `json:"converted_amount"`
+	Rate            float64 `json:"rate"`
+	RateDate        string  `json:"rate_date"`
+}
+
+type RevalueDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency" binding:"required,len=3"`
+	// 外币余额的入账日期
+	BookedDate string `json:"booked_date" binding:"required"`
+}
+
+type RevalueResultDTO struct {
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	BaseCurrency string `json:"base_currency"`
+	BookedValue  int64  `json:"booked_value"`
+	CurrentValue int64  `json:"current_value"`
+	// 未实现汇兑损益，正数为收益
+	UnrealizedGain int64 `json:"unrealized_gain"`
+}
@@ -0,0 +1,38 @@
+package currency
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	DefaultBaseCurrency = "CNY"
+
+	RateSourceManual = "manual"
+	RateSourceImport = "import"
+
+	DateLayout = "2006-01-02"
+)
+
+// ExchangeRate 某日 1 单位 FromCurrency 可兑换的 ToCurrency 数量，同一家庭同一币种对每天只保留一条
+type ExchangeRate struct {
+	model.DbBaseModel
+	TenantId     string  `json:"tenant_id" gorm:"size:32;not null;uniqueIndex:idx_exchange_rate_unique"`
+	FromCurrency string  `json:"from_currency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_unique"`
+	ToCurrency   string  `json:"to_currency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_unique"`
+	RateDate     string  `json:"rate_date" gorm:"size:10;not null;uniqueIndex:idx_exchange_rate_unique"`
+	Rate         float64 `json:"rate" gorm:"not null"`
+	Source       string  `json:"source" gorm:"size:20"`
+}
+
+func (entity *ExchangeRate) TableName() string {
+	return "finance_exchange_rate"
+}
+
+type CurrencySetting struct {
+	model.TenantBaseModel
+	BaseCurrency string `json:"base_currency" gorm:"size:3;not null"`
+}
+
+func (entity *CurrencySetting) TableName() string {
+	return "finance_currency_setting"
+}
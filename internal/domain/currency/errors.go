@@ -0,0 +1,13 @@
+package currency
+
+import "errors"
+
+var (
+	ErrCurrencyInvalid  = errors.New("币种代码无效")
+	ErrRateInvalid      = errors.New("汇率必须大于0")
+	ErrRateDateInvalid  = errors.New("汇率日期格式无效，应为YYYY-MM-DD")
+	ErrRateNotFound     = errors.New("未找到可用汇率")
+	ErrImportFormat     = errors.New("不支持的导入文件格式")
+	ErrImportEmpty      = errors.New("导入文件中没有汇率数据")
+	ErrSameCurrencyRate = errors.New("源币种与目标币种不能相同")
+)
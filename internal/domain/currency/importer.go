@@ -0,0 +1,113 @@
+package currency
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+type importedRate struct {
+	RateDate     string  `json:"rate_date"`
+	FromCurrency string  `json:"from_currency"`
+	ToCurrency   string  `json:"to_currency"`
+	Rate         float64 `json:"rate"`
+}
+
+// parseRates 解析汇率文件，CSV 列顺序为 rate_date,from_currency,to_currency,rate，首行可为表头；
+// JSON 为对象数组，字段名与 CSV 表头一致
+func parseRates(data []byte, format string) ([]importedRate, error) {
+	if len(format) == 0 {
+		format = ImportFormatCSV
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			format = ImportFormatJSON
+		}
+	}
+
+	var rates []importedRate
+	var err error
+	switch format {
+	case ImportFormatCSV:
+		rates, err = parseCSVRates(data)
+	case ImportFormatJSON:
+		err = json.Unmarshal(data, &rates)
+	default:
+		return nil, ErrImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	for i := range rates {
+		if err := normalizeRate(&rates[i]); err != nil {
+			return nil, fmt.Errorf("第%d条汇率: %w", i+1, err)
+		}
+	}
+	return rates, nil
+}
+
+func parseCSVRates(data []byte) ([]importedRate, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	rates := []importedRate{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "rate_date") {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行: %w", line, ErrRateInvalid)
+		}
+		rates = append(rates, importedRate{
+			RateDate:     strings.TrimSpace(record[0]),
+			FromCurrency: record[1],
+			ToCurrency:   record[2],
+			Rate:         rate,
+		})
+	}
+	return rates, nil
+}
+
+func normalizeRate(rate *importedRate) error {
+	from, err := normalizeCurrency(rate.FromCurrency)
+	if err != nil {
+		return err
+	}
+	to, err := normalizeCurrency(rate.ToCurrency)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return ErrSameCurrencyRate
+	}
+	if rate.Rate <= 0 {
+		return ErrRateInvalid
+	}
+	if !validDate(rate.RateDate) {
+		return ErrRateDateInvalid
+	}
+	rate.FromCurrency = from
+	rate.ToCurrency = to
+	return nil
+}
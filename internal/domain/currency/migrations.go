@@ -0,0 +1,22 @@
+package currency
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建汇率表
+	if err := db.AutoMigrate(&ExchangeRate{}); err != nil {
+		fmt.Println("创建汇率表失败", err)
+	}
+
+	// 创建币种设置表
+	if err := db.AutoMigrate(&CurrencySetting{}); err != nil {
+		fmt.Println("创建币种设置表失败", err)
+	}
+
+	fmt.Println("Currency模块迁移完成")
+}
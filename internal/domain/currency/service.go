@@ -0,0 +1,474 @@
+package currency
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+type CurrencyService interface {
+	SaveExchangeRate(ctx context.Context, req *request.DataRequest[SaveExchangeRateDTO]) (*response.DataResponse[ExchangeRateDTO], error)
+	ImportExchangeRates(ctx context.Context, req *ImportExchangeRatesDTO) (*response.DataResponse[ImportResultDTO], error)
+	ListExchangeRates(ctx context.Context, tenantId string, from string, to string) (*response.DataResponse[[]ExchangeRateDTO], error)
+	GetSetting(ctx context.Context, tenantId string) (*response.DataResponse[CurrencySettingDTO], error)
+	SaveSetting(ctx context.Context, req *request.DataRequest[CurrencySettingDTO]) (*response.DataResponse[CurrencySettingDTO], error)
+	Convert(ctx context.Context, req *request.DataRequest[ConvertDTO]) (*response.DataResponse[ConvertResultDTO], error)
+	Revalue(ctx context.Context, req *request.DataRequest[RevalueDTO]) (*response.DataResponse[RevalueResultDTO], error)
+	Converter
+}
+
+// Converter 供其他模块将金额折算为家庭本位币
+type Converter interface {
+	BaseCurrency(ctx context.Context, tenantId string) (string, error)
+	// ConvertAmount 按 date 当日（含）之前最近的汇率折算，date 为空时使用最新汇率
+	ConvertAmount(ctx context.Context, tenantId string, amount int64, from string, to string, date string) (int64, error)
+}
+
+type service struct {
+	rateRepo    repository.Repository[ExchangeRate]
+	settingRepo repository.Repository[CurrencySetting]
+}
+
+func NewCurrencyService(
+	rateRepo repository.Repository[ExchangeRate],
+	settingRepo repository.Repository[CurrencySetting],
+) CurrencyService {
+	return &service{
+		rateRepo:    rateRepo,
+		settingRepo: settingRepo,
+	}
+}
+
+func (s *service) SaveExchangeRate(ctx context.Context, req *request.DataRequest[SaveExchangeRateDTO]) (*response.DataResponse[ExchangeRateDTO], error) {
+	rate := importedRate{
+		RateDate:     req.Data.RateDate,
+		FromCurrency: req.Data.FromCurrency,
+		ToCurrency:   req.Data.ToCurrency,
+		Rate:         req.Data.Rate,
+	}
+	if err := normalizeRate(&rate); err != nil {
+		return nil, err
+	}
+	rates, err := s.findPairRates(ctx, req.Data.TenantId, rate.FromCurrency, rate.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, _, err := s.upsertRate(ctx, req.Data.TenantId, rates, rate, RateSourceManual)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[ExchangeRateDTO]{
+		Data: toExchangeRateDTO(entity),
+	}, nil
+}
+
+func (s *service) ImportExchangeRates(ctx context.Context, req *ImportExchangeRatesDTO) (*response.DataResponse[ImportResultDTO], error) {
+	imported, err := parseRates(req.Data, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按币种对加载已有汇率，只查询导入中出现的币种对
+	pairRates := map[[2]string][]ExchangeRate{}
+	result := ImportResultDTO{}
+	for _, rate := range imported {
+		pair := [2]string{rate.FromCurrency, rate.ToCurrency}
+		rates, ok := pairRates[pair]
+		if !ok {
+			rates, err = s.findPairRates(ctx, req.TenantId, rate.FromCurrency, rate.ToCurrency)
+			if err != nil {
+				return nil, err
+			}
+		}
+		entity, created, err := s.upsertRate(ctx, req.TenantId, rates, rate, RateSourceImport)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			rates = append(rates, *entity)
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		pairRates[pair] = rates
+	}
+
+	return &response.DataResponse[ImportResultDTO]{
+		Data: result,
+	}, nil
+}
+
+func (s *service) ListExchangeRates(ctx context.Context, tenantId string, from string, to string) (*response.DataResponse[[]ExchangeRateDTO], error) {
+	rates, err := s.findRates(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+
+	dtos := []ExchangeRateDTO{}
+	for i := range rates {
+		if len(from) > 0 && rates[i].FromCurrency != from {
+			continue
+		}
+		if len(to) > 0 && rates[i].ToCurrency != to {
+			continue
+		}
+		dtos = append(dtos, toExchangeRateDTO(&rates[i]))
+	}
+	return &response.DataResponse[[]ExchangeRateDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetSetting(ctx context.Context, tenantId string) (*response.DataResponse[CurrencySettingDTO], error) {
+	base, err := s.BaseCurrency(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[CurrencySettingDTO]{
+		Data: CurrencySettingDTO{
+			TenantId:     tenantId,
+			BaseCurrency: base,
+		},
+	}, nil
+}
+
+func (s *service) SaveSetting(ctx context.Context, req *request.DataRequest[CurrencySettingDTO]) (*response.DataResponse[CurrencySettingDTO], error) {
+	base, err := normalizeCurrency(req.Data.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	setting, err := s.findSetting(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	if setting == nil {
+		setting = &CurrencySetting{
+			BaseCurrency:    base,
+			TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+		}
+		setting, err = s.settingRepo.Add(ctx, setting)
+	} else {
+		setting.BaseCurrency = base
+		setting, err = s.settingRepo.Update(ctx, setting)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[CurrencySettingDTO]{
+		Data: CurrencySettingDTO{
+			TenantId:     setting.TenantId,
+			BaseCurrency: setting.BaseCurrency,
+		},
+	}, nil
+}
+
+func (s *service) Convert(ctx context.Context, req *request.DataRequest[ConvertDTO]) (*response.DataResponse[ConvertResultDTO], error) {
+	from, err := normalizeCurrency(req.Data.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+	to := req.Data.ToCurrency
+	if len(to) == 0 {
+		if to, err = s.BaseCurrency(ctx, req.Data.TenantId); err != nil {
+			return nil, err
+		}
+	}
+	if to, err = normalizeCurrency(to); err != nil {
+		return nil, err
+	}
+	if len(req.Data.Date) > 0 && !validDate(req.Data.Date) {
+		return nil, ErrRateDateInvalid
+	}
+
+	base, err := s.BaseCurrency(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.findConversionRates(ctx, req.Data.TenantId, from, to, base)
+	if err != nil {
+		return nil, err
+	}
+	rate, rateDate, ok := LookupRate(rates, from, to, req.Data.Date, base)
+	if !ok {
+		return nil, ErrRateNotFound
+	}
+
+	return &response.DataResponse[ConvertResultDTO]{
+		Data: ConvertResultDTO{
+			Amount:          req.Data.Amount,
+			FromCurrency:    from,
+			ToCurrency:      to,
+			ConvertedAmount: applyRate(req.Data.Amount, rate),
+			Rate:            rate,
+			RateDate:        rateDate,
+		},
+	}, nil
+}
+
+func (s *service) Revalue(ctx context.Context, req *request.DataRequest[RevalueDTO]) (*response.DataResponse[RevalueResultDTO], error) {
+	currency, err := normalizeCurrency(req.Data.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if !validDate(req.Data.BookedDate) {
+		return nil, ErrRateDateInvalid
+	}
+	base, err := s.BaseCurrency(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.findConversionRates(ctx, req.Data.TenantId, currency, base, base)
+	if err != nil {
+		return nil, err
+	}
+
+	bookedRate, _, ok := LookupRate(rates, currency, base, req.Data.BookedDate, base)
+	if !ok {
+		return nil, ErrRateNotFound
+	}
+	currentRate, _, ok := LookupRate(rates, currency, base, "", base)
+	if !ok {
+		return nil, ErrRateNotFound
+	}
+	bookedValue := applyRate(req.Data.Amount, bookedRate)
+	currentValue := applyRate(req.Data.Amount, currentRate)
+
+	return &response.DataResponse[RevalueResultDTO]{
+		Data: RevalueResultDTO{
+			Amount:         req.Data.Amount,
+			Currency:       currency,
+			BaseCurrency:   base,
+			BookedValue:    bookedValue,
+			CurrentValue:   currentValue,
+			UnrealizedGain: currentValue - bookedValue,
+		},
+	}, nil
+}
+
+func (s *service) BaseCurrency(ctx context.Context, tenantId string) (string, error) {
+	setting, err := s.findSetting(ctx, tenantId)
+	if err != nil {
+		return "", err
+	}
+	if setting == nil {
+		return DefaultBaseCurrency, nil
+	}
+	return setting.BaseCurrency, nil
+}
+
+func (s *service) ConvertAmount(ctx context.Context, tenantId string, amount int64, from string, to string, date string) (int64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+	base, err := s.BaseCurrency(ctx, tenantId)
+	if err != nil {
+		return 0, err
+	}
+	rates, err := s.findConversionRates(ctx, tenantId, from, to, base)
+	if err != nil {
+		return 0, err
+	}
+	rate, _, ok := LookupRate(rates, from, to, date, base)
+	if !ok {
+		return 0, ErrRateNotFound
+	}
+	return applyRate(amount, rate), nil
+}
+
+// LookupRate 查找 date 当日（含）之前最近的 from->to 汇率，date 为空时取最新汇率。
+// 依次尝试直接汇率、反向汇率，以及经由 via 币种的交叉汇率，返回汇率和所用汇率中较早的日期
+func LookupRate(rates []ExchangeRate, from string, to string, date string, via string) (float64, string, bool) {
+	if from == to {
+		return 1, date, true
+	}
+	if rate, rateDate, ok := lookupPair(rates, from, to, date); ok {
+		return rate, rateDate, true
+	}
+	if via == from || via == to || len(via) == 0 {
+		return 0, "", false
+	}
+	first, firstDate, ok := lookupPair(rates, from, via, date)
+	if !ok {
+		return 0, "", false
+	}
+	second, secondDate, ok := lookupPair(rates, via, to, date)
+	if !ok {
+		return 0, "", false
+	}
+	return first * second, min(firstDate, secondDate), true
+}
+
+func lookupPair(rates []ExchangeRate, from string, to string, date string) (float64, string, bool) {
+	var best *ExchangeRate
+	inverse := false
+	for i := range rates {
+		r := &rates[i]
+		if len(date) > 0 && r.RateDate > date {
+			continue
+		}
+		isDirect := r.FromCurrency == from && r.ToCurrency == to
+		isInverse := r.FromCurrency == to && r.ToCurrency == from
+		if !isDirect && !isInverse {
+			continue
+		}
+		// 同一天同时存在正反向汇率时优先使用正向汇率
+		if best == nil || r.RateDate > best.RateDate || (r.RateDate == best.RateDate && isDirect && inverse) {
+			best = r
+			inverse = isInverse
+		}
+	}
+	if best == nil {
+		return 0, "", false
+	}
+	if inverse {
+		return 1 / best.Rate, best.RateDate, true
+	}
+	return best.Rate, best.RateDate, true
+}
+
+func applyRate(amount int64, rate float64) int64 {
+	return int64(math.Round(float64(amount) * rate))
+}
+
+func (s *service) upsertRate(ctx context.Context, tenantId string, rates []ExchangeRate, rate importedRate, source string) (*ExchangeRate, bool, error) {
+	for i := range rates {
+		existing := &rates[i]
+		if existing.FromCurrency == rate.FromCurrency && existing.ToCurrency == rate.ToCurrency && existing.RateDate == rate.RateDate {
+			existing.Rate = rate.Rate
+			existing.Source = source
+			updated, err := s.rateRepo.Update(ctx, existing)
+			return updated, false, err
+		}
+	}
+
+	entity := &ExchangeRate{
+		FromCurrency: rate.FromCurrency,
+		ToCurrency:   rate.ToCurrency,
+		RateDate:     rate.RateDate,
+		Rate:         rate.Rate,
+		Source:       source,
+		TenantId:     tenantId,
+		DbBaseModel:  model.NewDbBaseModel(util.GenerateId()),
+	}
+	entity, err := s.rateRepo.Add(ctx, entity)
+	return entity, true, err
+}
+
+func (s *service) findRates(ctx context.Context, tenantId string) ([]ExchangeRate, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    100000,
+		PageNumber:  1,
+	}
+	return s.rateRepo.Query(ctx, query)
+}
+
+// findConversionRates 只加载 from->to 折算可能用到的币种对：直接/反向汇率以及经由 via 的交叉汇率
+func (s *service) findConversionRates(ctx context.Context, tenantId string, from string, to string, via string) ([]ExchangeRate, error) {
+	rates, err := s.findPairRates(ctx, tenantId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(via) == 0 || via == from || via == to {
+		return rates, nil
+	}
+	for _, pair := range [][2]string{{from, via}, {via, to}} {
+		pairRates, err := s.findPairRates(ctx, tenantId, pair[0], pair[1])
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, pairRates...)
+	}
+	return rates, nil
+}
+
+// findPairRates 查询 a、b 两个币种之间正反两个方向的汇率
+func (s *service) findPairRates(ctx context.Context, tenantId string, a string, b string) ([]ExchangeRate, error) {
+	rates := []ExchangeRate{}
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		wheres := []query.DbQueryWhere{}
+		filters := []query.DbQueryFilter{
+			query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+			query.NewDbQueryFilter("from_currency", []interface{}{pair[0]}, query.EQ, "String"),
+			query.NewDbQueryFilter("to_currency", []interface{}{pair[1]}, query.EQ, "String"),
+		}
+		wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+		query := &query.DbQuery{
+			QueryWheres: wheres,
+			PageSize:    100000,
+			PageNumber:  1,
+		}
+		pairRates, err := s.rateRepo.Query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, pairRates...)
+	}
+	return rates, nil
+}
+
+func (s *service) findSetting(ctx context.Context, tenantId string) (*CurrencySetting, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	settings, err := s.settingRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	return &settings[0], nil
+}
+
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", ErrCurrencyInvalid
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", ErrCurrencyInvalid
+		}
+	}
+	return code, nil
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func toExchangeRateDTO(rate *ExchangeRate) ExchangeRateDTO {
+	return ExchangeRateDTO{
+		Id:           rate.Id,
+		FromCurrency: rate.FromCurrency,
+		ToCurrency:   rate.ToCurrency,
+		RateDate:     rate.RateDate,
+		Rate:         rate.Rate,
+		Source:       rate.Source,
+	}
+}
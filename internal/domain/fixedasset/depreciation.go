@@ -0,0 +1,101 @@
+package fixedasset
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// valueAt 计算资产在 date 当日的价值：以当日（含）之前最近的估值为基础，没有估值时以购入价为基础，
+// 再按折旧方法扣减基础日期之后的折旧。返回 false 表示当日尚未购入或已处置
+func valueAt(asset *FixedAsset, valuations []AssetValuation, date string) (int64, bool) {
+	if date < asset.PurchaseDate {
+		return 0, false
+	}
+	if len(asset.DisposedDate) > 0 && date >= asset.DisposedDate {
+		return 0, false
+	}
+
+	baseDate, baseValue := asset.PurchaseDate, asset.PurchasePrice
+	for _, valuation := range valuations {
+		if valuation.ValuationDate <= date && valuation.ValuationDate >= baseDate {
+			baseDate, baseValue = valuation.ValuationDate, valuation.Value
+		}
+	}
+	return depreciate(asset, baseDate, baseValue, date), true
+}
+
+func depreciate(asset *FixedAsset, baseDate string, baseValue int64, date string) int64 {
+	if baseValue <= asset.SalvageValue {
+		return baseValue
+	}
+	elapsed := monthsBetween(baseDate, date)
+	var value float64
+	switch asset.Depreciation {
+	case DepreciationStraightLine:
+		// 估值后按剩余使用月数重新摊销
+		remaining := asset.UsefulLifeMonths - monthsBetween(asset.PurchaseDate, baseDate)
+		// 使用期满后的估值不再继续折旧
+		if remaining <= 0 {
+			return baseValue
+		}
+		monthly := float64(baseValue-asset.SalvageValue) / float64(remaining)
+		value = float64(baseValue) - monthly*float64(min(elapsed, remaining))
+	case DepreciationDecliningBalance:
+		rate := float64(asset.AnnualRateBps) / 10000
+		value = float64(baseValue) * math.Pow(1-rate, float64(elapsed)/12)
+	default:
+		return baseValue
+	}
+	return max(int64(math.Round(value)), asset.SalvageValue)
+}
+
+// schedulePoints 生成从购入起每满一年的折旧计划，直线法至使用期满，其他方法最多 years 年
+func schedulePoints(asset *FixedAsset, valuations []AssetValuation, years int) []SchedulePointDTO {
+	purchase, err := time.Parse(DateLayout, asset.PurchaseDate)
+	if err != nil {
+		return []SchedulePointDTO{}
+	}
+	if asset.Depreciation == DepreciationStraightLine && asset.UsefulLifeMonths > 0 {
+		years = (asset.UsefulLifeMonths + 11) / 12
+	}
+	valuations = append([]AssetValuation(nil), valuations...)
+	sort.Slice(valuations, func(i, j int) bool {
+		return valuations[i].ValuationDate < valuations[j].ValuationDate
+	})
+
+	points := make([]SchedulePointDTO, 0, years+1)
+	previous := asset.PurchasePrice
+	for year := 0; year <= years; year++ {
+		date := purchase.AddDate(year, 0, 0).Format(DateLayout)
+		value, ok := valueAt(asset, valuations, date)
+		if !ok {
+			break
+		}
+		points = append(points, SchedulePointDTO{
+			Year:         year,
+			Date:         date,
+			Value:        value,
+			Depreciation: previous - value,
+		})
+		previous = value
+	}
+	return points
+}
+
+// monthsBetween 返回两个日期之间的整月数
+func monthsBetween(from string, to string) int {
+	f, err := time.Parse(DateLayout, from)
+	if err != nil {
+		return 0
+	}
+	t, err := time.Parse(DateLayout, to)
+	if err != nil || t.Before(f) {
+		return 0
+	}
+	months := (t.Year()-f.Year())*12 + int(t.Month()-f.Month())
+	if t.Day() < f.Day() {
+		months--
+	}
+	return max(months, 0)
+}
@@ -0,0 +1,99 @@
+package fixedasset
+
+import "testing"
+
+func TestMonthsBetween(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want int
+	}{
+		{"2024-01-15", "2024-01-15", 0},
+		{"2024-01-15", "2024-02-14", 0},
+		{"2024-01-15", "2024-02-15", 1},
+		{"2024-01-31", "2024-03-01", 1},
+		{"2023-06-01", "2024-06-01", 12},
+		{"2024-06-01", "2024-01-01", 0},
+		{"invalid", "2024-01-01", 0},
+	}
+	for _, tt := range tests {
+		if got := monthsBetween(tt.from, tt.to); got != tt.want {
+			t.Errorf("monthsBetween(%s, %s) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestDepreciate(t *testing.T) {
+	straightLine := &FixedAsset{
+		PurchaseDate:     "2020-01-01",
+		PurchasePrice:    120000,
+		SalvageValue:     0,
+		UsefulLifeMonths: 60,
+		Depreciation:     DepreciationStraightLine,
+	}
+	declining := &FixedAsset{
+		PurchaseDate:  "2020-01-01",
+		PurchasePrice: 100000,
+		SalvageValue:  10000,
+		AnnualRateBps: 2000,
+		Depreciation:  DepreciationDecliningBalance,
+	}
+	tests := []struct {
+		name      string
+		asset     *FixedAsset
+		baseDate  string
+		baseValue int64
+		date      string
+		want      int64
+	}{
+		{"直线法按月摊销", straightLine, "2020-01-01", 120000, "2021-01-01", 96000},
+		{"直线法期满后为残值", straightLine, "2020-01-01", 120000, "2026-01-01", 0},
+		{"估值后按剩余月数重新摊销", straightLine, "2022-01-01", 60000, "2023-01-01", 40000},
+		{"使用期满后的估值保持不变", straightLine, "2025-06-01", 5000, "2026-06-01", 5000},
+		{"估值为零时保持为零", straightLine, "2022-01-01", 0, "2023-01-01", 0},
+		{"余额递减法按年率折旧", declining, "2020-01-01", 100000, "2021-01-01", 80000},
+		{"余额递减法不低于残值", declining, "2020-01-01", 100000, "2035-01-01", 10000},
+		{"基础价值不高于残值时不再折旧", declining, "2020-01-01", 8000, "2022-01-01", 8000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := depreciate(tt.asset, tt.baseDate, tt.baseValue, tt.date); got != tt.want {
+				t.Errorf("depreciate() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueAt(t *testing.T) {
+	asset := &FixedAsset{
+		PurchaseDate:     "2020-01-01",
+		PurchasePrice:    120000,
+		UsefulLifeMonths: 60,
+		Depreciation:     DepreciationStraightLine,
+		DisposedDate:     "2030-01-01",
+	}
+	valuations := []AssetValuation{
+		{ValuationDate: "2022-01-01", Value: 60000},
+		{ValuationDate: "2026-01-01", Value: 3000},
+	}
+	tests := []struct {
+		name   string
+		date   string
+		want   int64
+		wantOk bool
+	}{
+		{"购入前没有价值", "2019-12-31", 0, false},
+		{"估值前按购入价折旧", "2021-01-01", 96000, true},
+		{"以最近一次估值为基础", "2023-01-01", 40000, true},
+		{"使用期满后的估值被保留", "2027-01-01", 3000, true},
+		{"处置后没有价值", "2030-01-01", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := valueAt(asset, valuations, tt.date)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("valueAt() = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
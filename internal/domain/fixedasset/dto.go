@@ -0,0 +1,97 @@
+package fixedasset
+
+type FixedAssetDTO struct {
+	Id               string `json:"id"`
+	TenantId         string `json:"tenant_id"`
+	Name             string `json:"name"`
+	Category         string `json:"category"`
+	PurchasePrice    int64  `json:"purchase_price"`
+	PurchaseDate     string `json:"purchase_date"`
+	Currency         string `json:"currency"`
+	OwnerUserId      string `json:"owner_user_id"`
+	Depreciation     string `json:"depreciation"`
+	UsefulLifeMonths int    `json:"useful_life_months"`
+	AnnualRateBps    int    `json:"annual_rate_bps"`
+	SalvageValue     int64  `json:"salvage_value"`
+	DisposedDate     string `json:"disposed_date"`
+	DisposalAmount   int64  `json:"disposal_amount"`
+	Memo             string `json:"memo"`
+	// 今日价值，已处置时为 0
+	CurrentValue int64 `json:"current_value"`
+	// 最近一次手工估值
+	LastValuationDate string `json:"last_valuation_date"`
+}
+
+type CreateFixedAssetDTO struct {
+	TenantId         string `json:"tenant_id" binding:"required"`
+	Name             string `json:"name" binding:"required,max_len=200"`
+	Category         string `json:"category" binding:"required,oneof=real_estate vehicle valuables electronics other"`
+	PurchasePrice    int64  `json:"purchase_price" binding:"required,min=1"`
+	PurchaseDate     string `json:"purchase_date" binding:"required"`
+	Currency         string `json:"currency" binding:"omitempty,len=3"`
+	OwnerUserId      string `json:"owner_user_id" binding:"omitempty"`
+	Depreciation     string `json:"depreciation" binding:"omitempty,oneof=none straight_line declining_balance"`
+	UsefulLifeMonths int    `json:"useful_life_months" binding:"omitempty,min=1,max=1200"`
+	AnnualRateBps    int    `json:"annual_rate_bps" binding:"omitempty,min=1,max=10000"`
+	SalvageValue     int64  `json:"salvage_value" binding:"omitempty,min=0"`
+	Memo             string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type UpdateFixedAssetDTO struct {
+	TenantId         string `json:"tenant_id" binding:"required"`
+	Id               string `json:"id"`
+	Name             string `json:"name" binding:"omitempty,max_len=200"`
+	OwnerUserId      string `json:"owner_user_id" binding:"omitempty"`
+	Depreciation     string `json:"depreciation" binding:"omitempty,oneof=none straight_line declining_balance"`
+	UsefulLifeMonths int    `json:"useful_life_months" binding:"omitempty,min=1,max=1200"`
+	AnnualRateBps    int    `json:"annual_rate_bps" binding:"omitempty,min=1,max=10000"`
+	SalvageValue     *int64 `json:"salvage_value" binding:"omitempty,min=0"`
+	Memo             string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type CreateValuationDTO struct {
+	TenantId      string `json:"tenant_id" binding:"required"`
+	AssetId       string `json:"asset_id"`
+	ValuationDate string `json:"valuation_date" binding:"required"`
+	// 指针类型以便估值为0（已报废）时通过必填校验
+	Value *int64 `json:"value" binding:"required,min=0"`
+	Memo  string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type ValuationDTO struct {
+	Id            string `json:"id"`
+	ValuationDate string `json:"valuation_date"`
+	Value         int64  `json:"value"`
+	Memo          string `json:"memo"`
+}
+
+type DisposeFixedAssetDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	Id       string `json:"id"`
+	// 为空时为今天
+	DisposedDate   string `json:"disposed_date" binding:"omitempty"`
+	DisposalAmount int64  `json:"disposal_amount" binding:"omitempty,min=0"`
+}
+
+type SchedulePointDTO struct {
+	Year  int    `json:"year"`
+	Date  string `json:"date"`
+	Value int64  `json:"value"`
+	// 与上一年相比减少的价值，手工估值上调时为负数
+	Depreciation int64 `json:"depreciation"`
+}
+
+type FixedAssetDetailDTO struct {
+	Asset      FixedAssetDTO      `json:"asset"`
+	Valuations []ValuationDTO     `json:"valuations"`
+	Schedule   []SchedulePointDTO `json:"schedule"`
+}
+
+type AssetValue struct {
+	AssetId     string `json:"asset_id"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	OwnerUserId string `json:"owner_user_id"`
+	Currency    string `json:"currency"`
+	Value       int64  `json:"value"`
+}
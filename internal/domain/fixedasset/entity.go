@@ -0,0 +1,58 @@
+package fixedasset
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	CategoryRealEstate  = "real_estate"
+	CategoryVehicle     = "vehicle"
+	CategoryValuables   = "valuables"
+	CategoryElectronics = "electronics"
+	CategoryOther       = "other"
+
+	DepreciationNone = "none"
+	// 直线法：按剩余使用月数平均扣减至残值
+	DepreciationStraightLine = "straight_line"
+	// 余额递减法：每年按固定比例扣减当前价值
+	DepreciationDecliningBalance = "declining_balance"
+
+	DateLayout = "2006-01-02"
+)
+
+type FixedAsset struct {
+	model.TenantBaseModel
+	Name          string `json:"name" gorm:"size:200;not null"`
+	Category      string `json:"category" gorm:"size:20;not null"`
+	PurchasePrice int64  `json:"purchase_price" gorm:"not null"`
+	PurchaseDate  string `json:"purchase_date" gorm:"size:10;not null"`
+	Currency      string `json:"currency" gorm:"size:3"`
+	OwnerUserId   string `json:"owner_user_id" gorm:"size:32;index"`
+	Depreciation  string `json:"depreciation" gorm:"size:20;not null"`
+	// 直线法的使用月数
+	UsefulLifeMonths int `json:"useful_life_months"`
+	// 余额递减法的年折旧率，基点，2000 表示每年 20%
+	AnnualRateBps int   `json:"annual_rate_bps"`
+	SalvageValue  int64 `json:"salvage_value"`
+	// 处置后不再计入资产
+	DisposedDate   string `json:"disposed_date" gorm:"size:10"`
+	DisposalAmount int64  `json:"disposal_amount"`
+	Memo           string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *FixedAsset) TableName() string {
+	return "finance_fixed_asset"
+}
+
+// AssetValuation 手工估值，估值日之后以估值为基础继续折旧
+type AssetValuation struct {
+	model.TenantBaseModel
+	AssetId       string `json:"asset_id" gorm:"size:32;not null;index"`
+	ValuationDate string `json:"valuation_date" gorm:"size:10;not null"`
+	Value         int64  `json:"value" gorm:"not null"`
+	Memo          string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *AssetValuation) TableName() string {
+	return "finance_fixed_asset_valuation"
+}
@@ -0,0 +1,13 @@
+package fixedasset
+
+import "errors"
+
+var (
+	ErrAssetNotFound        = errors.New("资产不存在")
+	ErrAssetDisposed        = errors.New("资产已处置")
+	ErrDateInvalid          = errors.New("日期格式无效，应为YYYY-MM-DD")
+	ErrDateBeforePurchase   = errors.New("日期不能早于购入日期")
+	ErrUsefulLifeRequired   = errors.New("直线法折旧需填写使用月数")
+	ErrAnnualRateRequired   = errors.New("余额递减法折旧需填写年折旧率")
+	ErrSalvageAbovePurchase = errors.New("残值不能高于购入价格")
+)
@@ -0,0 +1,22 @@
+package fixedasset
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建固定资产表
+	if err := db.AutoMigrate(&FixedAsset{}); err != nil {
+		fmt.Println("创建固定资产表失败", err)
+	}
+
+	// 创建资产估值表
+	if err := db.AutoMigrate(&AssetValuation{}); err != nil {
+		fmt.Println("创建资产估值表失败", err)
+	}
+
+	fmt.Println("FixedAsset模块迁移完成")
+}
@@ -0,0 +1,408 @@
+package fixedasset
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+// 不折旧和余额递减法的折旧计划展示年数
+const scheduleYears = 10
+
+type FixedAssetService interface {
+	CreateAsset(ctx context.Context, req *request.DataRequest[CreateFixedAssetDTO]) (*response.DataResponse[FixedAssetDTO], error)
+	UpdateAsset(ctx context.Context, req *request.DataRequest[UpdateFixedAssetDTO]) (*response.DataResponse[FixedAssetDTO], error)
+	ListAssets(ctx context.Context, tenantId string, includeDisposed bool) (*response.DataResponse[[]FixedAssetDTO], error)
+	GetAsset(ctx context.Context, tenantId string, id string) (*response.DataResponse[FixedAssetDetailDTO], error)
+	// AddValuation 录入一次手工估值，之后的价值以该估值为基础继续折旧
+	AddValuation(ctx context.Context, req *request.DataRequest[CreateValuationDTO]) (*response.DataResponse[FixedAssetDTO], error)
+	DisposeAsset(ctx context.Context, req *request.DataRequest[DisposeFixedAssetDTO]) (*response.DataResponse[FixedAssetDTO], error)
+	GetSchedule(ctx context.Context, tenantId string, id string) (*response.DataResponse[[]SchedulePointDTO], error)
+	ValueReader
+}
+
+// ValueReader 供净资产等模块读取资产在某日的价值
+type ValueReader interface {
+	// AssetValues 返回 asOf 当日持有的资产价值，asOf 为空时按今天计算
+	AssetValues(ctx context.Context, tenantId string, asOf string) ([]AssetValue, error)
+}
+
+type service struct {
+	assetRepo     repository.Repository[FixedAsset]
+	valuationRepo repository.Repository[AssetValuation]
+}
+
+func NewFixedAssetService(
+	assetRepo repository.Repository[FixedAsset],
+	valuationRepo repository.Repository[AssetValuation],
+) FixedAssetService {
+	return &service{
+		assetRepo:     assetRepo,
+		valuationRepo: valuationRepo,
+	}
+}
+
+func (s *service) CreateAsset(ctx context.Context, req *request.DataRequest[CreateFixedAssetDTO]) (*response.DataResponse[FixedAssetDTO], error) {
+	if !validDate(req.Data.PurchaseDate) {
+		return nil, ErrDateInvalid
+	}
+	method := req.Data.Depreciation
+	if len(method) == 0 {
+		method = DepreciationNone
+	}
+
+	asset := &FixedAsset{
+		Name:             strings.TrimSpace(req.Data.Name),
+		Category:         req.Data.Category,
+		PurchasePrice:    req.Data.PurchasePrice,
+		PurchaseDate:     req.Data.PurchaseDate,
+		Currency:         strings.ToUpper(req.Data.Currency),
+		OwnerUserId:      req.Data.OwnerUserId,
+		Depreciation:     method,
+		UsefulLifeMonths: req.Data.UsefulLifeMonths,
+		AnnualRateBps:    req.Data.AnnualRateBps,
+		SalvageValue:     req.Data.SalvageValue,
+		Memo:             req.Data.Memo,
+		TenantBaseModel:  model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	if err := validateDepreciation(asset); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assetRepo.Add(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[FixedAssetDTO]{
+		Data: toFixedAssetDTO(asset, nil, today()),
+	}, nil
+}
+
+func (s *service) UpdateAsset(ctx context.Context, req *request.DataRequest[UpdateFixedAssetDTO]) (*response.DataResponse[FixedAssetDTO], error) {
+	asset, valuations, err := s.loadAsset(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		asset.Name = name
+	}
+	if len(req.Data.OwnerUserId) > 0 {
+		asset.OwnerUserId = req.Data.OwnerUserId
+	}
+	if len(req.Data.Depreciation) > 0 {
+		asset.Depreciation = req.Data.Depreciation
+	}
+	if req.Data.UsefulLifeMonths > 0 {
+		asset.UsefulLifeMonths = req.Data.UsefulLifeMonths
+	}
+	if req.Data.AnnualRateBps > 0 {
+		asset.AnnualRateBps = req.Data.AnnualRateBps
+	}
+	if req.Data.SalvageValue != nil {
+		asset.SalvageValue = *req.Data.SalvageValue
+	}
+	if len(req.Data.Memo) > 0 {
+		asset.Memo = req.Data.Memo
+	}
+	if err := validateDepreciation(asset); err != nil {
+		return nil, err
+	}
+
+	asset, err = s.assetRepo.Update(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[FixedAssetDTO]{
+		Data: toFixedAssetDTO(asset, valuations, today()),
+	}, nil
+}
+
+func (s *service) ListAssets(ctx context.Context, tenantId string, includeDisposed bool) (*response.DataResponse[[]FixedAssetDTO], error) {
+	assets, valuationsByAsset, err := s.loadAssets(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := today()
+	dtos := []FixedAssetDTO{}
+	for i := range assets {
+		if !includeDisposed && len(assets[i].DisposedDate) > 0 {
+			continue
+		}
+		dtos = append(dtos, toFixedAssetDTO(&assets[i], valuationsByAsset[assets[i].Id], now))
+	}
+	return &response.DataResponse[[]FixedAssetDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetAsset(ctx context.Context, tenantId string, id string) (*response.DataResponse[FixedAssetDetailDTO], error) {
+	asset, valuations, err := s.loadAsset(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	valuationDTOs := make([]ValuationDTO, 0, len(valuations))
+	for _, valuation := range valuations {
+		valuationDTOs = append(valuationDTOs, ValuationDTO{
+			Id:            valuation.Id,
+			ValuationDate: valuation.ValuationDate,
+			Value:         valuation.Value,
+			Memo:          valuation.Memo,
+		})
+	}
+
+	return &response.DataResponse[FixedAssetDetailDTO]{
+		Data: FixedAssetDetailDTO{
+			Asset:      toFixedAssetDTO(asset, valuations, today()),
+			Valuations: valuationDTOs,
+			Schedule:   schedulePoints(asset, valuations, scheduleYears),
+		},
+	}, nil
+}
+
+func (s *service) AddValuation(ctx context.Context, req *request.DataRequest[CreateValuationDTO]) (*response.DataResponse[FixedAssetDTO], error) {
+	if !validDate(req.Data.ValuationDate) {
+		return nil, ErrDateInvalid
+	}
+	asset, valuations, err := s.loadAsset(ctx, req.Data.TenantId, req.Data.AssetId)
+	if err != nil {
+		return nil, err
+	}
+	if len(asset.DisposedDate) > 0 {
+		return nil, ErrAssetDisposed
+	}
+	if req.Data.ValuationDate < asset.PurchaseDate {
+		return nil, ErrDateBeforePurchase
+	}
+
+	valuation := &AssetValuation{
+		AssetId:         asset.Id,
+		ValuationDate:   req.Data.ValuationDate,
+		Value:           *req.Data.Value,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(asset.TenantId, util.GenerateId()),
+	}
+	valuation, err = s.valuationRepo.Add(ctx, valuation)
+	if err != nil {
+		return nil, err
+	}
+	valuations = append(valuations, *valuation)
+
+	return &response.DataResponse[FixedAssetDTO]{
+		Data: toFixedAssetDTO(asset, valuations, today()),
+	}, nil
+}
+
+func (s *service) DisposeAsset(ctx context.Context, req *request.DataRequest[DisposeFixedAssetDTO]) (*response.DataResponse[FixedAssetDTO], error) {
+	date := req.Data.DisposedDate
+	if len(date) == 0 {
+		date = today().Format(DateLayout)
+	} else if !validDate(date) {
+		return nil, ErrDateInvalid
+	}
+	asset, valuations, err := s.loadAsset(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(asset.DisposedDate) > 0 {
+		return nil, ErrAssetDisposed
+	}
+	if date < asset.PurchaseDate {
+		return nil, ErrDateBeforePurchase
+	}
+
+	asset.DisposedDate = date
+	asset.DisposalAmount = req.Data.DisposalAmount
+	asset, err = s.assetRepo.Update(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[FixedAssetDTO]{
+		Data: toFixedAssetDTO(asset, valuations, today()),
+	}, nil
+}
+
+func (s *service) GetSchedule(ctx context.Context, tenantId string, id string) (*response.DataResponse[[]SchedulePointDTO], error) {
+	asset, valuations, err := s.loadAsset(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[[]SchedulePointDTO]{
+		Data: schedulePoints(asset, valuations, scheduleYears),
+	}, nil
+}
+
+func (s *service) AssetValues(ctx context.Context, tenantId string, asOf string) ([]AssetValue, error) {
+	if len(asOf) == 0 {
+		asOf = today().Format(DateLayout)
+	}
+	assets, valuationsByAsset, err := s.loadAssets(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []AssetValue{}
+	for i := range assets {
+		value, ok := valueAt(&assets[i], valuationsByAsset[assets[i].Id], asOf)
+		if !ok {
+			continue
+		}
+		values = append(values, AssetValue{
+			AssetId:     assets[i].Id,
+			Name:        assets[i].Name,
+			Category:    assets[i].Category,
+			OwnerUserId: assets[i].OwnerUserId,
+			Currency:    assets[i].Currency,
+			Value:       value,
+		})
+	}
+	return values, nil
+}
+
+func (s *service) loadAsset(ctx context.Context, tenantId string, id string) (*FixedAsset, []AssetValuation, error) {
+	asset, err := s.findAssetById(ctx, tenantId, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	valuations, err := s.findValuations(ctx, "asset_id", asset.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(valuations, func(i, j int) bool {
+		return valuations[i].ValuationDate < valuations[j].ValuationDate
+	})
+	return asset, valuations, nil
+}
+
+func (s *service) loadAssets(ctx context.Context, tenantId string) ([]FixedAsset, map[string][]AssetValuation, error) {
+	assets, err := s.findAssets(ctx, tenantId)
+	if err != nil {
+		return nil, nil, err
+	}
+	valuations, err := s.findValuations(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, nil, err
+	}
+	valuationsByAsset := map[string][]AssetValuation{}
+	for _, valuation := range valuations {
+		valuationsByAsset[valuation.AssetId] = append(valuationsByAsset[valuation.AssetId], valuation)
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].PurchaseDate < assets[j].PurchaseDate
+	})
+	return assets, valuationsByAsset, nil
+}
+
+func validateDepreciation(asset *FixedAsset) error {
+	if asset.SalvageValue > asset.PurchasePrice {
+		return ErrSalvageAbovePurchase
+	}
+	switch asset.Depreciation {
+	case DepreciationStraightLine:
+		if asset.UsefulLifeMonths <= 0 {
+			return ErrUsefulLifeRequired
+		}
+	case DepreciationDecliningBalance:
+		if asset.AnnualRateBps <= 0 {
+			return ErrAnnualRateRequired
+		}
+	}
+	return nil
+}
+
+func toFixedAssetDTO(asset *FixedAsset, valuations []AssetValuation, now time.Time) FixedAssetDTO {
+	dto := FixedAssetDTO{
+		Id:               asset.Id,
+		TenantId:         asset.TenantId,
+		Name:             asset.Name,
+		Category:         asset.Category,
+		PurchasePrice:    asset.PurchasePrice,
+		PurchaseDate:     asset.PurchaseDate,
+		Currency:         asset.Currency,
+		OwnerUserId:      asset.OwnerUserId,
+		Depreciation:     asset.Depreciation,
+		UsefulLifeMonths: asset.UsefulLifeMonths,
+		AnnualRateBps:    asset.AnnualRateBps,
+		SalvageValue:     asset.SalvageValue,
+		DisposedDate:     asset.DisposedDate,
+		DisposalAmount:   asset.DisposalAmount,
+		Memo:             asset.Memo,
+	}
+	dto.CurrentValue, _ = valueAt(asset, valuations, now.Format(DateLayout))
+	for _, valuation := range valuations {
+		if valuation.ValuationDate > dto.LastValuationDate {
+			dto.LastValuationDate = valuation.ValuationDate
+		}
+	}
+	return dto
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func (s *service) findAssetById(ctx context.Context, tenantId string, id string) (*FixedAsset, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	assets, err := s.assetRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 {
+		return nil, ErrAssetNotFound
+	}
+	return &assets[0], nil
+}
+
+func (s *service) findAssets(ctx context.Context, tenantId string) ([]FixedAsset, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1000,
+		PageNumber:  1,
+	}
+	return s.assetRepo.Query(ctx, query)
+}
+
+func (s *service) findValuations(ctx context.Context, field string, value string) ([]AssetValuation, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.valuationRepo.Query(ctx, query)
+}
@@ -0,0 +1,79 @@
+package goal
+
+type GoalDTO struct {
+	Id            string          `json:"id"`
+	TenantId      string          `json:"tenant_id"`
+	Name          string          `json:"name"`
+	TargetAmount  int64           `json:"target_amount"`
+	Currency      string          `json:"currency"`
+	TargetDate    string          `json:"target_date"`
+	StartDate     string          `json:"start_date"`
+	OwnerUserId   string          `json:"owner_user_id"`
+	Status        string          `json:"status"`
+	InitialAmount int64           `json:"initial_amount"`
+	SavedAmount   int64           `json:"saved_amount"`
+	Memo          string          `json:"memo"`
+	Progress      GoalProgressDTO `json:"progress"`
+}
+
+type GoalProgressDTO struct {
+	RemainingAmount int64 `json:"remaining_amount"`
+	// 完成百分比，保留两位小数
+	Percent float64 `json:"percent"`
+	// 距目标日期剩余月数，不限期时为 0
+	MonthsLeft int `json:"months_left"`
+	// 按期完成每月还需存入的金额
+	RequiredMonthly int64 `json:"required_monthly"`
+	// 最近几个月的平均月存入
+	RecentMonthlyRate int64 `json:"recent_monthly_rate"`
+	// 按最近存入速度预计的完成日期，无法预计时为空
+	ProjectedDate string `json:"projected_date"`
+	OnTrack       bool   `json:"on_track"`
+}
+
+type CreateGoalDTO struct {
+	TenantId     string `json:"tenant_id" binding:"required"`
+	Name         string `json:"name" binding:"required,max_len=200"`
+	TargetAmount int64  `json:"target_amount" binding:"required,min=1"`
+	Currency     string `json:"currency" binding:"omitempty,len=3"`
+	TargetDate   string `json:"target_date" binding:"omitempty"`
+	OwnerUserId  string `json:"owner_user_id" binding:"omitempty"`
+	// 已有的起始金额
+	InitialAmount int64  `json:"initial_amount" binding:"omitempty,min=0"`
+	Memo          string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type UpdateGoalDTO struct {
+	TenantId     string `json:"tenant_id" binding:"required"`
+	Id           string `json:"id"`
+	Name         string `json:"name" binding:"omitempty,max_len=200"`
+	TargetAmount int64  `json:"target_amount" binding:"omitempty,min=1"`
+	TargetDate   string `json:"target_date" binding:"omitempty"`
+	Memo         string `json:"memo" binding:"omitempty,max_len=500"`
+	Archived     *bool  `json:"archived" binding:"omitempty"`
+}
+
+type GoalContributionDTO struct {
+	Id     string `json:"id"`
+	GoalId string `json:"goal_id"`
+	UserId string `json:"user_id"`
+	Amount int64  `json:"amount"`
+	Date   string `json:"date"`
+	Memo   string `json:"memo"`
+}
+
+type CreateContributionDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	GoalId   string `json:"goal_id"`
+	UserId   string `json:"user_id" binding:"omitempty"`
+	// 负数表示从目标中取出
+	Amount int64 `json:"amount" binding:"required"`
+	// 为空时为今天
+	Date string `json:"date" binding:"omitempty"`
+	Memo string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type GoalDetailDTO struct {
+	Goal          GoalDTO               `json:"goal"`
+	Contributions []GoalContributionDTO `json:"contributions"`
+}
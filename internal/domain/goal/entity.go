@@ -0,0 +1,48 @@
+package goal
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	GoalStatusActive   = "active"
+	GoalStatusAchieved = "achieved"
+	GoalStatusArchived = "archived"
+
+	DateLayout = "2006-01-02"
+)
+
+type Goal struct {
+	model.TenantBaseModel
+	Name         string `json:"name" gorm:"size:200;not null"`
+	TargetAmount int64  `json:"target_amount" gorm:"not null"`
+	Currency     string `json:"currency" gorm:"size:3"`
+	// 目标日期为空表示不限期
+	TargetDate  string `json:"target_date" gorm:"size:10"`
+	StartDate   string `json:"start_date" gorm:"size:10;not null"`
+	OwnerUserId string `json:"owner_user_id" gorm:"size:32;index"`
+	Status      string `json:"status" gorm:"size:20;not null;index"`
+	// 创建时已有的金额，不计入近期存入速度
+	InitialAmount int64 `json:"initial_amount"`
+	// 已存金额，为起始金额加上全部存入记录
+	SavedAmount int64  `json:"saved_amount"`
+	Memo        string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Goal) TableName() string {
+	return "finance_goal"
+}
+
+// GoalContribution 成员向目标存入（负数为取出）的记录
+type GoalContribution struct {
+	model.TenantBaseModel
+	GoalId string `json:"goal_id" gorm:"size:32;not null;index"`
+	UserId string `json:"user_id" gorm:"size:32;index"`
+	Amount int64  `json:"amount" gorm:"not null"`
+	Date   string `json:"date" gorm:"size:10;not null"`
+	Memo   string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *GoalContribution) TableName() string {
+	return "finance_goal_contribution"
+}
@@ -0,0 +1,13 @@
+package goal
+
+import "errors"
+
+var (
+	ErrGoalNotFound           = errors.New("储蓄目标不存在")
+	ErrGoalArchived           = errors.New("储蓄目标已归档")
+	ErrTargetDateInvalid      = errors.New("目标日期格式无效，应为YYYY-MM-DD")
+	ErrTargetDateTooEarly     = errors.New("目标日期不能早于开始日期")
+	ErrContributionDate       = errors.New("存入日期格式无效，应为YYYY-MM-DD")
+	ErrContributionZero       = errors.New("存入金额不能为0")
+	ErrWithdrawalExceedsSaved = errors.New("取出金额不能超过已存金额")
+)
@@ -0,0 +1,22 @@
+package goal
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建储蓄目标表
+	if err := db.AutoMigrate(&Goal{}); err != nil {
+		fmt.Println("创建储蓄目标表失败", err)
+	}
+
+	// 创建目标存入记录表
+	if err := db.AutoMigrate(&GoalContribution{}); err != nil {
+		fmt.Println("创建目标存入记录表失败", err)
+	}
+
+	fmt.Println("Goal模块迁移完成")
+}
@@ -0,0 +1,75 @@
+package goal
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// 计算近期存入速度的月数
+	recentRateMonths = 3
+
+	daysPerMonth = 365.25 / 12
+)
+
+// computeProgress 根据已存金额和存入记录计算目标进度与预计完成日期
+func computeProgress(goal *Goal, contributions []GoalContribution, now time.Time) GoalProgressDTO {
+	progress := GoalProgressDTO{
+		RemainingAmount: max(goal.TargetAmount-goal.SavedAmount, 0),
+	}
+	if goal.TargetAmount > 0 {
+		percent := float64(goal.SavedAmount) / float64(goal.TargetAmount) * 100
+		progress.Percent = math.Round(min(max(percent, 0), 100)*100) / 100
+	}
+
+	// 近期平均月存入，目标创建不足窗口期时按实际月数计算
+	windowStart := now.AddDate(0, -recentRateMonths, 0)
+	if start, err := time.Parse(DateLayout, goal.StartDate); err == nil && start.After(windowStart) {
+		windowStart = start
+	}
+	var recent int64
+	for _, c := range contributions {
+		date, err := time.Parse(DateLayout, c.Date)
+		if err != nil || date.Before(windowStart) || date.After(now) {
+			continue
+		}
+		recent += c.Amount
+	}
+	months := max(now.Sub(windowStart).Hours()/24/daysPerMonth, 1)
+	progress.RecentMonthlyRate = int64(math.Round(float64(recent) / months))
+
+	if progress.RemainingAmount == 0 {
+		progress.OnTrack = true
+		return progress
+	}
+
+	if target, err := time.Parse(DateLayout, goal.TargetDate); err == nil {
+		progress.MonthsLeft = monthsBetween(now, target)
+		progress.RequiredMonthly = ceilDiv(progress.RemainingAmount, int64(max(progress.MonthsLeft, 1)))
+	}
+
+	if progress.RecentMonthlyRate > 0 {
+		need := ceilDiv(progress.RemainingAmount, progress.RecentMonthlyRate)
+		projected := now.AddDate(0, int(need), 0)
+		progress.ProjectedDate = projected.Format(DateLayout)
+		if len(goal.TargetDate) == 0 {
+			progress.OnTrack = true
+		} else {
+			progress.OnTrack = progress.ProjectedDate <= goal.TargetDate
+		}
+	}
+	return progress
+}
+
+// monthsBetween 返回从 from 到 to 的月数，不足一月按一月计，已过期为 0
+func monthsBetween(from, to time.Time) int {
+	days := to.Sub(from).Hours() / 24
+	if days <= 0 {
+		return 0
+	}
+	return int(math.Ceil(days / daysPerMonth))
+}
+
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,349 @@
+package goal
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+type GoalService interface {
+	CreateGoal(ctx context.Context, req *request.DataRequest[CreateGoalDTO]) (*response.DataResponse[GoalDTO], error)
+	UpdateGoal(ctx context.Context, req *request.DataRequest[UpdateGoalDTO]) (*response.DataResponse[GoalDTO], error)
+	ListGoals(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]GoalDTO], error)
+	GetGoal(ctx context.Context, tenantId string, id string) (*response.DataResponse[GoalDetailDTO], error)
+	AddContribution(ctx context.Context, req *request.DataRequest[CreateContributionDTO]) (*response.DataResponse[GoalDTO], error)
+}
+
+type service struct {
+	goalRepo         repository.Repository[Goal]
+	contributionRepo repository.Repository[GoalContribution]
+}
+
+func NewGoalService(goalRepo repository.Repository[Goal], contributionRepo repository.Repository[GoalContribution]) GoalService {
+	return &service{
+		goalRepo:         goalRepo,
+		contributionRepo: contributionRepo,
+	}
+}
+
+func (s *service) CreateGoal(ctx context.Context, req *request.DataRequest[CreateGoalDTO]) (*response.DataResponse[GoalDTO], error) {
+	now := today()
+	startDate := now.Format(DateLayout)
+	if len(req.Data.TargetDate) > 0 {
+		if !validDate(req.Data.TargetDate) {
+			return nil, ErrTargetDateInvalid
+		}
+		if req.Data.TargetDate < startDate {
+			return nil, ErrTargetDateTooEarly
+		}
+	}
+
+	goal := &Goal{
+		Name:            strings.TrimSpace(req.Data.Name),
+		TargetAmount:    req.Data.TargetAmount,
+		Currency:        strings.ToUpper(req.Data.Currency),
+		TargetDate:      req.Data.TargetDate,
+		StartDate:       startDate,
+		OwnerUserId:     req.Data.OwnerUserId,
+		Status:          GoalStatusActive,
+		InitialAmount:   req.Data.InitialAmount,
+		SavedAmount:     req.Data.InitialAmount,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	refreshStatus(goal)
+
+	goal, err := s.goalRepo.Add(ctx, goal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[GoalDTO]{
+		Data: toGoalDTO(goal, nil, now),
+	}, nil
+}
+
+func (s *service) UpdateGoal(ctx context.Context, req *request.DataRequest[UpdateGoalDTO]) (*response.DataResponse[GoalDTO], error) {
+	goal, err := s.findGoalById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		goal.Name = name
+	}
+	if len(req.Data.Memo) > 0 {
+		goal.Memo = req.Data.Memo
+	}
+	if len(req.Data.TargetDate) > 0 {
+		if !validDate(req.Data.TargetDate) {
+			return nil, ErrTargetDateInvalid
+		}
+		if req.Data.TargetDate < goal.StartDate {
+			return nil, ErrTargetDateTooEarly
+		}
+		goal.TargetDate = req.Data.TargetDate
+	}
+	if req.Data.TargetAmount > 0 {
+		goal.TargetAmount = req.Data.TargetAmount
+	}
+	if req.Data.Archived != nil {
+		if *req.Data.Archived {
+			goal.Status = GoalStatusArchived
+		} else {
+			goal.Status = GoalStatusActive
+		}
+	}
+	refreshStatus(goal)
+
+	goal, err = s.goalRepo.Update(ctx, goal)
+	if err != nil {
+		return nil, err
+	}
+	contributions, err := s.findContributions(ctx, "goal_id", goal.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[GoalDTO]{
+		Data: toGoalDTO(goal, contributions, today()),
+	}, nil
+}
+
+func (s *service) ListGoals(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]GoalDTO], error) {
+	goals, err := s.findGoals(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	contributions, err := s.findContributions(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	byGoal := map[string][]GoalContribution{}
+	for _, c := range contributions {
+		byGoal[c.GoalId] = append(byGoal[c.GoalId], c)
+	}
+
+	sort.Slice(goals, func(i, j int) bool {
+		if goals[i].TargetDate != goals[j].TargetDate {
+			// 不限期的目标排在最后
+			if len(goals[i].TargetDate) == 0 || len(goals[j].TargetDate) == 0 {
+				return len(goals[j].TargetDate) == 0
+			}
+			return goals[i].TargetDate < goals[j].TargetDate
+		}
+		return goals[i].Name < goals[j].Name
+	})
+
+	now := today()
+	dtos := []GoalDTO{}
+	for i := range goals {
+		if len(status) > 0 && goals[i].Status != status {
+			continue
+		}
+		dtos = append(dtos, toGoalDTO(&goals[i], byGoal[goals[i].Id], now))
+	}
+	return &response.DataResponse[[]GoalDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetGoal(ctx context.Context, tenantId string, id string) (*response.DataResponse[GoalDetailDTO], error) {
+	goal, err := s.findGoalById(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+	contributions, err := s.findContributions(ctx, "goal_id", goal.Id)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		return contributions[i].Date > contributions[j].Date
+	})
+
+	dtos := make([]GoalContributionDTO, 0, len(contributions))
+	for _, c := range contributions {
+		dtos = append(dtos, GoalContributionDTO{
+			Id:     c.Id,
+			GoalId: c.GoalId,
+			UserId: c.UserId,
+			Amount: c.Amount,
+			Date:   c.Date,
+			Memo:   c.Memo,
+		})
+	}
+
+	return &response.DataResponse[GoalDetailDTO]{
+		Data: GoalDetailDTO{
+			Goal:          toGoalDTO(goal, contributions, today()),
+			Contributions: dtos,
+		},
+	}, nil
+}
+
+func (s *service) AddContribution(ctx context.Context, req *request.DataRequest[CreateContributionDTO]) (*response.DataResponse[GoalDTO], error) {
+	if req.Data.Amount == 0 {
+		return nil, ErrContributionZero
+	}
+	date := req.Data.Date
+	if len(date) == 0 {
+		date = today().Format(DateLayout)
+	} else if !validDate(date) {
+		return nil, ErrContributionDate
+	}
+	goal, err := s.findGoalById(ctx, req.Data.TenantId, req.Data.GoalId)
+	if err != nil {
+		return nil, err
+	}
+	if goal.Status == GoalStatusArchived {
+		return nil, ErrGoalArchived
+	}
+
+	goal, contributions, err := s.addContribution(ctx, goal, req.Data.UserId, req.Data.Amount, date, req.Data.Memo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[GoalDTO]{
+		Data: toGoalDTO(goal, contributions, today()),
+	}, nil
+}
+
+// addContribution 写入存取记录后按全部记录重新汇总已存金额，避免并发存取互相覆盖
+func (s *service) addContribution(ctx context.Context, goal *Goal, userId string, amount int64, date string, memo string) (*Goal, []GoalContribution, error) {
+	if amount < 0 {
+		contributions, err := s.findContributions(ctx, "goal_id", goal.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if savedAmount(goal, contributions)+amount < 0 {
+			return nil, nil, ErrWithdrawalExceedsSaved
+		}
+	}
+	contribution := &GoalContribution{
+		GoalId:          goal.Id,
+		UserId:          userId,
+		Amount:          amount,
+		Date:            date,
+		Memo:            memo,
+		TenantBaseModel: model.NewTenantBaseModel(goal.TenantId, util.GenerateId()),
+	}
+	if _, err := s.contributionRepo.Add(ctx, contribution); err != nil {
+		return nil, nil, err
+	}
+
+	contributions, err := s.findContributions(ctx, "goal_id", goal.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	goal.SavedAmount = savedAmount(goal, contributions)
+	refreshStatus(goal)
+	goal, err = s.goalRepo.Update(ctx, goal)
+	if err != nil {
+		return nil, nil, err
+	}
+	return goal, contributions, nil
+}
+
+// savedAmount 已存金额为起始金额加上全部存取记录
+func savedAmount(goal *Goal, contributions []GoalContribution) int64 {
+	saved := goal.InitialAmount
+	for _, c := range contributions {
+		saved += c.Amount
+	}
+	return saved
+}
+
+// refreshStatus 已存金额达到目标时标记为已完成，取出后回到进行中；已归档的目标不变
+func refreshStatus(goal *Goal) {
+	if goal.Status == GoalStatusArchived {
+		return
+	}
+	if goal.SavedAmount >= goal.TargetAmount {
+		goal.Status = GoalStatusAchieved
+	} else {
+		goal.Status = GoalStatusActive
+	}
+}
+
+func toGoalDTO(goal *Goal, contributions []GoalContribution, now time.Time) GoalDTO {
+	return GoalDTO{
+		Id:            goal.Id,
+		TenantId:      goal.TenantId,
+		Name:          goal.Name,
+		TargetAmount:  goal.TargetAmount,
+		Currency:      goal.Currency,
+		TargetDate:    goal.TargetDate,
+		StartDate:     goal.StartDate,
+		OwnerUserId:   goal.OwnerUserId,
+		Status:        goal.Status,
+		InitialAmount: goal.InitialAmount,
+		SavedAmount:   goal.SavedAmount,
+		Memo:          goal.Memo,
+		Progress:      computeProgress(goal, contributions, now),
+	}
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func (s *service) findGoalById(ctx context.Context, tenantId string, id string) (*Goal, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	goals, err := s.goalRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(goals) == 0 {
+		return nil, ErrGoalNotFound
+	}
+	return &goals[0], nil
+}
+
+func (s *service) findGoals(ctx context.Context, field string, value string) ([]Goal, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1000,
+		PageNumber:  1,
+	}
+	return s.goalRepo.Query(ctx, query)
+}
+
+func (s *service) findContributions(ctx context.Context, field string, value string) ([]GoalContribution, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.contributionRepo.Query(ctx, query)
+}
@@ -0,0 +1,132 @@
+package insurance
+
+import (
+	"github.com/loongkirin/go-family-finance/internal/domain/reimbursement"
+)
+
+type PolicyDTO struct {
+	Id               string   `json:"id"`
+	TenantId         string   `json:"tenant_id"`
+	Name             string   `json:"name"`
+	Category         string   `json:"category"`
+	Insurer          string   `json:"insurer"`
+	PolicyNumber     string   `json:"policy_number"`
+	HolderUserId     string   `json:"holder_user_id"`
+	InsuredUserId    string   `json:"insured_user_id"`
+	InsuredName      string   `json:"insured_name"`
+	Beneficiary      string   `json:"beneficiary"`
+	CoverageAmount   int64    `json:"coverage_amount"`
+	CoverageMemo     string   `json:"coverage_memo"`
+	PremiumAmount    int64    `json:"premium_amount"`
+	Currency         string   `json:"currency"`
+	PremiumFrequency string   `json:"premium_frequency"`
+	FirstPremiumDate string   `json:"first_premium_date"`
+	PremiumEndDate   string   `json:"premium_end_date"`
+	NextPremiumDate  string   `json:"next_premium_date"`
+	AnnualPremium    int64    `json:"annual_premium"`
+	StartDate        string   `json:"start_date"`
+	EndDate          string   `json:"end_date"`
+	RenewalDate      string   `json:"renewal_date"`
+	RemindDaysBefore int      `json:"remind_days_before"`
+	NotifyUserIds    []string `json:"notify_user_ids"`
+	Status           string   `json:"status"`
+	Memo             string   `json:"memo"`
+}
+
+type CreatePolicyDTO struct {
+	TenantId         string `json:"tenant_id" binding:"required"`
+	Name             string `json:"name" binding:"required,max_len=200"`
+	Category         string `json:"category" binding:"required,oneof=health critical_illness life accident car property other"`
+	Insurer          string `json:"insurer" binding:"required,max_len=100"`
+	PolicyNumber     string `json:"policy_number" binding:"omitempty,max_len=100"`
+	HolderUserId     string `json:"holder_user_id" binding:"omitempty"`
+	InsuredUserId    string `json:"insured_user_id" binding:"omitempty"`
+	InsuredName      string `json:"insured_name" binding:"omitempty,max_len=100"`
+	Beneficiary      string `json:"beneficiary" binding:"omitempty,max_len=200"`
+	CoverageAmount   int64  `json:"coverage_amount" binding:"omitempty,min=0"`
+	CoverageMemo     string `json:"coverage_memo" binding:"omitempty,max_len=500"`
+	PremiumAmount    int64  `json:"premium_amount" binding:"required,min=1"`
+	Currency         string `json:"currency" binding:"omitempty,len=3"`
+	PremiumFrequency string `json:"premium_frequency" binding:"required,oneof=monthly quarterly semi_annual annual single"`
+	// 为空时为保障开始日期
+	FirstPremiumDate string   `json:"first_premium_date" binding:"omitempty"`
+	PremiumEndDate   string   `json:"premium_end_date" binding:"omitempty"`
+	StartDate        string   `json:"start_date" binding:"required"`
+	EndDate          string   `json:"end_date" binding:"omitempty"`
+	RenewalDate      string   `json:"renewal_date" binding:"omitempty"`
+	RemindDaysBefore int      `json:"remind_days_before" binding:"omitempty,min=0,max=60"`
+	NotifyUserIds    []string `json:"notify_user_ids" binding:"omitempty"`
+	Memo             string   `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type UpdatePolicyDTO struct {
+	TenantId         string   `json:"tenant_id" binding:"required"`
+	Id               string   `json:"id"`
+	Name             string   `json:"name" binding:"omitempty,max_len=200"`
+	PolicyNumber     string   `json:"policy_number" binding:"omitempty,max_len=100"`
+	Beneficiary      string   `json:"beneficiary" binding:"omitempty,max_len=200"`
+	CoverageAmount   int64    `json:"coverage_amount" binding:"omitempty,min=0"`
+	CoverageMemo     string   `json:"coverage_memo" binding:"omitempty,max_len=500"`
+	PremiumAmount    int64    `json:"premium_amount" binding:"omitempty,min=1"`
+	RemindDaysBefore *int     `json:"remind_days_before" binding:"omitempty,min=0,max=60"`
+	NotifyUserIds    []string `json:"notify_user_ids" binding:"omitempty"`
+	// 退保后不再生成保费账单和续保提醒
+	Status string `json:"status" binding:"omitempty,oneof=active cancelled"`
+	Memo   string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type RenewPolicyDTO struct {
+	TenantId    string `json:"tenant_id" binding:"required"`
+	Id          string `json:"id"`
+	RenewalDate string `json:"renewal_date" binding:"required"`
+	// 续保后的保费，为空时不变
+	PremiumAmount int64 `json:"premium_amount" binding:"omitempty,min=1"`
+	// 续保后的保障结束日期，为空时不变
+	EndDate string `json:"end_date" binding:"omitempty"`
+}
+
+type CreatePolicyClaimDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	PolicyId string `json:"policy_id"`
+	// 关联已有的报销单，为空时按下列字段新建报销单
+	ClaimId      string `json:"claim_id" binding:"omitempty"`
+	IncidentDate string `json:"incident_date" binding:"omitempty"`
+	Description  string `json:"description" binding:"omitempty,max_len=500"`
+	// 为空时为被保险人，被保险人不是家庭成员时为投保人
+	UserId      string `json:"user_id" binding:"omitempty"`
+	Title       string `json:"title" binding:"omitempty,max_len=200"`
+	ExpenseDate string `json:"expense_date" binding:"omitempty"`
+	Amount      int64  `json:"amount" binding:"omitempty,min=1"`
+	SourceType  string `json:"source_type" binding:"omitempty,max_len=50"`
+	SourceId    string `json:"source_id" binding:"omitempty"`
+}
+
+type PolicyClaimDTO struct {
+	Id           string                 `json:"id"`
+	PolicyId     string                 `json:"policy_id"`
+	IncidentDate string                 `json:"incident_date"`
+	Description  string                 `json:"description"`
+	Claim        reimbursement.ClaimDTO `json:"claim"`
+}
+
+type PolicyDetailDTO struct {
+	Policy PolicyDTO        `json:"policy"`
+	Claims []PolicyClaimDTO `json:"claims"`
+	// 未来一年的缴费日期
+	UpcomingPremiums []string `json:"upcoming_premiums"`
+}
+
+type PremiumTotalDTO struct {
+	InsuredUserId string `json:"insured_user_id"`
+	InsuredName   string `json:"insured_name"`
+	Currency      string `json:"currency"`
+	PolicyCount   int    `json:"policy_count"`
+	AnnualPremium int64  `json:"annual_premium"`
+}
+
+// PremiumSummaryDTO 有效保单按年折算的保费，趸交保单不计入
+type PremiumSummaryDTO struct {
+	ByInsured []PremiumTotalDTO `json:"by_insured"`
+	// 按币种合计，不区分被保险人
+	Totals []PremiumTotalDTO `json:"totals"`
+}
@@ -0,0 +1,81 @@
+package insurance
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	CategoryHealth          = "health"
+	CategoryCriticalIllness = "critical_illness"
+	CategoryLife            = "life"
+	CategoryAccident        = "accident"
+	CategoryCar             = "car"
+	CategoryProperty        = "property"
+	CategoryOther           = "other"
+
+	FrequencyMonthly    = "monthly"
+	FrequencyQuarterly  = "quarterly"
+	FrequencySemiAnnual = "semi_annual"
+	FrequencyAnnual     = "annual"
+	// 趸交，一次性缴清
+	FrequencySingle = "single"
+
+	PolicyStatusActive    = "active"
+	PolicyStatusCancelled = "cancelled"
+
+	// 上传保单附件时使用的 owner_type
+	AttachmentOwnerType = "insurance_policy"
+
+	DateLayout = "2006-01-02"
+)
+
+type Policy struct {
+	model.TenantBaseModel
+	Name         string `json:"name" gorm:"size:200;not null"`
+	Category     string `json:"category" gorm:"size:30;not null"`
+	Insurer      string `json:"insurer" gorm:"size:100;not null"`
+	PolicyNumber string `json:"policy_number" gorm:"size:100"`
+	// 投保人
+	HolderUserId string `json:"holder_user_id" gorm:"size:32;index"`
+	// 被保险人为家庭成员时填写用户 ID，否则填写姓名
+	InsuredUserId    string `json:"insured_user_id" gorm:"size:32;index"`
+	InsuredName      string `json:"insured_name" gorm:"size:100"`
+	Beneficiary      string `json:"beneficiary" gorm:"size:200"`
+	CoverageAmount   int64  `json:"coverage_amount"`
+	CoverageMemo     string `json:"coverage_memo" gorm:"size:500"`
+	PremiumAmount    int64  `json:"premium_amount" gorm:"not null"`
+	Currency         string `json:"currency" gorm:"size:3"`
+	PremiumFrequency string `json:"premium_frequency" gorm:"size:20;not null"`
+	FirstPremiumDate string `json:"first_premium_date" gorm:"size:10;not null"`
+	// 最后一期缴费日期，为空时缴费至保障结束
+	PremiumEndDate string `json:"premium_end_date" gorm:"size:10"`
+	// 已生成账单的期数，下一期缴费日由首期缴费日和期数推算
+	BilledPremiums int    `json:"billed_premiums"`
+	StartDate      string `json:"start_date" gorm:"size:10;not null"`
+	EndDate        string `json:"end_date" gorm:"size:10"`
+	// 需要续保的保单填写，如一年期医疗险和车险
+	RenewalDate         string `json:"renewal_date" gorm:"size:10;index"`
+	RenewalRemindedDate string `json:"renewal_reminded_date" gorm:"size:10"`
+	// 保费账单提前几天提醒
+	RemindDaysBefore int      `json:"remind_days_before"`
+	NotifyUserIds    []string `json:"notify_user_ids" gorm:"type:text;serializer:json"`
+	Status           string   `json:"status" gorm:"size:20;not null;index"`
+	Memo             string   `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Policy) TableName() string {
+	return "finance_insurance_policy"
+}
+
+// PolicyClaim 保单理赔，赔款的申请和到账由报销模块跟踪
+type PolicyClaim struct {
+	model.TenantBaseModel
+	PolicyId     string `json:"policy_id" gorm:"size:32;not null;index"`
+	ClaimId      string `json:"claim_id" gorm:"size:32;not null;index"`
+	IncidentDate string `json:"incident_date" gorm:"size:10"`
+	Description  string `json:"description" gorm:"size:500"`
+}
+
+func (entity *PolicyClaim) TableName() string {
+	return "finance_insurance_claim"
+}
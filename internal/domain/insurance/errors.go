@@ -0,0 +1,15 @@
+package insurance
+
+import "errors"
+
+var (
+	ErrPolicyNotFound      = errors.New("保单不存在")
+	ErrPolicyCancelled     = errors.New("保单已退保")
+	ErrDateInvalid         = errors.New("日期格式无效，应为YYYY-MM-DD")
+	ErrEndBeforeStart      = errors.New("结束日期不能早于开始日期")
+	ErrInsuredRequired     = errors.New("请填写被保险人成员或姓名")
+	ErrRenewalDateNotLater = errors.New("新的续保日期需晚于当前续保日期")
+	ErrClaimAlreadyLinked  = errors.New("该报销单已关联理赔")
+	ErrClaimAmountRequired = errors.New("请填写理赔金额")
+	ErrClaimantRequired    = errors.New("请填写理赔申请成员")
+)
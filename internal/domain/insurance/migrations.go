@@ -0,0 +1,22 @@
+package insurance
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建保单表
+	if err := db.AutoMigrate(&Policy{}); err != nil {
+		fmt.Println("创建保单表失败", err)
+	}
+
+	// 创建保单理赔表
+	if err := db.AutoMigrate(&PolicyClaim{}); err != nil {
+		fmt.Println("创建保单理赔表失败", err)
+	}
+
+	fmt.Println("Insurance模块迁移完成")
+}
@@ -0,0 +1,61 @@
+package insurance
+
+import (
+	"time"
+)
+
+// 每种缴费频率两期之间的月数，趸交为 0
+var frequencyMonths = map[string]int{
+	FrequencyMonthly:    1,
+	FrequencyQuarterly:  3,
+	FrequencySemiAnnual: 6,
+	FrequencyAnnual:     12,
+	FrequencySingle:     0,
+}
+
+// premiumDate 返回第 n 期（从 0 开始）的缴费日期，没有该期时返回 false
+func premiumDate(policy *Policy, n int) (string, bool) {
+	first, err := time.Parse(DateLayout, policy.FirstPremiumDate)
+	if err != nil {
+		return "", false
+	}
+	months := frequencyMonths[policy.PremiumFrequency]
+	if n > 0 && months == 0 {
+		return "", false
+	}
+	date := addMonths(first, n*months).Format(DateLayout)
+	last := policy.PremiumEndDate
+	if len(last) == 0 {
+		last = policy.EndDate
+	}
+	if len(last) > 0 && date > last {
+		return "", false
+	}
+	return date, true
+}
+
+// nextPremiumDate 返回尚未生成账单的下一期缴费日期
+func nextPremiumDate(policy *Policy) string {
+	if policy.Status != PolicyStatusActive {
+		return ""
+	}
+	date, _ := premiumDate(policy, policy.BilledPremiums)
+	return date
+}
+
+// annualPremium 按缴费频率折算的年保费，趸交不计入
+func annualPremium(policy *Policy) int64 {
+	months := frequencyMonths[policy.PremiumFrequency]
+	if months == 0 {
+		return 0
+	}
+	return policy.PremiumAmount * int64(12/months)
+}
+
+// addMonths 按月顺延，目标月份没有对应日期时取月末
+func addMonths(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	target := time.Date(y, m+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := target.AddDate(0, 1, -1).Day()
+	return time.Date(target.Year(), target.Month(), min(d, last), 0, 0, 0, 0, time.UTC)
+}
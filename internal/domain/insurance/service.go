@@ -0,0 +1,688 @@
+package insurance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/bill"
+	"github.com/loongkirin/go-family-finance/internal/domain/notification"
+	"github.com/loongkirin/go-family-finance/internal/domain/reimbursement"
+)
+
+const (
+	NotificationCategory = "insurance_renewal"
+	// 保费账单的来源类型
+	BillSourcePolicy = "insurance_policy"
+
+	// 提前生成保费账单的天数
+	premiumBillDaysAhead = 30
+	// 提前提醒续保的天数
+	renewalRemindDays = 30
+
+	policyPageSize = 500
+)
+
+type InsuranceService interface {
+	CreatePolicy(ctx context.Context, req *request.DataRequest[CreatePolicyDTO]) (*response.DataResponse[PolicyDTO], error)
+	UpdatePolicy(ctx context.Context, req *request.DataRequest[UpdatePolicyDTO]) (*response.DataResponse[PolicyDTO], error)
+	// ListPolicies 按被保险人和状态筛选保单，参数为空时不筛选
+	ListPolicies(ctx context.Context, tenantId string, insuredUserId string, status string) (*response.DataResponse[[]PolicyDTO], error)
+	GetPolicy(ctx context.Context, tenantId string, id string) (*response.DataResponse[PolicyDetailDTO], error)
+	// RenewPolicy 登记续保，更新续保日期并重新开始续保提醒
+	RenewPolicy(ctx context.Context, req *request.DataRequest[RenewPolicyDTO]) (*response.DataResponse[PolicyDTO], error)
+	// CreateClaim 登记一次理赔，关联已有报销单或新建一张由保险公司报销的报销单
+	CreateClaim(ctx context.Context, req *request.DataRequest[CreatePolicyClaimDTO]) (*response.DataResponse[PolicyClaimDTO], error)
+	GetPremiumSummary(ctx context.Context, tenantId string) (*response.DataResponse[PremiumSummaryDTO], error)
+	// GeneratePremiumBills 为未来 30 天内到期的保费生成账单，可重复执行
+	GeneratePremiumBills(ctx context.Context, asOf time.Time) (int, error)
+	// SendRenewalReminders 发送续保提醒，可重复执行
+	SendRenewalReminders(ctx context.Context, asOf time.Time) (int, error)
+}
+
+type service struct {
+	policyRepo     repository.Repository[Policy]
+	claimRepo      repository.Repository[PolicyClaim]
+	bills          bill.BillService
+	reimbursements reimbursement.ReimbursementService
+	notifier       notification.Notifier
+	members        auth.MemberDirectory
+}
+
+func NewInsuranceService(
+	policyRepo repository.Repository[Policy],
+	claimRepo repository.Repository[PolicyClaim],
+	bills bill.BillService,
+	reimbursements reimbursement.ReimbursementService,
+	notifier notification.Notifier,
+	members auth.MemberDirectory,
+) InsuranceService {
+	return &service{
+		policyRepo:     policyRepo,
+		claimRepo:      claimRepo,
+		bills:          bills,
+		reimbursements: reimbursements,
+		notifier:       notifier,
+		members:        members,
+	}
+}
+
+func (s *service) CreatePolicy(ctx context.Context, req *request.DataRequest[CreatePolicyDTO]) (*response.DataResponse[PolicyDTO], error) {
+	insuredName := strings.TrimSpace(req.Data.InsuredName)
+	if len(req.Data.InsuredUserId) == 0 && len(insuredName) == 0 {
+		return nil, ErrInsuredRequired
+	}
+	firstPremiumDate := req.Data.FirstPremiumDate
+	if len(firstPremiumDate) == 0 {
+		firstPremiumDate = req.Data.StartDate
+	}
+	if !validDate(req.Data.StartDate) || !validDate(firstPremiumDate) ||
+		!validOptionalDate(req.Data.EndDate) || !validOptionalDate(req.Data.PremiumEndDate) || !validOptionalDate(req.Data.RenewalDate) {
+		return nil, ErrDateInvalid
+	}
+	if len(req.Data.EndDate) > 0 && req.Data.EndDate < req.Data.StartDate {
+		return nil, ErrEndBeforeStart
+	}
+
+	policy := &Policy{
+		Name:             strings.TrimSpace(req.Data.Name),
+		Category:         req.Data.Category,
+		Insurer:          strings.TrimSpace(req.Data.Insurer),
+		PolicyNumber:     req.Data.PolicyNumber,
+		HolderUserId:     req.Data.HolderUserId,
+		InsuredUserId:    req.Data.InsuredUserId,
+		InsuredName:      insuredName,
+		Beneficiary:      req.Data.Beneficiary,
+		CoverageAmount:   req.Data.CoverageAmount,
+		CoverageMemo:     req.Data.CoverageMemo,
+		PremiumAmount:    req.Data.PremiumAmount,
+		Currency:         strings.ToUpper(req.Data.Currency),
+		PremiumFrequency: req.Data.PremiumFrequency,
+		FirstPremiumDate: firstPremiumDate,
+		PremiumEndDate:   req.Data.PremiumEndDate,
+		StartDate:        req.Data.StartDate,
+		EndDate:          req.Data.EndDate,
+		RenewalDate:      req.Data.RenewalDate,
+		RemindDaysBefore: req.Data.RemindDaysBefore,
+		NotifyUserIds:    req.Data.NotifyUserIds,
+		Status:           PolicyStatusActive,
+		Memo:             req.Data.Memo,
+		TenantBaseModel:  model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	// 录入已有保单时，今天之前的保费视为已缴，不再生成账单
+	skipPastPremiums(policy)
+
+	policy, err := s.policyRepo.Add(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PolicyDTO]{
+		Data: toPolicyDTO(policy),
+	}, nil
+}
+
+func (s *service) UpdatePolicy(ctx context.Context, req *request.DataRequest[UpdatePolicyDTO]) (*response.DataResponse[PolicyDTO], error) {
+	policy, err := s.findPolicyById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		policy.Name = name
+	}
+	if len(req.Data.PolicyNumber) > 0 {
+		policy.PolicyNumber = req.Data.PolicyNumber
+	}
+	if len(req.Data.Beneficiary) > 0 {
+		policy.Beneficiary = req.Data.Beneficiary
+	}
+	if req.Data.CoverageAmount > 0 {
+		policy.CoverageAmount = req.Data.CoverageAmount
+	}
+	if len(req.Data.CoverageMemo) > 0 {
+		policy.CoverageMemo = req.Data.CoverageMemo
+	}
+	if req.Data.PremiumAmount > 0 {
+		policy.PremiumAmount = req.Data.PremiumAmount
+	}
+	if req.Data.RemindDaysBefore != nil {
+		policy.RemindDaysBefore = *req.Data.RemindDaysBefore
+	}
+	if req.Data.NotifyUserIds != nil {
+		policy.NotifyUserIds = req.Data.NotifyUserIds
+	}
+	if len(req.Data.Status) > 0 {
+		// 重新生效时不补生成停用期间的保费账单
+		if req.Data.Status == PolicyStatusActive && policy.Status != PolicyStatusActive {
+			skipPastPremiums(policy)
+		}
+		policy.Status = req.Data.Status
+	}
+	if len(req.Data.Memo) > 0 {
+		policy.Memo = req.Data.Memo
+	}
+
+	policy, err = s.policyRepo.Update(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PolicyDTO]{
+		Data: toPolicyDTO(policy),
+	}, nil
+}
+
+// skipPastPremiums 将已生成期数推进到今天，今天之前的保费不再生成账单
+func skipPastPremiums(policy *Policy) {
+	now := today().Format(DateLayout)
+	for {
+		date, ok := premiumDate(policy, policy.BilledPremiums)
+		if !ok || date >= now {
+			return
+		}
+		policy.BilledPremiums++
+	}
+}
+
+func (s *service) ListPolicies(ctx context.Context, tenantId string, insuredUserId string, status string) (*response.DataResponse[[]PolicyDTO], error) {
+	policies, err := s.findPolicies(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].StartDate < policies[j].StartDate
+	})
+
+	dtos := []PolicyDTO{}
+	for i := range policies {
+		if len(insuredUserId) > 0 && policies[i].InsuredUserId != insuredUserId {
+			continue
+		}
+		if len(status) > 0 && policies[i].Status != status {
+			continue
+		}
+		dtos = append(dtos, toPolicyDTO(&policies[i]))
+	}
+	return &response.DataResponse[[]PolicyDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetPolicy(ctx context.Context, tenantId string, id string) (*response.DataResponse[PolicyDetailDTO], error) {
+	policy, err := s.findPolicyById(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.findClaims(ctx, "policy_id", policy.Id)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := s.reimbursements.ListClaims(ctx, tenantId, "", "")
+	if err != nil {
+		return nil, err
+	}
+	claimsById := map[string]reimbursement.ClaimDTO{}
+	for _, claim := range claims.Data {
+		claimsById[claim.Id] = claim
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].IncidentDate > links[j].IncidentDate
+	})
+	claimDTOs := make([]PolicyClaimDTO, 0, len(links))
+	for i := range links {
+		claimDTOs = append(claimDTOs, toPolicyClaimDTO(&links[i], claimsById[links[i].ClaimId]))
+	}
+
+	upcoming := []string{}
+	if policy.Status == PolicyStatusActive {
+		until := today().AddDate(1, 0, 0).Format(DateLayout)
+		for n := policy.BilledPremiums; ; n++ {
+			date, ok := premiumDate(policy, n)
+			if !ok || date > until {
+				break
+			}
+			upcoming = append(upcoming, date)
+		}
+	}
+
+	return &response.DataResponse[PolicyDetailDTO]{
+		Data: PolicyDetailDTO{
+			Policy:           toPolicyDTO(policy),
+			Claims:           claimDTOs,
+			UpcomingPremiums: upcoming,
+		},
+	}, nil
+}
+
+func (s *service) RenewPolicy(ctx context.Context, req *request.DataRequest[RenewPolicyDTO]) (*response.DataResponse[PolicyDTO], error) {
+	if !validDate(req.Data.RenewalDate) || !validOptionalDate(req.Data.EndDate) {
+		return nil, ErrDateInvalid
+	}
+	policy, err := s.findPolicyById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Status != PolicyStatusActive {
+		return nil, ErrPolicyCancelled
+	}
+	if req.Data.RenewalDate <= policy.RenewalDate {
+		return nil, ErrRenewalDateNotLater
+	}
+	if len(req.Data.EndDate) > 0 && req.Data.EndDate < policy.StartDate {
+		return nil, ErrEndBeforeStart
+	}
+
+	policy.RenewalDate = req.Data.RenewalDate
+	policy.RenewalRemindedDate = ""
+	if req.Data.PremiumAmount > 0 {
+		policy.PremiumAmount = req.Data.PremiumAmount
+	}
+	if len(req.Data.EndDate) > 0 {
+		policy.EndDate = req.Data.EndDate
+	}
+	policy, err = s.policyRepo.Update(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PolicyDTO]{
+		Data: toPolicyDTO(policy),
+	}, nil
+}
+
+func (s *service) CreateClaim(ctx context.Context, req *request.DataRequest[CreatePolicyClaimDTO]) (*response.DataResponse[PolicyClaimDTO], error) {
+	if !validOptionalDate(req.Data.IncidentDate) {
+		return nil, ErrDateInvalid
+	}
+	policy, err := s.findPolicyById(ctx, req.Data.TenantId, req.Data.PolicyId)
+	if err != nil {
+		return nil, err
+	}
+
+	var claim reimbursement.ClaimDTO
+	if len(req.Data.ClaimId) > 0 {
+		linked, err := s.findClaims(ctx, "claim_id", req.Data.ClaimId)
+		if err != nil {
+			return nil, err
+		}
+		if len(linked) > 0 {
+			return nil, ErrClaimAlreadyLinked
+		}
+		detail, err := s.reimbursements.GetClaim(ctx, policy.TenantId, req.Data.ClaimId)
+		if err != nil {
+			return nil, err
+		}
+		claim = detail.Data.Claim
+	} else {
+		if req.Data.Amount <= 0 {
+			return nil, ErrClaimAmountRequired
+		}
+		userId := req.Data.UserId
+		if len(userId) == 0 {
+			userId = policy.InsuredUserId
+		}
+		if len(userId) == 0 {
+			userId = policy.HolderUserId
+		}
+		if len(userId) == 0 {
+			return nil, ErrClaimantRequired
+		}
+		title := strings.TrimSpace(req.Data.Title)
+		if len(title) == 0 {
+			title = policy.Name + " 理赔"
+		}
+		expenseDate := req.Data.ExpenseDate
+		if len(expenseDate) == 0 {
+			expenseDate = req.Data.IncidentDate
+		}
+		if len(expenseDate) == 0 {
+			expenseDate = today().Format(DateLayout)
+		}
+		created, err := s.reimbursements.CreateClaim(ctx, &request.DataRequest[reimbursement.CreateClaimDTO]{
+			Data: reimbursement.CreateClaimDTO{
+				TenantId:    policy.TenantId,
+				UserId:      userId,
+				PayerType:   reimbursement.PayerInsurance,
+				PayerName:   policy.Insurer,
+				Title:       title,
+				Category:    policy.Category,
+				ExpenseDate: expenseDate,
+				Amount:      req.Data.Amount,
+				Currency:    policy.Currency,
+				SourceType:  req.Data.SourceType,
+				SourceId:    req.Data.SourceId,
+				Memo:        req.Data.Description,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		claim = created.Data
+	}
+
+	link := &PolicyClaim{
+		PolicyId:        policy.Id,
+		ClaimId:         claim.Id,
+		IncidentDate:    req.Data.IncidentDate,
+		Description:     req.Data.Description,
+		TenantBaseModel: model.NewTenantBaseModel(policy.TenantId, util.GenerateId()),
+	}
+	link, err = s.claimRepo.Add(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PolicyClaimDTO]{
+		Data: toPolicyClaimDTO(link, claim),
+	}, nil
+}
+
+func (s *service) GetPremiumSummary(ctx context.Context, tenantId string) (*response.DataResponse[PremiumSummaryDTO], error) {
+	policies, err := s.findPolicies(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := PremiumSummaryDTO{
+		ByInsured: []PremiumTotalDTO{},
+		Totals:    []PremiumTotalDTO{},
+	}
+	byInsured := map[string]int{}
+	totals := map[string]int{}
+	for i := range policies {
+		policy := &policies[i]
+		premium := annualPremium(policy)
+		if policy.Status != PolicyStatusActive || premium == 0 {
+			continue
+		}
+
+		key := policy.InsuredUserId + "|" + policy.InsuredName + "|" + policy.Currency
+		pos, ok := byInsured[key]
+		if !ok {
+			pos = len(summary.ByInsured)
+			byInsured[key] = pos
+			summary.ByInsured = append(summary.ByInsured, PremiumTotalDTO{
+				InsuredUserId: policy.InsuredUserId,
+				InsuredName:   policy.InsuredName,
+				Currency:      policy.Currency,
+			})
+		}
+		summary.ByInsured[pos].PolicyCount++
+		summary.ByInsured[pos].AnnualPremium += premium
+
+		pos, ok = totals[policy.Currency]
+		if !ok {
+			pos = len(summary.Totals)
+			totals[policy.Currency] = pos
+			summary.Totals = append(summary.Totals, PremiumTotalDTO{Currency: policy.Currency})
+		}
+		summary.Totals[pos].PolicyCount++
+		summary.Totals[pos].AnnualPremium += premium
+	}
+	sort.SliceStable(summary.ByInsured, func(i, j int) bool {
+		return summary.ByInsured[i].AnnualPremium > summary.ByInsured[j].AnnualPremium
+	})
+
+	return &response.DataResponse[PremiumSummaryDTO]{
+		Data: summary,
+	}, nil
+}
+
+func (s *service) GeneratePremiumBills(ctx context.Context, asOf time.Time) (int, error) {
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	until := asOf.AddDate(0, 0, premiumBillDaysAhead).Format(DateLayout)
+
+	created := 0
+	var errs error
+	for page := 1; ; page++ {
+		policies, err := s.findPolicyPage(ctx, "status", PolicyStatusActive, page)
+		if err != nil {
+			return created, errors.Join(errs, err)
+		}
+		// 单张保单失败不影响其他保单
+		for i := range policies {
+			n, err := s.generatePolicyBills(ctx, &policies[i], until)
+			created += n
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("premium bills for policy %s: %w", policies[i].Id, err))
+			}
+		}
+		if len(policies) < policyPageSize {
+			return created, errs
+		}
+	}
+}
+
+// generatePolicyBills 逐期创建保费账单后再推进已生成期数。账单按保单和到期日去重，
+// 更新期数失败后重复执行也不会重复生成
+func (s *service) generatePolicyBills(ctx context.Context, policy *Policy, until string) (int, error) {
+	created := 0
+	for {
+		date, ok := premiumDate(policy, policy.BilledPremiums)
+		if !ok || date > until {
+			return created, nil
+		}
+		_, err := s.bills.CreateBill(ctx, &request.DataRequest[bill.CreateBillDTO]{
+			Data: bill.CreateBillDTO{
+				TenantId:         policy.TenantId,
+				Name:             "保费：" + policy.Name,
+				Category:         bill.BillInsurance,
+				ExpectedAmount:   policy.PremiumAmount,
+				Currency:         policy.Currency,
+				DueDate:          date,
+				Memo:             strings.TrimSpace(policy.Insurer + " " + policy.PolicyNumber),
+				RemindDaysBefore: policy.RemindDaysBefore,
+				NotifyUserIds:    policy.NotifyUserIds,
+				SourceType:       BillSourcePolicy,
+				SourceId:         policy.Id,
+			},
+		})
+		if err != nil {
+			return created, err
+		}
+		policy.BilledPremiums++
+		if _, err := s.policyRepo.Update(ctx, policy); err != nil {
+			return created, err
+		}
+		created++
+	}
+}
+
+func (s *service) SendRenewalReminders(ctx context.Context, asOf time.Time) (int, error) {
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+
+	sent := 0
+	var errs error
+	for page := 1; ; page++ {
+		policies, err := s.findPolicyPage(ctx, "status", PolicyStatusActive, page)
+		if err != nil {
+			return sent, errors.Join(errs, err)
+		}
+		// 单张保单失败不影响其他保单
+		for i := range policies {
+			reminded, err := s.remindRenewal(ctx, &policies[i], asOf)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("renewal reminder for policy %s: %w", policies[i].Id, err))
+				continue
+			}
+			if reminded {
+				sent++
+			}
+		}
+		if len(policies) < policyPageSize {
+			return sent, errs
+		}
+	}
+}
+
+// remindRenewal 续保日临近时发送一次续保提醒
+func (s *service) remindRenewal(ctx context.Context, policy *Policy, asOf time.Time) (bool, error) {
+	if len(policy.RenewalDate) == 0 || len(policy.RenewalRemindedDate) > 0 {
+		return false, nil
+	}
+	due := daysUntil(policy.RenewalDate, asOf)
+	if due < 0 || due > renewalRemindDays {
+		return false, nil
+	}
+
+	userIds := policy.NotifyUserIds
+	if len(userIds) == 0 {
+		var err error
+		if userIds, err = s.members.TenantUserIds(ctx, policy.TenantId); err != nil {
+			return false, err
+		}
+	}
+	// 先记录提醒状态再发送，避免重复执行时重复提醒
+	policy.RenewalRemindedDate = asOf.Format(DateLayout)
+	if _, err := s.policyRepo.Update(ctx, policy); err != nil {
+		return false, err
+	}
+	s.notifier.Notify(notification.Message{
+		TenantId: policy.TenantId,
+		UserIds:  userIds,
+		Category: NotificationCategory,
+		Title:    "续保提醒：" + policy.Name,
+		Content: fmt.Sprintf("%s（%s）将于 %s 到期续保，当前保费 %s %s。",
+			policy.Name, policy.Insurer, policy.RenewalDate, formatAmount(policy.PremiumAmount), policy.Currency),
+	})
+	return true, nil
+}
+
+func toPolicyDTO(policy *Policy) PolicyDTO {
+	return PolicyDTO{
+		Id:               policy.Id,
+		TenantId:         policy.TenantId,
+		Name:             policy.Name,
+		Category:         policy.Category,
+		Insurer:          policy.Insurer,
+		PolicyNumber:     policy.PolicyNumber,
+		HolderUserId:     policy.HolderUserId,
+		InsuredUserId:    policy.InsuredUserId,
+		InsuredName:      policy.InsuredName,
+		Beneficiary:      policy.Beneficiary,
+		CoverageAmount:   policy.CoverageAmount,
+		CoverageMemo:     policy.CoverageMemo,
+		PremiumAmount:    policy.PremiumAmount,
+		Currency:         policy.Currency,
+		PremiumFrequency: policy.PremiumFrequency,
+		FirstPremiumDate: policy.FirstPremiumDate,
+		PremiumEndDate:   policy.PremiumEndDate,
+		NextPremiumDate:  nextPremiumDate(policy),
+		AnnualPremium:    annualPremium(policy),
+		StartDate:        policy.StartDate,
+		EndDate:          policy.EndDate,
+		RenewalDate:      policy.RenewalDate,
+		RemindDaysBefore: policy.RemindDaysBefore,
+		NotifyUserIds:    policy.NotifyUserIds,
+		Status:           policy.Status,
+		Memo:             policy.Memo,
+	}
+}
+
+func toPolicyClaimDTO(link *PolicyClaim, claim reimbursement.ClaimDTO) PolicyClaimDTO {
+	return PolicyClaimDTO{
+		Id:           link.Id,
+		PolicyId:     link.PolicyId,
+		IncidentDate: link.IncidentDate,
+		Description:  link.Description,
+		Claim:        claim,
+	}
+}
+
+func daysUntil(date string, now time.Time) int {
+	t, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return 0
+	}
+	return int(t.Sub(now).Hours() / 24)
+}
+
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%.2f", float64(amount)/100)
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func validOptionalDate(date string) bool {
+	return len(date) == 0 || validDate(date)
+}
+
+func (s *service) findPolicyById(ctx context.Context, tenantId string, id string) (*Policy, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	policies, err := s.policyRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, ErrPolicyNotFound
+	}
+	return &policies[0], nil
+}
+
+func (s *service) findPolicies(ctx context.Context, field string, value string) ([]Policy, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.policyRepo.Query(ctx, query)
+}
+
+// findPolicyPage 按 id 顺序分页查询，后台任务更新保单不会打乱分页
+func (s *service) findPolicyPage(ctx context.Context, field string, value string, page int) ([]Policy, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		OrderBys:    []query.DbQueryOrderBy{query.NewDbQueryOrderBy("id", false)},
+		PageSize:    policyPageSize,
+		PageNumber:  page,
+	}
+	return s.policyRepo.Query(ctx, query)
+}
+
+func (s *service) findClaims(ctx context.Context, field string, value string) ([]PolicyClaim, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1000,
+		PageNumber:  1,
+	}
+	return s.claimRepo.Query(ctx, query)
+}
@@ -0,0 +1,170 @@
+package investment
+
+type PortfolioDTO struct {
+	Id          string `json:"id"`
+	TenantId    string `json:"tenant_id"`
+	Name        string `json:"name"`
+	Broker      string `json:"broker"`
+	Currency    string `json:"currency"`
+	CostMethod  string `json:"cost_method"`
+	OwnerUserId string `json:"owner_user_id"`
+	Closed      bool   `json:"closed"`
+	Memo        string `json:"memo"`
+	MarketValue int64  `json:"market_value"`
+	CostBasis   int64  `json:"cost_basis"`
+}
+
+type CreatePortfolioDTO struct {
+	TenantId    string `json:"tenant_id" binding:"required"`
+	Name        string `json:"name" binding:"required,max_len=200"`
+	Broker      string `json:"broker" binding:"omitempty,max_len=200"`
+	Currency    string `json:"currency" binding:"omitempty,len=3"`
+	CostMethod  string `json:"cost_method" binding:"omitempty,oneof=average fifo"`
+	OwnerUserId string `json:"owner_user_id" binding:"omitempty"`
+	Memo        string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type UpdatePortfolioDTO struct {
+	TenantId    string `json:"tenant_id" binding:"required"`
+	Id          string `json:"id"`
+	Name        string `json:"name" binding:"omitempty,max_len=200"`
+	Broker      string `json:"broker" binding:"omitempty,max_len=200"`
+	OwnerUserId string `json:"owner_user_id" binding:"omitempty"`
+	// 修改成本计算方法后持仓成本按新方法重新计算
+	CostMethod string `json:"cost_method" binding:"omitempty,oneof=average fifo"`
+	Closed     *bool  `json:"closed" binding:"omitempty"`
+	Memo       string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type SecurityDTO struct {
+	Id         string  `json:"id"`
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	AssetClass string  `json:"asset_class"`
+	Currency   string  `json:"currency"`
+	LastPrice  float64 `json:"last_price"`
+	PriceDate  string  `json:"price_date"`
+}
+
+type CreateSecurityDTO struct {
+	TenantId   string `json:"tenant_id" binding:"required"`
+	Code       string `json:"code" binding:"required,max_len=50"`
+	Name       string `json:"name" binding:"required,max_len=200"`
+	Kind       string `json:"kind" binding:"required,oneof=fund stock wealth_product bond other"`
+	AssetClass string `json:"asset_class" binding:"required,oneof=equity fixed_income cash mixed other"`
+	Currency   string `json:"currency" binding:"omitempty,len=3"`
+}
+
+type TransactionDTO struct {
+	Id          string  `json:"id"`
+	PortfolioId string  `json:"portfolio_id"`
+	SecurityId  string  `json:"security_id"`
+	Kind        string  `json:"kind"`
+	Date        string  `json:"date"`
+	Quantity    float64 `json:"quantity"`
+	Price       float64 `json:"price"`
+	Amount      int64   `json:"amount"`
+	Fee         int64   `json:"fee"`
+	Memo        string  `json:"memo"`
+}
+
+type CreateTransactionDTO struct {
+	TenantId    string `json:"tenant_id" binding:"required"`
+	PortfolioId string `json:"portfolio_id"`
+	SecurityId  string `json:"security_id" binding:"omitempty"`
+	Kind        string `json:"kind" binding:"required,oneof=buy sell dividend fee"`
+	Date        string `json:"date" binding:"required"`
+	// 分红时填写份额表示红利再投资
+	Quantity float64 `json:"quantity" binding:"omitempty,min=0"`
+	Price    float64 `json:"price" binding:"omitempty,min=0"`
+	// 为空时按份额乘以价格计算
+	Amount int64  `json:"amount" binding:"omitempty,min=0"`
+	Fee    int64  `json:"fee" binding:"omitempty,min=0"`
+	Memo   string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type SavePriceDTO struct {
+	TenantId   string  `json:"tenant_id" binding:"required"`
+	SecurityId string  `json:"security_id" binding:"required"`
+	PriceDate  string  `json:"price_date" binding:"required"`
+	Price      float64 `json:"price" binding:"required"`
+}
+
+type PriceDTO struct {
+	SecurityId string  `json:"security_id"`
+	PriceDate  string  `json:"price_date"`
+	Price      float64 `json:"price"`
+	Source     string  `json:"source"`
+}
+
+type ImportPricesDTO struct {
+	TenantId string `form:"tenant_id" binding:"required"`
+	Data     []byte `form:"-"`
+}
+
+type ImportResultDTO struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+type HoldingDTO struct {
+	SecurityId  string  `json:"security_id"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"`
+	AssetClass  string  `json:"asset_class"`
+	Quantity    float64 `json:"quantity"`
+	CostBasis   int64   `json:"cost_basis"`
+	AverageCost float64 `json:"average_cost"`
+	Price       float64 `json:"price"`
+	PriceDate   string  `json:"price_date"`
+	MarketValue int64   `json:"market_value"`
+	Unrealized  int64   `json:"unrealized"`
+	Realized    int64   `json:"realized"`
+	Dividends   int64   `json:"dividends"`
+	Fees        int64   `json:"fees"`
+}
+
+type HoldingsDTO struct {
+	PortfolioId string       `json:"portfolio_id"`
+	Currency    string       `json:"currency"`
+	CostMethod  string       `json:"cost_method"`
+	Holdings    []HoldingDTO `json:"holdings"`
+	MarketValue int64        `json:"market_value"`
+	CostBasis   int64        `json:"cost_basis"`
+	Unrealized  int64        `json:"unrealized"`
+	Realized    int64        `json:"realized"`
+	Dividends   int64        `json:"dividends"`
+	// 已实现收益加分红加浮动盈亏，已实现收益中已扣除费用
+	TotalReturn int64 `json:"total_return"`
+}
+
+type ValuationPointDTO struct {
+	Date        string `json:"date"`
+	Currency    string `json:"currency"`
+	MarketValue int64  `json:"market_value"`
+	CostBasis   int64  `json:"cost_basis"`
+}
+
+type AllocationItemDTO struct {
+	Key         string  `json:"key"`
+	MarketValue int64   `json:"market_value"`
+	Percent     float64 `json:"percent"`
+}
+
+type AllocationDTO struct {
+	Currency     string              `json:"currency"`
+	MarketValue  int64               `json:"market_value"`
+	ByAssetClass []AllocationItemDTO `json:"by_asset_class"`
+	ByKind       []AllocationItemDTO `json:"by_kind"`
+	BySecurity   []AllocationItemDTO `json:"by_security"`
+}
+
+type PortfolioValue struct {
+	PortfolioId string `json:"portfolio_id"`
+	Name        string `json:"name"`
+	OwnerUserId string `json:"owner_user_id"`
+	Currency    string `json:"currency"`
+	MarketValue int64  `json:"market_value"`
+}
@@ -0,0 +1,95 @@
+package investment
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	CostMethodAverage = "average"
+	CostMethodFifo    = "fifo"
+
+	SecurityFund  = "fund"
+	SecurityStock = "stock"
+	// 银行理财产品
+	SecurityWealthProduct = "wealth_product"
+	SecurityBond          = "bond"
+	SecurityOther         = "other"
+
+	AssetClassEquity      = "equity"
+	AssetClassFixedIncome = "fixed_income"
+	AssetClassCash        = "cash"
+	AssetClassMixed       = "mixed"
+	AssetClassOther       = "other"
+
+	TxnBuy      = "buy"
+	TxnSell     = "sell"
+	TxnDividend = "dividend"
+	TxnFee      = "fee"
+
+	PriceSourceManual = "manual"
+	PriceSourceImport = "import"
+
+	DateLayout = "2006-01-02"
+)
+
+// Portfolio 投资账户，如证券账户、基金平台或银行理财账户
+type Portfolio struct {
+	model.TenantBaseModel
+	Name        string `json:"name" gorm:"size:200;not null"`
+	Broker      string `json:"broker" gorm:"size:200"`
+	Currency    string `json:"currency" gorm:"size:3"`
+	CostMethod  string `json:"cost_method" gorm:"size:10;not null"`
+	OwnerUserId string `json:"owner_user_id" gorm:"size:32;index"`
+	Closed      bool   `json:"closed" gorm:"default:false"`
+	Memo        string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Portfolio) TableName() string {
+	return "finance_investment_portfolio"
+}
+
+// Security 投资标的，按租户维护，Code 为基金、股票代码或理财产品编号
+type Security struct {
+	model.TenantBaseModel
+	Code       string `json:"code" gorm:"size:50;not null;index"`
+	Name       string `json:"name" gorm:"size:200;not null"`
+	Kind       string `json:"kind" gorm:"size:20;not null"`
+	AssetClass string `json:"asset_class" gorm:"size:20;not null"`
+	Currency   string `json:"currency" gorm:"size:3"`
+}
+
+func (entity *Security) TableName() string {
+	return "finance_investment_security"
+}
+
+// InvestmentTransaction 投资交易。Quantity 为份额或股数，Price 为单位净值或成交价；
+// Amount 和 Fee 以分为单位，买入为成交金额，卖出为卖出金额，分红为分红金额
+type InvestmentTransaction struct {
+	model.TenantBaseModel
+	PortfolioId string  `json:"portfolio_id" gorm:"size:32;not null;index"`
+	SecurityId  string  `json:"security_id" gorm:"size:32;index"`
+	Kind        string  `json:"kind" gorm:"size:10;not null"`
+	Date        string  `json:"date" gorm:"size:10;not null"`
+	Quantity    float64 `json:"quantity"`
+	Price       float64 `json:"price"`
+	Amount      int64   `json:"amount"`
+	Fee         int64   `json:"fee"`
+	Memo        string  `json:"memo" gorm:"size:500"`
+}
+
+func (entity *InvestmentTransaction) TableName() string {
+	return "finance_investment_transaction"
+}
+
+// SecurityPrice 标的某日的价格或单位净值
+type SecurityPrice struct {
+	model.TenantBaseModel
+	SecurityId string  `json:"security_id" gorm:"size:32;not null;index"`
+	PriceDate  string  `json:"price_date" gorm:"size:10;not null"`
+	Price      float64 `json:"price" gorm:"not null"`
+	Source     string  `json:"source" gorm:"size:20"`
+}
+
+func (entity *SecurityPrice) TableName() string {
+	return "finance_investment_price"
+}
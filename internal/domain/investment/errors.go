@@ -0,0 +1,20 @@
+package investment
+
+import "errors"
+
+var (
+	ErrPortfolioNotFound     = errors.New("投资账户不存在")
+	ErrPortfolioClosed       = errors.New("投资账户已关闭")
+	ErrSecurityNotFound      = errors.New("投资标的不存在")
+	ErrSecurityCodeExists    = errors.New("投资标的代码已存在")
+	ErrSecurityRequired      = errors.New("请选择投资标的")
+	ErrDateInvalid           = errors.New("日期格式无效，应为YYYY-MM-DD")
+	ErrQuantityRequired      = errors.New("买入和卖出需填写份额")
+	ErrAmountRequired        = errors.New("请填写金额或价格")
+	ErrInsufficientQuantity  = errors.New("卖出份额超过持有份额")
+	ErrPriceInvalid          = errors.New("价格必须大于0")
+	ErrImportEmpty           = errors.New("导入文件中没有价格")
+	ErrImportUnknownSecurity = errors.New("未知的投资标的代码")
+	ErrGranularityInvalid    = errors.New("粒度无效，应为day、week或month")
+	ErrCurrencyMismatch      = errors.New("投资标的币种与投资账户币种不一致")
+)
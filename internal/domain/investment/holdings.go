@@ -0,0 +1,146 @@
+package investment
+
+import (
+	"math"
+	"sort"
+)
+
+// 份额比较的容差，基金份额通常保留两位小数
+const quantityEpsilon = 1e-6
+
+type lot struct {
+	quantity float64
+	cost     int64
+}
+
+// position 某一标的按时间顺序累计交易后的持仓
+type position struct {
+	securityId string
+	quantity   float64
+	// 剩余持仓的成本，含买入费用
+	cost      int64
+	lots      []lot
+	realized  int64
+	dividends int64
+	fees      int64
+	// 没有价格记录时以最近成交价估值
+	lastTradePrice float64
+	// 某笔卖出超过了当时的持有份额
+	oversold bool
+}
+
+// 同一天内先计入买入和分红再计入卖出，避免录入顺序导致先卖后买
+var txnKindOrder = map[string]int{
+	TxnBuy:      0,
+	TxnDividend: 1,
+	TxnSell:     2,
+	TxnFee:      3,
+}
+
+// computePositions 按成本计算方法累计 asOf（含）之前的交易，asOf 为空时累计全部交易
+func computePositions(method string, txns []InvestmentTransaction, asOf string) map[string]*position {
+	sorted := append([]InvestmentTransaction(nil), txns...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date < sorted[j].Date
+		}
+		return txnKindOrder[sorted[i].Kind] < txnKindOrder[sorted[j].Kind]
+	})
+
+	positions := map[string]*position{}
+	for _, txn := range sorted {
+		if len(asOf) > 0 && txn.Date > asOf {
+			break
+		}
+		p, ok := positions[txn.SecurityId]
+		if !ok {
+			p = &position{securityId: txn.SecurityId}
+			positions[txn.SecurityId] = p
+		}
+		switch txn.Kind {
+		case TxnBuy:
+			p.buy(txn.Quantity, txn.Amount+txn.Fee)
+			p.fees += txn.Fee
+			if txn.Price > 0 {
+				p.lastTradePrice = txn.Price
+			}
+		case TxnSell:
+			if txn.Quantity > p.quantity+quantityEpsilon {
+				p.oversold = true
+			}
+			removed := p.sell(method, txn.Quantity)
+			p.realized += txn.Amount - txn.Fee - removed
+			p.fees += txn.Fee
+			if txn.Price > 0 {
+				p.lastTradePrice = txn.Price
+			}
+		case TxnDividend:
+			p.dividends += txn.Amount
+			// 红利再投资：分红金额转为新增份额的成本
+			if txn.Quantity > 0 {
+				p.buy(txn.Quantity, txn.Amount)
+			}
+		case TxnFee:
+			p.fees += txn.Amount
+			p.realized -= txn.Amount
+		}
+	}
+	return positions
+}
+
+func (p *position) buy(quantity float64, cost int64) {
+	p.quantity += quantity
+	p.cost += cost
+	p.lots = append(p.lots, lot{quantity: quantity, cost: cost})
+}
+
+// sell 减少持仓并返回卖出部分的成本
+func (p *position) sell(method string, quantity float64) int64 {
+	quantity = math.Min(quantity, p.quantity)
+	if quantity <= 0 {
+		return 0
+	}
+
+	var removed int64
+	if method == CostMethodFifo {
+		left := quantity
+		for len(p.lots) > 0 && left > quantityEpsilon {
+			head := &p.lots[0]
+			if head.quantity <= left+quantityEpsilon {
+				removed += head.cost
+				left -= head.quantity
+				p.lots = p.lots[1:]
+				continue
+			}
+			part := int64(math.Round(float64(head.cost) * left / head.quantity))
+			head.cost -= part
+			head.quantity -= left
+			removed += part
+			left = 0
+		}
+	} else {
+		removed = int64(math.Round(float64(p.cost) * quantity / p.quantity))
+		// 平均成本法下按比例缩减各批次，保持批次数据与持仓一致
+		ratio := 1 - quantity/p.quantity
+		for i := range p.lots {
+			p.lots[i].quantity *= ratio
+			p.lots[i].cost = int64(math.Round(float64(p.lots[i].cost) * ratio))
+		}
+	}
+
+	p.quantity -= quantity
+	p.cost -= removed
+	if p.quantity < quantityEpsilon {
+		// 清仓后剩余的尾差计入成本
+		removed += p.cost
+		p.quantity = 0
+		p.cost = 0
+		p.lots = nil
+	}
+	return removed
+}
+
+// marketValue 以分为单位的市值
+func marketValue(quantity float64, price float64) int64 {
+	return int64(math.Round(quantity * price * 100))
+}
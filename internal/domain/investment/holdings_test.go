@@ -0,0 +1,152 @@
+package investment
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPositionSell(t *testing.T) {
+	type buy struct {
+		quantity float64
+		cost     int64
+	}
+	tests := []struct {
+		name         string
+		method       string
+		buys         []buy
+		sells        []float64
+		wantRemoved  []int64
+		wantQuantity float64
+		wantCost     int64
+	}{
+		{
+			name:         "先进先出跨批次卖出",
+			method:       CostMethodFifo,
+			buys:         []buy{{10, 1000}, {10, 2000}},
+			sells:        []float64{15},
+			wantRemoved:  []int64{2000},
+			wantQuantity: 5,
+			wantCost:     1000,
+		},
+		{
+			name:         "平均成本按比例结转",
+			method:       CostMethodAverage,
+			buys:         []buy{{10, 1000}, {10, 2000}},
+			sells:        []float64{15},
+			wantRemoved:  []int64{2250},
+			wantQuantity: 5,
+			wantCost:     750,
+		},
+		{
+			name:         "清仓时尾差计入最后一笔",
+			method:       CostMethodFifo,
+			buys:         []buy{{3, 1000}},
+			sells:        []float64{1, 2},
+			wantRemoved:  []int64{333, 667},
+			wantQuantity: 0,
+			wantCost:     0,
+		},
+		{
+			name:         "平均成本分次清仓",
+			method:       CostMethodAverage,
+			buys:         []buy{{3, 1000}},
+			sells:        []float64{1, 2},
+			wantRemoved:  []int64{333, 667},
+			wantQuantity: 0,
+			wantCost:     0,
+		},
+		{
+			name:         "卖出超过持仓时只结转现有持仓",
+			method:       CostMethodFifo,
+			buys:         []buy{{10, 1000}},
+			sells:        []float64{15},
+			wantRemoved:  []int64{1000},
+			wantQuantity: 0,
+			wantCost:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &position{}
+			for _, b := range tt.buys {
+				p.buy(b.quantity, b.cost)
+			}
+			for i, quantity := range tt.sells {
+				if removed := p.sell(tt.method, quantity); removed != tt.wantRemoved[i] {
+					t.Errorf("sell %d removed = %d, want %d", i, removed, tt.wantRemoved[i])
+				}
+			}
+			if math.Abs(p.quantity-tt.wantQuantity) > quantityEpsilon {
+				t.Errorf("quantity = %v, want %v", p.quantity, tt.wantQuantity)
+			}
+			if p.cost != tt.wantCost {
+				t.Errorf("cost = %d, want %d", p.cost, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestComputePositions(t *testing.T) {
+	tests := []struct {
+		name         string
+		txns         []InvestmentTransaction
+		asOf         string
+		wantQuantity float64
+		wantRealized int64
+		wantOversold bool
+	}{
+		{
+			name: "同一天先录入卖出也按先买后卖计算",
+			txns: []InvestmentTransaction{
+				{SecurityId: "s", Kind: TxnSell, Date: "2024-01-02", Quantity: 10, Amount: 1500},
+				{SecurityId: "s", Kind: TxnBuy, Date: "2024-01-02", Quantity: 10, Amount: 1000},
+			},
+			wantQuantity: 0,
+			wantRealized: 500,
+		},
+		{
+			name: "补录更早的卖出后重新检查之后的卖出",
+			txns: []InvestmentTransaction{
+				{SecurityId: "s", Kind: TxnBuy, Date: "2024-01-01", Quantity: 10, Amount: 1000},
+				{SecurityId: "s", Kind: TxnSell, Date: "2024-01-10", Quantity: 10, Amount: 1200},
+				{SecurityId: "s", Kind: TxnSell, Date: "2024-01-05", Quantity: 5, Amount: 600},
+			},
+			wantQuantity: 0,
+			wantRealized: 800,
+			wantOversold: true,
+		},
+		{
+			name: "只累计截止日之前的交易",
+			txns: []InvestmentTransaction{
+				{SecurityId: "s", Kind: TxnBuy, Date: "2024-01-01", Quantity: 10, Amount: 1000},
+				{SecurityId: "s", Kind: TxnSell, Date: "2024-02-01", Quantity: 20, Amount: 3000},
+			},
+			asOf:         "2024-01-31",
+			wantQuantity: 10,
+		},
+		{
+			name: "卖出费用和单独费用计入已实现损益",
+			txns: []InvestmentTransaction{
+				{SecurityId: "s", Kind: TxnBuy, Date: "2024-01-01", Quantity: 10, Amount: 1000, Fee: 5},
+				{SecurityId: "s", Kind: TxnSell, Date: "2024-01-02", Quantity: 10, Amount: 1200, Fee: 5},
+				{SecurityId: "s", Kind: TxnFee, Date: "2024-01-03", Amount: 10},
+			},
+			wantQuantity: 0,
+			wantRealized: 180,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := computePositions(CostMethodFifo, tt.txns, tt.asOf)["s"]
+			if math.Abs(p.quantity-tt.wantQuantity) > quantityEpsilon {
+				t.Errorf("quantity = %v, want %v", p.quantity, tt.wantQuantity)
+			}
+			if p.realized != tt.wantRealized {
+				t.Errorf("realized = %d, want %d", p.realized, tt.wantRealized)
+			}
+			if p.oversold != tt.wantOversold {
+				t.Errorf("oversold = %v, want %v", p.oversold, tt.wantOversold)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package investment
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建投资账户表
+	if err := db.AutoMigrate(&Portfolio{}); err != nil {
+		fmt.Println("创建投资账户表失败", err)
+	}
+
+	// 创建投资标的表
+	if err := db.AutoMigrate(&Security{}); err != nil {
+		fmt.Println("创建投资标的表失败", err)
+	}
+
+	// 创建投资交易表
+	if err := db.AutoMigrate(&InvestmentTransaction{}); err != nil {
+		fmt.Println("创建投资交易表失败", err)
+	}
+
+	// 创建标的价格表
+	if err := db.AutoMigrate(&SecurityPrice{}); err != nil {
+		fmt.Println("创建标的价格表失败", err)
+	}
+
+	fmt.Println("Investment模块迁移完成")
+}
@@ -0,0 +1,90 @@
+package investment
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type importedPrice struct {
+	Code      string
+	PriceDate string
+	Price     float64
+}
+
+// parsePriceCSV 解析价格文件，列顺序为 code,price_date,price，首行可为表头
+func parsePriceCSV(data []byte) ([]importedPrice, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	prices := []importedPrice{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "code") {
+			continue
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("第%d行: %w", line, ErrPriceInvalid)
+		}
+		date := strings.TrimSpace(record[1])
+		if !validDate(date) {
+			return nil, fmt.Errorf("第%d行: %w", line, ErrDateInvalid)
+		}
+		prices = append(prices, importedPrice{
+			Code:      strings.TrimSpace(record[0]),
+			PriceDate: date,
+			Price:     price,
+		})
+	}
+	if len(prices) == 0 {
+		return nil, ErrImportEmpty
+	}
+	return prices, nil
+}
+
+// priceBook 按标的索引的价格记录，按日期升序
+type priceBook map[string][]SecurityPrice
+
+func newPriceBook(prices []SecurityPrice) priceBook {
+	book := priceBook{}
+	for _, price := range prices {
+		book[price.SecurityId] = append(book[price.SecurityId], price)
+	}
+	for id := range book {
+		sort.Slice(book[id], func(i, j int) bool {
+			return book[id][i].PriceDate < book[id][j].PriceDate
+		})
+	}
+	return book
+}
+
+// at 返回 date（含）之前最近的价格，date 为空时返回最新价格
+func (book priceBook) at(securityId string, date string) (SecurityPrice, bool) {
+	prices := book[securityId]
+	if len(date) == 0 {
+		if len(prices) == 0 {
+			return SecurityPrice{}, false
+		}
+		return prices[len(prices)-1], true
+	}
+	i := sort.Search(len(prices), func(i int) bool {
+		return prices[i].PriceDate > date
+	})
+	if i == 0 {
+		return SecurityPrice{}, false
+	}
+	return prices[i-1], true
+}
@@ -0,0 +1,912 @@
+package investment
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+	"github.com/loongkirin/go-family-finance/internal/domain/currency"
+)
+
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+type InvestmentService interface {
+	CreatePortfolio(ctx context.Context, req *request.DataRequest[CreatePortfolioDTO]) (*response.DataResponse[PortfolioDTO], error)
+	UpdatePortfolio(ctx context.Context, req *request.DataRequest[UpdatePortfolioDTO]) (*response.DataResponse[PortfolioDTO], error)
+	ListPortfolios(ctx context.Context, tenantId string) (*response.DataResponse[[]PortfolioDTO], error)
+	CreateSecurity(ctx context.Context, req *request.DataRequest[CreateSecurityDTO]) (*response.DataResponse[SecurityDTO], error)
+	ListSecurities(ctx context.Context, tenantId string) (*response.DataResponse[[]SecurityDTO], error)
+	AddTransaction(ctx context.Context, req *request.DataRequest[CreateTransactionDTO]) (*response.DataResponse[TransactionDTO], error)
+	ListTransactions(ctx context.Context, tenantId string, portfolioId string) (*response.DataResponse[[]TransactionDTO], error)
+	SavePrice(ctx context.Context, req *request.DataRequest[SavePriceDTO]) (*response.DataResponse[PriceDTO], error)
+	ImportPrices(ctx context.Context, req *ImportPricesDTO) (*response.DataResponse[ImportResultDTO], error)
+	ListPrices(ctx context.Context, tenantId string, securityId string) (*response.DataResponse[[]PriceDTO], error)
+	GetHoldings(ctx context.Context, tenantId string, portfolioId string) (*response.DataResponse[HoldingsDTO], error)
+	// GetValuation 返回市值时间序列，portfolioId 为空时汇总全部投资账户并折算为家庭本位币
+	GetValuation(ctx context.Context, tenantId string, portfolioId string, from string, to string, granularity string) (*response.DataResponse[[]ValuationPointDTO], error)
+	// GetAllocation 返回资产配置，portfolioId 为空时汇总全部投资账户并折算为家庭本位币
+	GetAllocation(ctx context.Context, tenantId string, portfolioId string) (*response.DataResponse[AllocationDTO], error)
+	PortfolioValuer
+}
+
+// PortfolioValuer 供净资产等模块读取投资账户在某日的市值
+type PortfolioValuer interface {
+	// PortfolioValues 按 asOf 当日（含）之前的交易和价格计算各投资账户市值，asOf 为空时按最新数据
+	PortfolioValues(ctx context.Context, tenantId string, asOf string) ([]PortfolioValue, error)
+}
+
+type service struct {
+	portfolioRepo   repository.Repository[Portfolio]
+	securityRepo    repository.Repository[Security]
+	transactionRepo repository.Repository[InvestmentTransaction]
+	priceRepo       repository.Repository[SecurityPrice]
+	converter       currency.Converter
+}
+
+func NewInvestmentService(
+	portfolioRepo repository.Repository[Portfolio],
+	securityRepo repository.Repository[Security],
+	transactionRepo repository.Repository[InvestmentTransaction],
+	priceRepo repository.Repository[SecurityPrice],
+	converter currency.Converter,
+) InvestmentService {
+	return &service{
+		portfolioRepo:   portfolioRepo,
+		securityRepo:    securityRepo,
+		transactionRepo: transactionRepo,
+		priceRepo:       priceRepo,
+		converter:       converter,
+	}
+}
+
+func (s *service) CreatePortfolio(ctx context.Context, req *request.DataRequest[CreatePortfolioDTO]) (*response.DataResponse[PortfolioDTO], error) {
+	method := req.Data.CostMethod
+	if len(method) == 0 {
+		method = CostMethodAverage
+	}
+	portfolio := &Portfolio{
+		Name:            strings.TrimSpace(req.Data.Name),
+		Broker:          req.Data.Broker,
+		Currency:        strings.ToUpper(req.Data.Currency),
+		CostMethod:      method,
+		OwnerUserId:     req.Data.OwnerUserId,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	portfolio, err := s.portfolioRepo.Add(ctx, portfolio)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PortfolioDTO]{
+		Data: toPortfolioDTO(portfolio, 0, 0),
+	}, nil
+}
+
+func (s *service) UpdatePortfolio(ctx context.Context, req *request.DataRequest[UpdatePortfolioDTO]) (*response.DataResponse[PortfolioDTO], error) {
+	portfolio, err := s.findPortfolioById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		portfolio.Name = name
+	}
+	if len(req.Data.Broker) > 0 {
+		portfolio.Broker = req.Data.Broker
+	}
+	if len(req.Data.OwnerUserId) > 0 {
+		portfolio.OwnerUserId = req.Data.OwnerUserId
+	}
+	if len(req.Data.CostMethod) > 0 {
+		portfolio.CostMethod = req.Data.CostMethod
+	}
+	if req.Data.Closed != nil {
+		portfolio.Closed = *req.Data.Closed
+	}
+	if len(req.Data.Memo) > 0 {
+		portfolio.Memo = req.Data.Memo
+	}
+
+	portfolio, err = s.portfolioRepo.Update(ctx, portfolio)
+	if err != nil {
+		return nil, err
+	}
+	holdings, err := s.holdings(ctx, portfolio)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PortfolioDTO]{
+		Data: toPortfolioDTO(portfolio, holdings.MarketValue, holdings.CostBasis),
+	}, nil
+}
+
+func (s *service) ListPortfolios(ctx context.Context, tenantId string) (*response.DataResponse[[]PortfolioDTO], error) {
+	portfolios, err := s.findPortfolios(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(portfolios, func(i, j int) bool {
+		return portfolios[i].Name < portfolios[j].Name
+	})
+
+	dtos := make([]PortfolioDTO, 0, len(portfolios))
+	for i := range portfolios {
+		holdings, err := s.holdings(ctx, &portfolios[i])
+		if err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, toPortfolioDTO(&portfolios[i], holdings.MarketValue, holdings.CostBasis))
+	}
+	return &response.DataResponse[[]PortfolioDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) CreateSecurity(ctx context.Context, req *request.DataRequest[CreateSecurityDTO]) (*response.DataResponse[SecurityDTO], error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Data.Code))
+	securities, err := s.findSecurities(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	for _, security := range securities {
+		if security.Code == code {
+			return nil, ErrSecurityCodeExists
+		}
+	}
+
+	security := &Security{
+		Code:            code,
+		Name:            strings.TrimSpace(req.Data.Name),
+		Kind:            req.Data.Kind,
+		AssetClass:      req.Data.AssetClass,
+		Currency:        strings.ToUpper(req.Data.Currency),
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	security, err = s.securityRepo.Add(ctx, security)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[SecurityDTO]{
+		Data: toSecurityDTO(security, nil),
+	}, nil
+}
+
+func (s *service) ListSecurities(ctx context.Context, tenantId string) (*response.DataResponse[[]SecurityDTO], error) {
+	securities, err := s.findSecurities(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.findPrices(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	book := newPriceBook(prices)
+	sort.Slice(securities, func(i, j int) bool {
+		return securities[i].Code < securities[j].Code
+	})
+
+	dtos := make([]SecurityDTO, 0, len(securities))
+	for i := range securities {
+		var latest *SecurityPrice
+		if price, ok := book.at(securities[i].Id, ""); ok {
+			latest = &price
+		}
+		dtos = append(dtos, toSecurityDTO(&securities[i], latest))
+	}
+	return &response.DataResponse[[]SecurityDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) AddTransaction(ctx context.Context, req *request.DataRequest[CreateTransactionDTO]) (*response.DataResponse[TransactionDTO], error) {
+	if !validDate(req.Data.Date) {
+		return nil, ErrDateInvalid
+	}
+	portfolio, err := s.findPortfolioById(ctx, req.Data.TenantId, req.Data.PortfolioId)
+	if err != nil {
+		return nil, err
+	}
+	if portfolio.Closed {
+		return nil, ErrPortfolioClosed
+	}
+
+	// 账户级费用可不指定标的，其余交易必须指定
+	if len(req.Data.SecurityId) > 0 || req.Data.Kind != TxnFee {
+		if len(req.Data.SecurityId) == 0 {
+			return nil, ErrSecurityRequired
+		}
+		security, err := s.findSecurityById(ctx, req.Data.TenantId, req.Data.SecurityId)
+		if err != nil {
+			return nil, err
+		}
+		// 账户内的金额直接相加，标的币种必须与账户一致
+		if len(security.Currency) > 0 && len(portfolio.Currency) > 0 && security.Currency != portfolio.Currency {
+			return nil, ErrCurrencyMismatch
+		}
+	}
+
+	amount := req.Data.Amount
+	if amount == 0 && req.Data.Kind != TxnFee {
+		amount = marketValue(req.Data.Quantity, req.Data.Price)
+	}
+	if (req.Data.Kind == TxnBuy || req.Data.Kind == TxnSell) && req.Data.Quantity <= 0 {
+		return nil, ErrQuantityRequired
+	}
+	if amount <= 0 {
+		return nil, ErrAmountRequired
+	}
+
+	txn := &InvestmentTransaction{
+		PortfolioId:     portfolio.Id,
+		SecurityId:      req.Data.SecurityId,
+		Kind:            req.Data.Kind,
+		Date:            req.Data.Date,
+		Quantity:        req.Data.Quantity,
+		Price:           req.Data.Price,
+		Amount:          amount,
+		Fee:             req.Data.Fee,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(portfolio.TenantId, util.GenerateId()),
+	}
+	if txn.Price == 0 && txn.Quantity > 0 {
+		txn.Price = float64(amount) / 100 / txn.Quantity
+	}
+	if txn.Kind == TxnSell {
+		txns, err := s.findTransactions(ctx, "portfolio_id", portfolio.Id)
+		if err != nil {
+			return nil, err
+		}
+		// 补录较早的卖出时，之后的卖出也必须仍有足够份额
+		if p, ok := computePositions(portfolio.CostMethod, append(txns, *txn), "")[txn.SecurityId]; !ok || p.oversold {
+			return nil, ErrInsufficientQuantity
+		}
+	}
+	txn, err = s.transactionRepo.Add(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[TransactionDTO]{
+		Data: toTransactionDTO(txn),
+	}, nil
+}
+
+func (s *service) ListTransactions(ctx context.Context, tenantId string, portfolioId string) (*response.DataResponse[[]TransactionDTO], error) {
+	field, value := "tenant_id", tenantId
+	if len(portfolioId) > 0 {
+		portfolio, err := s.findPortfolioById(ctx, tenantId, portfolioId)
+		if err != nil {
+			return nil, err
+		}
+		field, value = "portfolio_id", portfolio.Id
+	}
+	txns, err := s.findTransactions(ctx, field, value)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(txns, func(i, j int) bool {
+		return txns[i].Date > txns[j].Date
+	})
+
+	dtos := make([]TransactionDTO, 0, len(txns))
+	for i := range txns {
+		dtos = append(dtos, toTransactionDTO(&txns[i]))
+	}
+	return &response.DataResponse[[]TransactionDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) SavePrice(ctx context.Context, req *request.DataRequest[SavePriceDTO]) (*response.DataResponse[PriceDTO], error) {
+	if !validDate(req.Data.PriceDate) {
+		return nil, ErrDateInvalid
+	}
+	if req.Data.Price <= 0 {
+		return nil, ErrPriceInvalid
+	}
+	security, err := s.findSecurityById(ctx, req.Data.TenantId, req.Data.SecurityId)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.findPrices(ctx, "security_id", security.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	price, _, err := s.upsertPrice(ctx, security, prices, req.Data.PriceDate, req.Data.Price, PriceSourceManual)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PriceDTO]{
+		Data: toPriceDTO(price),
+	}, nil
+}
+
+func (s *service) ImportPrices(ctx context.Context, req *ImportPricesDTO) (*response.DataResponse[ImportResultDTO], error) {
+	imported, err := parsePriceCSV(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	securities, err := s.findSecurities(ctx, req.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	byCode := map[string]*Security{}
+	for i := range securities {
+		byCode[securities[i].Code] = &securities[i]
+	}
+	// 先校验全部代码，避免导入一半失败
+	for _, price := range imported {
+		if _, ok := byCode[strings.ToUpper(price.Code)]; !ok {
+			return nil, ErrImportUnknownSecurity
+		}
+	}
+	prices, err := s.findPrices(ctx, "tenant_id", req.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ImportResultDTO{}
+	for _, price := range imported {
+		entity, created, err := s.upsertPrice(ctx, byCode[strings.ToUpper(price.Code)], prices, price.PriceDate, price.Price, PriceSourceImport)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			prices = append(prices, *entity)
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	return &response.DataResponse[ImportResultDTO]{
+		Data: result,
+	}, nil
+}
+
+func (s *service) ListPrices(ctx context.Context, tenantId string, securityId string) (*response.DataResponse[[]PriceDTO], error) {
+	security, err := s.findSecurityById(ctx, tenantId, securityId)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.findPrices(ctx, "security_id", security.Id)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].PriceDate > prices[j].PriceDate
+	})
+
+	dtos := make([]PriceDTO, 0, len(prices))
+	for i := range prices {
+		dtos = append(dtos, toPriceDTO(&prices[i]))
+	}
+	return &response.DataResponse[[]PriceDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetHoldings(ctx context.Context, tenantId string, portfolioId string) (*response.DataResponse[HoldingsDTO], error) {
+	portfolio, err := s.findPortfolioById(ctx, tenantId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+	holdings, err := s.holdings(ctx, portfolio)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[HoldingsDTO]{
+		Data: *holdings,
+	}, nil
+}
+
+func (s *service) GetValuation(ctx context.Context, tenantId string, portfolioId string, from string, to string, granularity string) (*response.DataResponse[[]ValuationPointDTO], error) {
+	if len(granularity) == 0 {
+		granularity = GranularityMonth
+	}
+	if granularity != GranularityDay && granularity != GranularityWeek && granularity != GranularityMonth {
+		return nil, ErrGranularityInvalid
+	}
+	if (len(from) > 0 && !validDate(from)) || (len(to) > 0 && !validDate(to)) {
+		return nil, ErrDateInvalid
+	}
+	portfolios, txns, err := s.scope(ctx, tenantId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.findPrices(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	book := newPriceBook(prices)
+
+	if len(from) == 0 {
+		for _, txn := range txns {
+			if len(from) == 0 || txn.Date < from {
+				from = txn.Date
+			}
+		}
+	}
+	if len(to) == 0 {
+		to = time.Now().Format(DateLayout)
+	}
+	points := []ValuationPointDTO{}
+	if len(from) == 0 || from > to {
+		return &response.DataResponse[[]ValuationPointDTO]{
+			Data: points,
+		}, nil
+	}
+
+	target, err := s.reportCurrency(ctx, tenantId, portfolios)
+	if err != nil {
+		return nil, err
+	}
+	byPortfolio := map[string][]InvestmentTransaction{}
+	for _, txn := range txns {
+		byPortfolio[txn.PortfolioId] = append(byPortfolio[txn.PortfolioId], txn)
+	}
+	for _, date := range seriesDates(from, to, granularity) {
+		point := ValuationPointDTO{Date: date, Currency: target}
+		for _, portfolio := range portfolios {
+			var value, cost int64
+			for _, p := range computePositions(portfolio.CostMethod, byPortfolio[portfolio.Id], date) {
+				value += marketValue(p.quantity, priceOf(book, p, date))
+				cost += p.cost
+			}
+			if value, err = s.convert(ctx, tenantId, value, portfolio.Currency, target, date); err != nil {
+				return nil, err
+			}
+			if cost, err = s.convert(ctx, tenantId, cost, portfolio.Currency, target, date); err != nil {
+				return nil, err
+			}
+			point.MarketValue += value
+			point.CostBasis += cost
+		}
+		points = append(points, point)
+	}
+	return &response.DataResponse[[]ValuationPointDTO]{
+		Data: points,
+	}, nil
+}
+
+func (s *service) GetAllocation(ctx context.Context, tenantId string, portfolioId string) (*response.DataResponse[AllocationDTO], error) {
+	portfolios, txns, err := s.scope(ctx, tenantId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+	securities, err := s.findSecurities(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.findPrices(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	book := newPriceBook(prices)
+	byId := map[string]*Security{}
+	for i := range securities {
+		byId[securities[i].Id] = &securities[i]
+	}
+
+	byPortfolio := map[string][]InvestmentTransaction{}
+	for _, txn := range txns {
+		byPortfolio[txn.PortfolioId] = append(byPortfolio[txn.PortfolioId], txn)
+	}
+	target, err := s.reportCurrency(ctx, tenantId, portfolios)
+	if err != nil {
+		return nil, err
+	}
+	byClass, byKind, bySecurity := map[string]int64{}, map[string]int64{}, map[string]int64{}
+	allocation := AllocationDTO{Currency: target}
+	for _, portfolio := range portfolios {
+		for id, p := range computePositions(portfolio.CostMethod, byPortfolio[portfolio.Id], "") {
+			security, ok := byId[id]
+			if !ok || p.quantity == 0 {
+				continue
+			}
+			value, err := s.convert(ctx, tenantId, marketValue(p.quantity, priceOf(book, p, "")), portfolio.Currency, target, "")
+			if err != nil {
+				return nil, err
+			}
+			byClass[security.AssetClass] += value
+			byKind[security.Kind] += value
+			bySecurity[security.Code] += value
+			allocation.MarketValue += value
+		}
+	}
+	allocation.ByAssetClass = allocationItems(byClass, allocation.MarketValue)
+	allocation.ByKind = allocationItems(byKind, allocation.MarketValue)
+	allocation.BySecurity = allocationItems(bySecurity, allocation.MarketValue)
+
+	return &response.DataResponse[AllocationDTO]{
+		Data: allocation,
+	}, nil
+}
+
+func (s *service) PortfolioValues(ctx context.Context, tenantId string, asOf string) ([]PortfolioValue, error) {
+	portfolios, txns, err := s.scope(ctx, tenantId, "")
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.findPrices(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	book := newPriceBook(prices)
+	byPortfolio := map[string][]InvestmentTransaction{}
+	for _, txn := range txns {
+		byPortfolio[txn.PortfolioId] = append(byPortfolio[txn.PortfolioId], txn)
+	}
+
+	values := make([]PortfolioValue, 0, len(portfolios))
+	for _, portfolio := range portfolios {
+		value := PortfolioValue{
+			PortfolioId: portfolio.Id,
+			Name:        portfolio.Name,
+			OwnerUserId: portfolio.OwnerUserId,
+			Currency:    portfolio.Currency,
+		}
+		for _, p := range computePositions(portfolio.CostMethod, byPortfolio[portfolio.Id], asOf) {
+			value.MarketValue += marketValue(p.quantity, priceOf(book, p, asOf))
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// holdings 计算投资账户当前持仓、成本和收益
+func (s *service) holdings(ctx context.Context, portfolio *Portfolio) (*HoldingsDTO, error) {
+	txns, err := s.findTransactions(ctx, "portfolio_id", portfolio.Id)
+	if err != nil {
+		return nil, err
+	}
+	securities, err := s.findSecurities(ctx, portfolio.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.findPrices(ctx, "tenant_id", portfolio.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	book := newPriceBook(prices)
+
+	result := &HoldingsDTO{
+		PortfolioId: portfolio.Id,
+		Currency:    portfolio.Currency,
+		CostMethod:  portfolio.CostMethod,
+		Holdings:    []HoldingDTO{},
+	}
+	positions := computePositions(portfolio.CostMethod, txns, "")
+	for i := range securities {
+		security := &securities[i]
+		p, ok := positions[security.Id]
+		if !ok {
+			continue
+		}
+		holding := HoldingDTO{
+			SecurityId: security.Id,
+			Code:       security.Code,
+			Name:       security.Name,
+			Kind:       security.Kind,
+			AssetClass: security.AssetClass,
+			Quantity:   p.quantity,
+			CostBasis:  p.cost,
+			Realized:   p.realized,
+			Dividends:  p.dividends,
+			Fees:       p.fees,
+			Price:      p.lastTradePrice,
+		}
+		if price, ok := book.at(security.Id, ""); ok {
+			holding.Price = price.Price
+			holding.PriceDate = price.PriceDate
+		}
+		if p.quantity > 0 {
+			holding.AverageCost = math.Round(float64(p.cost)/100/p.quantity*10000) / 10000
+			holding.MarketValue = marketValue(p.quantity, holding.Price)
+			holding.Unrealized = holding.MarketValue - p.cost
+		}
+		result.Holdings = append(result.Holdings, holding)
+		result.MarketValue += holding.MarketValue
+		result.CostBasis += holding.CostBasis
+		result.Unrealized += holding.Unrealized
+		result.Realized += holding.Realized
+		result.Dividends += holding.Dividends
+	}
+	// 未指定标的的账户级费用
+	if p, ok := positions[""]; ok {
+		result.Realized += p.realized
+	}
+	result.TotalReturn = result.Realized + result.Dividends + result.Unrealized
+	sort.Slice(result.Holdings, func(i, j int) bool {
+		return result.Holdings[i].MarketValue > result.Holdings[j].MarketValue
+	})
+	return result, nil
+}
+
+// scope 返回租户全部或指定投资账户及其交易
+func (s *service) scope(ctx context.Context, tenantId string, portfolioId string) ([]Portfolio, []InvestmentTransaction, error) {
+	if len(portfolioId) > 0 {
+		portfolio, err := s.findPortfolioById(ctx, tenantId, portfolioId)
+		if err != nil {
+			return nil, nil, err
+		}
+		txns, err := s.findTransactions(ctx, "portfolio_id", portfolio.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []Portfolio{*portfolio}, txns, nil
+	}
+	portfolios, err := s.findPortfolios(ctx, tenantId)
+	if err != nil {
+		return nil, nil, err
+	}
+	txns, err := s.findTransactions(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return portfolios, txns, nil
+}
+
+// reportCurrency 单个投资账户按账户币种展示，汇总多个账户时折算为家庭本位币
+func (s *service) reportCurrency(ctx context.Context, tenantId string, portfolios []Portfolio) (string, error) {
+	if len(portfolios) == 1 && len(portfolios[0].Currency) > 0 {
+		return portfolios[0].Currency, nil
+	}
+	return s.converter.BaseCurrency(ctx, tenantId)
+}
+
+// convert 将账户币种金额折算为 to，账户未设置币种时视为本位币
+func (s *service) convert(ctx context.Context, tenantId string, amount int64, from string, to string, date string) (int64, error) {
+	if amount == 0 || len(from) == 0 || from == to {
+		return amount, nil
+	}
+	return s.converter.ConvertAmount(ctx, tenantId, amount, from, to, date)
+}
+
+func (s *service) upsertPrice(ctx context.Context, security *Security, prices []SecurityPrice, date string, value float64, source string) (*SecurityPrice, bool, error) {
+	for i := range prices {
+		if prices[i].SecurityId == security.Id && prices[i].PriceDate == date {
+			prices[i].Price = value
+			prices[i].Source = source
+			price, err := s.priceRepo.Update(ctx, &prices[i])
+			return price, false, err
+		}
+	}
+	price := &SecurityPrice{
+		SecurityId:      security.Id,
+		PriceDate:       date,
+		Price:           value,
+		Source:          source,
+		TenantBaseModel: model.NewTenantBaseModel(security.TenantId, util.GenerateId()),
+	}
+	price, err := s.priceRepo.Add(ctx, price)
+	return price, true, err
+}
+
+// priceOf 取 date 之前最近的价格，没有价格记录时取最近成交价
+func priceOf(book priceBook, p *position, date string) float64 {
+	if price, ok := book.at(p.securityId, date); ok {
+		return price.Price
+	}
+	return p.lastTradePrice
+}
+
+// seriesDates 生成时间序列的取值日期，按月时取每月最后一天，最后一点为 to
+func seriesDates(from string, to string, granularity string) []string {
+	start, _ := time.Parse(DateLayout, from)
+	end, _ := time.Parse(DateLayout, to)
+	dates := []string{}
+	for d := start; !d.After(end); {
+		var next time.Time
+		switch granularity {
+		case GranularityDay:
+			next = d.AddDate(0, 0, 1)
+		case GranularityWeek:
+			next = d.AddDate(0, 0, 7)
+		default:
+			next = time.Date(d.Year(), d.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		}
+		point := next.AddDate(0, 0, -1)
+		if granularity != GranularityMonth {
+			point = d
+		}
+		if point.After(end) {
+			point = end
+		}
+		dates = append(dates, point.Format(DateLayout))
+		d = next
+	}
+	if len(dates) > 0 && dates[len(dates)-1] != to {
+		dates = append(dates, to)
+	}
+	return dates
+}
+
+func allocationItems(values map[string]int64, total int64) []AllocationItemDTO {
+	items := make([]AllocationItemDTO, 0, len(values))
+	for key, value := range values {
+		item := AllocationItemDTO{Key: key, MarketValue: value}
+		if total > 0 {
+			item.Percent = math.Round(float64(value)/float64(total)*10000) / 100
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].MarketValue != items[j].MarketValue {
+			return items[i].MarketValue > items[j].MarketValue
+		}
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
+
+func toPortfolioDTO(portfolio *Portfolio, marketValue int64, costBasis int64) PortfolioDTO {
+	return PortfolioDTO{
+		Id:          portfolio.Id,
+		TenantId:    portfolio.TenantId,
+		Name:        portfolio.Name,
+		Broker:      portfolio.Broker,
+		Currency:    portfolio.Currency,
+		CostMethod:  portfolio.CostMethod,
+		OwnerUserId: portfolio.OwnerUserId,
+		Closed:      portfolio.Closed,
+		Memo:        portfolio.Memo,
+		MarketValue: marketValue,
+		CostBasis:   costBasis,
+	}
+}
+
+func toSecurityDTO(security *Security, latest *SecurityPrice) SecurityDTO {
+	dto := SecurityDTO{
+		Id:         security.Id,
+		Code:       security.Code,
+		Name:       security.Name,
+		Kind:       security.Kind,
+		AssetClass: security.AssetClass,
+		Currency:   security.Currency,
+	}
+	if latest != nil {
+		dto.LastPrice = latest.Price
+		dto.PriceDate = latest.PriceDate
+	}
+	return dto
+}
+
+func toTransactionDTO(txn *InvestmentTransaction) TransactionDTO {
+	return TransactionDTO{
+		Id:          txn.Id,
+		PortfolioId: txn.PortfolioId,
+		SecurityId:  txn.SecurityId,
+		Kind:        txn.Kind,
+		Date:        txn.Date,
+		Quantity:    txn.Quantity,
+		Price:       txn.Price,
+		Amount:      txn.Amount,
+		Fee:         txn.Fee,
+		Memo:        txn.Memo,
+	}
+}
+
+func toPriceDTO(price *SecurityPrice) PriceDTO {
+	return PriceDTO{
+		SecurityId: price.SecurityId,
+		PriceDate:  price.PriceDate,
+		Price:      price.Price,
+		Source:     price.Source,
+	}
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func (s *service) findPortfolioById(ctx context.Context, tenantId string, id string) (*Portfolio, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	portfolios, err := s.portfolioRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(portfolios) == 0 {
+		return nil, ErrPortfolioNotFound
+	}
+	return &portfolios[0], nil
+}
+
+func (s *service) findPortfolios(ctx context.Context, tenantId string) ([]Portfolio, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1000,
+		PageNumber:  1,
+	}
+	return s.portfolioRepo.Query(ctx, query)
+}
+
+func (s *service) findSecurityById(ctx context.Context, tenantId string, id string) (*Security, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	securities, err := s.securityRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(securities) == 0 {
+		return nil, ErrSecurityNotFound
+	}
+	return &securities[0], nil
+}
+
+func (s *service) findSecurities(ctx context.Context, tenantId string) ([]Security, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.securityRepo.Query(ctx, query)
+}
+
+func (s *service) findTransactions(ctx context.Context, field string, value string) ([]InvestmentTransaction, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    100000,
+		PageNumber:  1,
+	}
+	return s.transactionRepo.Query(ctx, query)
+}
+
+func (s *service) findPrices(ctx context.Context, field string, value string) ([]SecurityPrice, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    100000,
+		PageNumber:  1,
+	}
+	return s.priceRepo.Query(ctx, query)
+}
@@ -0,0 +1,133 @@
+package iou
+
+type IouDTO struct {
+	Id                 string `json:"id"`
+	TenantId           string `json:"tenant_id"`
+	UserId             string `json:"user_id"`
+	Direction          string `json:"direction"`
+	CounterpartyUserId string `json:"counterparty_user_id"`
+	CounterpartyName   string `json:"counterparty_name"`
+	Amount             int64  `json:"amount"`
+	RepaidAmount       int64  `json:"repaid_amount"`
+	Outstanding        int64  `json:"outstanding"`
+	Currency           string `json:"currency"`
+	Date               string `json:"date"`
+	DueDate            string `json:"due_date"`
+	Settled            bool   `json:"settled"`
+	SettledDate        string `json:"settled_date"`
+	Memo               string `json:"memo"`
+}
+
+type CreateIouDTO struct {
+	TenantId           string `json:"tenant_id" binding:"required"`
+	UserId             string `json:"user_id" binding:"required"`
+	Direction          string `json:"direction" binding:"required,oneof=lent borrowed"`
+	CounterpartyUserId string `json:"counterparty_user_id" binding:"omitempty"`
+	CounterpartyName   string `json:"counterparty_name" binding:"omitempty,max_len=100"`
+	Amount             int64  `json:"amount" binding:"required,min=1"`
+	Currency           string `json:"currency" binding:"omitempty,len=3"`
+	Date               string `json:"date" binding:"required"`
+	DueDate            string `json:"due_date" binding:"omitempty"`
+	Memo               string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type RepayIouDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	Id       string `json:"id"`
+	// 为空时还清全部未还金额
+	Amount int64 `json:"amount" binding:"omitempty,min=1"`
+	// 为空时为今天
+	Date string `json:"date" binding:"omitempty"`
+}
+
+// CounterpartyBalanceDTO 与某一对方之间某一币种未结清的借款，Net 为正表示对方欠我方
+type CounterpartyBalanceDTO struct {
+	CounterpartyUserId string `json:"counterparty_user_id"`
+	CounterpartyName   string `json:"counterparty_name"`
+	Currency           string `json:"currency"`
+	Receivable         int64  `json:"receivable"`
+	Payable            int64  `json:"payable"`
+	Net                int64  `json:"net"`
+	OpenCount          int    `json:"open_count"`
+}
+
+type SharedExpenseDTO struct {
+	Id       string         `json:"id"`
+	TenantId string         `json:"tenant_id"`
+	Title    string         `json:"title"`
+	Date     string         `json:"date"`
+	Amount   int64          `json:"amount"`
+	Currency string         `json:"currency"`
+	PaidBy   string         `json:"paid_by"`
+	Shares   []ExpenseShare `json:"shares"`
+	Memo     string         `json:"memo"`
+}
+
+type CreateSharedExpenseDTO struct {
+	TenantId  string `json:"tenant_id" binding:"required"`
+	Title     string `json:"title" binding:"required,max_len=200"`
+	Date      string `json:"date" binding:"required"`
+	Amount    int64  `json:"amount" binding:"required,min=1"`
+	Currency  string `json:"currency" binding:"omitempty,len=3"`
+	PaidBy    string `json:"paid_by" binding:"required,max_len=100"`
+	SplitMode string `json:"split_mode" binding:"required,oneof=equal exact"`
+	// 平均分摊时的参与人
+	Participants []string `json:"participants" binding:"omitempty"`
+	// 按金额分摊时每人的金额
+	Shares []ExpenseShare `json:"shares" binding:"omitempty"`
+	Memo   string         `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type SettlementDTO struct {
+	Id              string `json:"id"`
+	FromParticipant string `json:"from_participant"`
+	ToParticipant   string `json:"to_participant"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+	Date            string `json:"date"`
+	Memo            string `json:"memo"`
+}
+
+type CreateSettlementDTO struct {
+	TenantId        string `json:"tenant_id" binding:"required"`
+	FromParticipant string `json:"from_participant" binding:"required,max_len=100"`
+	ToParticipant   string `json:"to_participant" binding:"required,max_len=100"`
+	Amount          int64  `json:"amount" binding:"required,min=1"`
+	// 结清的共同支出币种
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	// 为空时为今天
+	Date string `json:"date" binding:"omitempty"`
+	Memo string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+// ParticipantBalanceDTO 参与人在某一币种下的分摊余额，Net 为正表示应收回，为负表示应付出
+type ParticipantBalanceDTO struct {
+	Participant string `json:"participant"`
+	Currency    string `json:"currency"`
+	Paid        int64  `json:"paid"`
+	Share       int64  `json:"share"`
+	Net         int64  `json:"net"`
+}
+
+type TransferDTO struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type SharedBalancesDTO struct {
+	Balances []ParticipantBalanceDTO `json:"balances"`
+	// 结清所有分摊所需的转账
+	Plan []TransferDTO `json:"plan"`
+}
+
+type IouBalance struct {
+	IouId              string `json:"iou_id"`
+	UserId             string `json:"user_id"`
+	Direction          string `json:"direction"`
+	CounterpartyUserId string `json:"counterparty_user_id"`
+	CounterpartyName   string `json:"counterparty_name"`
+	Currency           string `json:"currency"`
+	Outstanding        int64  `json:"outstanding"`
+}
@@ -0,0 +1,76 @@
+package iou
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	// 借出
+	DirectionLent = "lent"
+	// 借入
+	DirectionBorrowed = "borrowed"
+
+	SplitEqual = "equal"
+	SplitExact = "exact"
+
+	DateLayout = "2006-01-02"
+)
+
+// Iou 成员与家庭成员或外部亲友之间的借款记录
+type Iou struct {
+	model.TenantBaseModel
+	UserId    string `json:"user_id" gorm:"size:32;not null;index"`
+	Direction string `json:"direction" gorm:"size:10;not null"`
+	// 对方为家庭成员时填写用户 ID，否则填写姓名
+	CounterpartyUserId string `json:"counterparty_user_id" gorm:"size:32"`
+	CounterpartyName   string `json:"counterparty_name" gorm:"size:100"`
+	Amount             int64  `json:"amount" gorm:"not null"`
+	RepaidAmount       int64  `json:"repaid_amount"`
+	Currency           string `json:"currency" gorm:"size:3"`
+	Date               string `json:"date" gorm:"size:10;not null"`
+	DueDate            string `json:"due_date" gorm:"size:10"`
+	Settled            bool   `json:"settled" gorm:"default:false;index"`
+	SettledDate        string `json:"settled_date" gorm:"size:10"`
+	Memo               string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Iou) TableName() string {
+	return "finance_iou"
+}
+
+// ExpenseShare 参与人应分摊的金额，参与人为成员用户 ID 或外部亲友姓名
+type ExpenseShare struct {
+	Participant string `json:"participant"`
+	Amount      int64  `json:"amount"`
+}
+
+// SharedExpense 多人分摊的共同支出
+type SharedExpense struct {
+	model.TenantBaseModel
+	Title    string         `json:"title" gorm:"size:200;not null"`
+	Date     string         `json:"date" gorm:"size:10;not null"`
+	Amount   int64          `json:"amount" gorm:"not null"`
+	Currency string         `json:"currency" gorm:"size:3"`
+	PaidBy   string         `json:"paid_by" gorm:"size:100;not null"`
+	Shares   []ExpenseShare `json:"shares" gorm:"type:text;serializer:json"`
+	Memo     string         `json:"memo" gorm:"size:500"`
+}
+
+func (entity *SharedExpense) TableName() string {
+	return "finance_shared_expense"
+}
+
+// Settlement 参与人之间的结清付款
+type Settlement struct {
+	model.TenantBaseModel
+	FromParticipant string `json:"from_participant" gorm:"size:100;not null"`
+	ToParticipant   string `json:"to_participant" gorm:"size:100;not null"`
+	Amount          int64  `json:"amount" gorm:"not null"`
+	Currency        string `json:"currency" gorm:"size:3"`
+	Date            string `json:"date" gorm:"size:10;not null"`
+	Memo            string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Settlement) TableName() string {
+	return "finance_shared_settlement"
+}
@@ -0,0 +1,16 @@
+package iou
+
+import "errors"
+
+var (
+	ErrIouNotFound           = errors.New("借款记录不存在")
+	ErrIouAlreadySettled     = errors.New("借款已结清")
+	ErrCounterpartyRequired  = errors.New("请填写对方成员或姓名")
+	ErrDateInvalid           = errors.New("日期格式无效，应为YYYY-MM-DD")
+	ErrRepayTooLarge         = errors.New("还款金额超过未还金额")
+	ErrParticipantsRequired  = errors.New("请填写分摊参与人")
+	ErrSharesNotBalanced     = errors.New("分摊金额合计与支出金额不一致")
+	ErrSettlementSameParties = errors.New("付款人和收款人不能相同")
+	ErrSettlementParticipant = errors.New("付款人和收款人必须是该币种共同支出的参与人")
+	ErrSettlementTooLarge    = errors.New("结清金额超过付款人未结清的应付金额")
+)
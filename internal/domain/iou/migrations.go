@@ -0,0 +1,27 @@
+package iou
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建借款记录表
+	if err := db.AutoMigrate(&Iou{}); err != nil {
+		fmt.Println("创建借款记录表失败", err)
+	}
+
+	// 创建共同支出表
+	if err := db.AutoMigrate(&SharedExpense{}); err != nil {
+		fmt.Println("创建共同支出表失败", err)
+	}
+
+	// 创建共同支出结清表
+	if err := db.AutoMigrate(&Settlement{}); err != nil {
+		fmt.Println("创建共同支出结清表失败", err)
+	}
+
+	fmt.Println("Iou模块迁移完成")
+}
@@ -0,0 +1,526 @@
+package iou
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+type IouService interface {
+	CreateIou(ctx context.Context, req *request.DataRequest[CreateIouDTO]) (*response.DataResponse[IouDTO], error)
+	RepayIou(ctx context.Context, req *request.DataRequest[RepayIouDTO]) (*response.DataResponse[IouDTO], error)
+	ListIous(ctx context.Context, tenantId string, userId string, includeSettled bool) (*response.DataResponse[[]IouDTO], error)
+	// ListCounterpartyBalances 按对方和币种汇总未结清的借入借出，userId 为空时汇总全家
+	ListCounterpartyBalances(ctx context.Context, tenantId string, userId string) (*response.DataResponse[[]CounterpartyBalanceDTO], error)
+	CreateSharedExpense(ctx context.Context, req *request.DataRequest[CreateSharedExpenseDTO]) (*response.DataResponse[SharedExpenseDTO], error)
+	ListSharedExpenses(ctx context.Context, tenantId string) (*response.DataResponse[[]SharedExpenseDTO], error)
+	CreateSettlement(ctx context.Context, req *request.DataRequest[CreateSettlementDTO]) (*response.DataResponse[SettlementDTO], error)
+	ListSettlements(ctx context.Context, tenantId string) (*response.DataResponse[[]SettlementDTO], error)
+	// GetSharedBalances 返回共同支出的分摊余额和最少转账结清方案
+	GetSharedBalances(ctx context.Context, tenantId string) (*response.DataResponse[SharedBalancesDTO], error)
+	BalanceReader
+}
+
+// BalanceReader 供净资产等模块读取某日未结清的借款
+type BalanceReader interface {
+	// OpenIous 返回 asOf 当日未结清的借款。部分还款没有记录日期，
+	// 因此 asOf 早于结清日时按借款全额计算；asOf 为空时按当前未还金额
+	OpenIous(ctx context.Context, tenantId string, asOf string) ([]IouBalance, error)
+}
+
+type service struct {
+	iouRepo        repository.Repository[Iou]
+	expenseRepo    repository.Repository[SharedExpense]
+	settlementRepo repository.Repository[Settlement]
+}
+
+func NewIouService(
+	iouRepo repository.Repository[Iou],
+	expenseRepo repository.Repository[SharedExpense],
+	settlementRepo repository.Repository[Settlement],
+) IouService {
+	return &service{
+		iouRepo:        iouRepo,
+		expenseRepo:    expenseRepo,
+		settlementRepo: settlementRepo,
+	}
+}
+
+func (s *service) CreateIou(ctx context.Context, req *request.DataRequest[CreateIouDTO]) (*response.DataResponse[IouDTO], error) {
+	name := strings.TrimSpace(req.Data.CounterpartyName)
+	if len(req.Data.CounterpartyUserId) == 0 && len(name) == 0 {
+		return nil, ErrCounterpartyRequired
+	}
+	if !validDate(req.Data.Date) || (len(req.Data.DueDate) > 0 && !validDate(req.Data.DueDate)) {
+		return nil, ErrDateInvalid
+	}
+
+	iou := &Iou{
+		UserId:             req.Data.UserId,
+		Direction:          req.Data.Direction,
+		CounterpartyUserId: req.Data.CounterpartyUserId,
+		CounterpartyName:   name,
+		Amount:             req.Data.Amount,
+		Currency:           strings.ToUpper(req.Data.Currency),
+		Date:               req.Data.Date,
+		DueDate:            req.Data.DueDate,
+		Memo:               req.Data.Memo,
+		TenantBaseModel:    model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	iou, err := s.iouRepo.Add(ctx, iou)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[IouDTO]{
+		Data: toIouDTO(iou),
+	}, nil
+}
+
+func (s *service) RepayIou(ctx context.Context, req *request.DataRequest[RepayIouDTO]) (*response.DataResponse[IouDTO], error) {
+	date := req.Data.Date
+	if len(date) == 0 {
+		date = time.Now().Format(DateLayout)
+	} else if !validDate(date) {
+		return nil, ErrDateInvalid
+	}
+	iou, err := s.findIouById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if iou.Settled {
+		return nil, ErrIouAlreadySettled
+	}
+	outstanding := iou.Amount - iou.RepaidAmount
+	amount := req.Data.Amount
+	if amount == 0 {
+		amount = outstanding
+	}
+	if amount > outstanding {
+		return nil, ErrRepayTooLarge
+	}
+
+	iou.RepaidAmount += amount
+	if iou.RepaidAmount >= iou.Amount {
+		iou.Settled = true
+		iou.SettledDate = date
+	}
+	iou, err = s.iouRepo.Update(ctx, iou)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[IouDTO]{
+		Data: toIouDTO(iou),
+	}, nil
+}
+
+func (s *service) ListIous(ctx context.Context, tenantId string, userId string, includeSettled bool) (*response.DataResponse[[]IouDTO], error) {
+	ious, err := s.findIous(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ious, func(i, j int) bool {
+		return ious[i].Date > ious[j].Date
+	})
+
+	dtos := []IouDTO{}
+	for i := range ious {
+		if !includeSettled && ious[i].Settled {
+			continue
+		}
+		if len(userId) > 0 && ious[i].UserId != userId {
+			continue
+		}
+		dtos = append(dtos, toIouDTO(&ious[i]))
+	}
+	return &response.DataResponse[[]IouDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) ListCounterpartyBalances(ctx context.Context, tenantId string, userId string) (*response.DataResponse[[]CounterpartyBalanceDTO], error) {
+	ious, err := s.findIous(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	index := map[string]*CounterpartyBalanceDTO{}
+	keys := []string{}
+	for _, iou := range ious {
+		if iou.Settled || (len(userId) > 0 && iou.UserId != userId) {
+			continue
+		}
+		// 不同币种的借款分开汇总
+		key := iou.Currency + ":" + iou.CounterpartyUserId
+		if len(iou.CounterpartyUserId) == 0 {
+			key = iou.Currency + ":name:" + iou.CounterpartyName
+		}
+		balance, ok := index[key]
+		if !ok {
+			balance = &CounterpartyBalanceDTO{
+				CounterpartyUserId: iou.CounterpartyUserId,
+				CounterpartyName:   iou.CounterpartyName,
+				Currency:           iou.Currency,
+			}
+			index[key] = balance
+			keys = append(keys, key)
+		}
+		outstanding := iou.Amount - iou.RepaidAmount
+		if iou.Direction == DirectionLent {
+			balance.Receivable += outstanding
+		} else {
+			balance.Payable += outstanding
+		}
+		balance.OpenCount++
+	}
+
+	dtos := make([]CounterpartyBalanceDTO, 0, len(keys))
+	for _, key := range keys {
+		balance := index[key]
+		balance.Net = balance.Receivable - balance.Payable
+		dtos = append(dtos, *balance)
+	}
+	sort.SliceStable(dtos, func(i, j int) bool {
+		return abs(dtos[i].Net) > abs(dtos[j].Net)
+	})
+	return &response.DataResponse[[]CounterpartyBalanceDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) CreateSharedExpense(ctx context.Context, req *request.DataRequest[CreateSharedExpenseDTO]) (*response.DataResponse[SharedExpenseDTO], error) {
+	if !validDate(req.Data.Date) {
+		return nil, ErrDateInvalid
+	}
+
+	var shares []ExpenseShare
+	if req.Data.SplitMode == SplitEqual {
+		participants := []string{}
+		for _, participant := range req.Data.Participants {
+			participant = strings.TrimSpace(participant)
+			if len(participant) > 0 && !slices.Contains(participants, participant) {
+				participants = append(participants, participant)
+			}
+		}
+		if len(participants) == 0 {
+			return nil, ErrParticipantsRequired
+		}
+		shares = splitEqually(req.Data.Amount, participants)
+	} else {
+		if len(req.Data.Shares) == 0 {
+			return nil, ErrParticipantsRequired
+		}
+		var total int64
+		for _, share := range req.Data.Shares {
+			share.Participant = strings.TrimSpace(share.Participant)
+			if len(share.Participant) == 0 || share.Amount < 0 {
+				return nil, ErrParticipantsRequired
+			}
+			total += share.Amount
+			shares = append(shares, share)
+		}
+		if total != req.Data.Amount {
+			return nil, ErrSharesNotBalanced
+		}
+	}
+
+	expense := &SharedExpense{
+		Title:           strings.TrimSpace(req.Data.Title),
+		Date:            req.Data.Date,
+		Amount:          req.Data.Amount,
+		Currency:        strings.ToUpper(req.Data.Currency),
+		PaidBy:          strings.TrimSpace(req.Data.PaidBy),
+		Shares:          shares,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	expense, err := s.expenseRepo.Add(ctx, expense)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[SharedExpenseDTO]{
+		Data: toSharedExpenseDTO(expense),
+	}, nil
+}
+
+func (s *service) ListSharedExpenses(ctx context.Context, tenantId string) (*response.DataResponse[[]SharedExpenseDTO], error) {
+	expenses, err := s.findSharedExpenses(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(expenses, func(i, j int) bool {
+		return expenses[i].Date > expenses[j].Date
+	})
+
+	dtos := make([]SharedExpenseDTO, 0, len(expenses))
+	for i := range expenses {
+		dtos = append(dtos, toSharedExpenseDTO(&expenses[i]))
+	}
+	return &response.DataResponse[[]SharedExpenseDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) CreateSettlement(ctx context.Context, req *request.DataRequest[CreateSettlementDTO]) (*response.DataResponse[SettlementDTO], error) {
+	from := strings.TrimSpace(req.Data.FromParticipant)
+	to := strings.TrimSpace(req.Data.ToParticipant)
+	if from == to {
+		return nil, ErrSettlementSameParties
+	}
+	date := req.Data.Date
+	if len(date) == 0 {
+		date = time.Now().Format(DateLayout)
+	} else if !validDate(date) {
+		return nil, ErrDateInvalid
+	}
+	currency := strings.ToUpper(req.Data.Currency)
+	expenses, err := s.findSharedExpenses(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := s.findSettlements(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant(expenses, currency, from) || !isParticipant(expenses, currency, to) {
+		return nil, ErrSettlementParticipant
+	}
+	for _, balance := range computeBalances(expenses, settlements) {
+		if balance.Currency == currency && balance.Participant == from && req.Data.Amount > -balance.Net {
+			return nil, ErrSettlementTooLarge
+		}
+	}
+
+	settlement := &Settlement{
+		FromParticipant: from,
+		ToParticipant:   to,
+		Amount:          req.Data.Amount,
+		Currency:        currency,
+		Date:            date,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	settlement, err = s.settlementRepo.Add(ctx, settlement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[SettlementDTO]{
+		Data: toSettlementDTO(settlement),
+	}, nil
+}
+
+func (s *service) ListSettlements(ctx context.Context, tenantId string) (*response.DataResponse[[]SettlementDTO], error) {
+	settlements, err := s.findSettlements(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(settlements, func(i, j int) bool {
+		return settlements[i].Date > settlements[j].Date
+	})
+
+	dtos := make([]SettlementDTO, 0, len(settlements))
+	for i := range settlements {
+		dtos = append(dtos, toSettlementDTO(&settlements[i]))
+	}
+	return &response.DataResponse[[]SettlementDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetSharedBalances(ctx context.Context, tenantId string) (*response.DataResponse[SharedBalancesDTO], error) {
+	expenses, err := s.findSharedExpenses(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := s.findSettlements(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := computeBalances(expenses, settlements)
+	return &response.DataResponse[SharedBalancesDTO]{
+		Data: SharedBalancesDTO{
+			Balances: balances,
+			Plan:     settlementPlan(balances),
+		},
+	}, nil
+}
+
+func (s *service) OpenIous(ctx context.Context, tenantId string, asOf string) ([]IouBalance, error) {
+	ious, err := s.findIous(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := []IouBalance{}
+	for _, iou := range ious {
+		outstanding := iou.Amount - iou.RepaidAmount
+		if len(asOf) > 0 {
+			if iou.Date > asOf || (iou.Settled && iou.SettledDate <= asOf) {
+				continue
+			}
+			if iou.Settled {
+				outstanding = iou.Amount
+			}
+		}
+		if outstanding <= 0 {
+			continue
+		}
+		balances = append(balances, IouBalance{
+			IouId:              iou.Id,
+			UserId:             iou.UserId,
+			Direction:          iou.Direction,
+			CounterpartyUserId: iou.CounterpartyUserId,
+			CounterpartyName:   iou.CounterpartyName,
+			Currency:           iou.Currency,
+			Outstanding:        outstanding,
+		})
+	}
+	return balances, nil
+}
+
+func toIouDTO(iou *Iou) IouDTO {
+	return IouDTO{
+		Id:                 iou.Id,
+		TenantId:           iou.TenantId,
+		UserId:             iou.UserId,
+		Direction:          iou.Direction,
+		CounterpartyUserId: iou.CounterpartyUserId,
+		CounterpartyName:   iou.CounterpartyName,
+		Amount:             iou.Amount,
+		RepaidAmount:       iou.RepaidAmount,
+		Outstanding:        iou.Amount - iou.RepaidAmount,
+		Currency:           iou.Currency,
+		Date:               iou.Date,
+		DueDate:            iou.DueDate,
+		Settled:            iou.Settled,
+		SettledDate:        iou.SettledDate,
+		Memo:               iou.Memo,
+	}
+}
+
+func toSharedExpenseDTO(expense *SharedExpense) SharedExpenseDTO {
+	return SharedExpenseDTO{
+		Id:       expense.Id,
+		TenantId: expense.TenantId,
+		Title:    expense.Title,
+		Date:     expense.Date,
+		Amount:   expense.Amount,
+		Currency: expense.Currency,
+		PaidBy:   expense.PaidBy,
+		Shares:   expense.Shares,
+		Memo:     expense.Memo,
+	}
+}
+
+func toSettlementDTO(settlement *Settlement) SettlementDTO {
+	return SettlementDTO{
+		Id:              settlement.Id,
+		FromParticipant: settlement.FromParticipant,
+		ToParticipant:   settlement.ToParticipant,
+		Amount:          settlement.Amount,
+		Currency:        settlement.Currency,
+		Date:            settlement.Date,
+		Memo:            settlement.Memo,
+	}
+}
+
+// isParticipant 判断 participant 是否付过或分摊过该币种的共同支出
+func isParticipant(expenses []SharedExpense, currency string, participant string) bool {
+	for _, expense := range expenses {
+		if expense.Currency != currency {
+			continue
+		}
+		if expense.PaidBy == participant {
+			return true
+		}
+		for _, share := range expense.Shares {
+			if share.Participant == participant {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func (s *service) findIouById(ctx context.Context, tenantId string, id string) (*Iou, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	ious, err := s.iouRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(ious) == 0 {
+		return nil, ErrIouNotFound
+	}
+	return &ious[0], nil
+}
+
+func (s *service) findIous(ctx context.Context, tenantId string) ([]Iou, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.iouRepo.Query(ctx, query)
+}
+
+func (s *service) findSharedExpenses(ctx context.Context, tenantId string) ([]SharedExpense, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.expenseRepo.Query(ctx, query)
+}
+
+func (s *service) findSettlements(ctx context.Context, tenantId string) ([]Settlement, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.settlementRepo.Query(ctx, query)
+}
@@ -0,0 +1,113 @@
+package iou
+
+import (
+	"sort"
+)
+
+// computeBalances 按币种汇总每位参与人已付、应摊和净额，已记录的结清付款计入净额。
+// 不同币种的金额不能直接相加，同一参与人在每个币种下各有一条余额
+func computeBalances(expenses []SharedExpense, settlements []Settlement) []ParticipantBalanceDTO {
+	type key struct {
+		currency    string
+		participant string
+	}
+	index := map[key]*ParticipantBalanceDTO{}
+	get := func(currency string, participant string) *ParticipantBalanceDTO {
+		k := key{currency, participant}
+		balance, ok := index[k]
+		if !ok {
+			balance = &ParticipantBalanceDTO{Participant: participant, Currency: currency}
+			index[k] = balance
+		}
+		return balance
+	}
+
+	for _, expense := range expenses {
+		get(expense.Currency, expense.PaidBy).Paid += expense.Amount
+		for _, share := range expense.Shares {
+			get(expense.Currency, share.Participant).Share += share.Amount
+		}
+	}
+	for _, settlement := range settlements {
+		get(settlement.Currency, settlement.FromParticipant).Net += settlement.Amount
+		get(settlement.Currency, settlement.ToParticipant).Net -= settlement.Amount
+	}
+
+	balances := make([]ParticipantBalanceDTO, 0, len(index))
+	for _, balance := range index {
+		balance.Net += balance.Paid - balance.Share
+		balances = append(balances, *balance)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Currency != balances[j].Currency {
+			return balances[i].Currency < balances[j].Currency
+		}
+		if balances[i].Net != balances[j].Net {
+			return balances[i].Net > balances[j].Net
+		}
+		return balances[i].Participant < balances[j].Participant
+	})
+	return balances
+}
+
+// settlementPlan 每个币种内由应付最多者向应收最多者依次付款，n 个有余额的参与人最多需要 n-1 笔转账
+func settlementPlan(balances []ParticipantBalanceDTO) []TransferDTO {
+	type party struct {
+		name   string
+		amount int64
+	}
+	currencies := []string{}
+	creditors := map[string][]party{}
+	debtors := map[string][]party{}
+	for _, balance := range balances {
+		if _, ok := creditors[balance.Currency]; !ok {
+			currencies = append(currencies, balance.Currency)
+			creditors[balance.Currency] = []party{}
+		}
+		switch {
+		case balance.Net > 0:
+			creditors[balance.Currency] = append(creditors[balance.Currency], party{balance.Participant, balance.Net})
+		case balance.Net < 0:
+			debtors[balance.Currency] = append(debtors[balance.Currency], party{balance.Participant, -balance.Net})
+		}
+	}
+	byAmount := func(parties []party) {
+		sort.SliceStable(parties, func(i, j int) bool {
+			return parties[i].amount > parties[j].amount
+		})
+	}
+
+	plan := []TransferDTO{}
+	for _, currency := range currencies {
+		cs, ds := creditors[currency], debtors[currency]
+		byAmount(cs)
+		byAmount(ds)
+		for i, j := 0, 0; i < len(cs) && j < len(ds); {
+			amount := min(cs[i].amount, ds[j].amount)
+			plan = append(plan, TransferDTO{From: ds[j].name, To: cs[i].name, Amount: amount, Currency: currency})
+			cs[i].amount -= amount
+			ds[j].amount -= amount
+			if cs[i].amount == 0 {
+				i++
+			}
+			if ds[j].amount == 0 {
+				j++
+			}
+		}
+	}
+	return plan
+}
+
+// splitEqually 平均分摊，除不尽的分按顺序分给前几位参与人
+func splitEqually(amount int64, participants []string) []ExpenseShare {
+	n := int64(len(participants))
+	shares := make([]ExpenseShare, 0, n)
+	for i, participant := range participants {
+		share := amount / n
+		if int64(i) < amount%n {
+			share++
+		}
+		shares = append(shares, ExpenseShare{Participant: participant, Amount: share})
+	}
+	return shares
+}
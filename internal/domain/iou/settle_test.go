@@ -0,0 +1,129 @@
+package iou
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitEqually(t *testing.T) {
+	tests := []struct {
+		name         string
+		amount       int64
+		participants []string
+		want         []int64
+	}{
+		{"整除", 300, []string{"a", "b", "c"}, []int64{100, 100, 100}},
+		{"余数分给前几位", 100, []string{"a", "b", "c"}, []int64{34, 33, 33}},
+		{"余数为二", 101, []string{"a", "b", "c"}, []int64{34, 34, 33}},
+		{"单人", 99, []string{"a"}, []int64{99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := splitEqually(tt.amount, tt.participants)
+			got := make([]int64, 0, len(shares))
+			total := int64(0)
+			for i, share := range shares {
+				if share.Participant != tt.participants[i] {
+					t.Errorf("share %d participant = %s, want %s", i, share.Participant, tt.participants[i])
+				}
+				got = append(got, share.Amount)
+				total += share.Amount
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shares = %v, want %v", got, tt.want)
+			}
+			if total != tt.amount {
+				t.Errorf("total = %d, want %d", total, tt.amount)
+			}
+		})
+	}
+}
+
+func TestComputeBalancesAndSettlementPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		expenses    []SharedExpense
+		settlements []Settlement
+		balances    []ParticipantBalanceDTO
+		plan        []TransferDTO
+	}{
+		{
+			name: "一人垫付三人平摊",
+			expenses: []SharedExpense{
+				{Amount: 300, Currency: "CNY", PaidBy: "a", Shares: splitEqually(300, []string{"a", "b", "c"})},
+			},
+			balances: []ParticipantBalanceDTO{
+				{Participant: "a", Currency: "CNY", Paid: 300, Share: 100, Net: 200},
+				{Participant: "b", Currency: "CNY", Share: 100, Net: -100},
+				{Participant: "c", Currency: "CNY", Share: 100, Net: -100},
+			},
+			plan: []TransferDTO{
+				{From: "b", To: "a", Amount: 100, Currency: "CNY"},
+				{From: "c", To: "a", Amount: 100, Currency: "CNY"},
+			},
+		},
+		{
+			name: "不同币种分别结算不相互抵消",
+			expenses: []SharedExpense{
+				{Amount: 200, Currency: "CNY", PaidBy: "a", Shares: splitEqually(200, []string{"a", "b"})},
+				{Amount: 100, Currency: "USD", PaidBy: "b", Shares: splitEqually(100, []string{"a", "b"})},
+			},
+			balances: []ParticipantBalanceDTO{
+				{Participant: "a", Currency: "CNY", Paid: 200, Share: 100, Net: 100},
+				{Participant: "b", Currency: "CNY", Share: 100, Net: -100},
+				{Participant: "b", Currency: "USD", Paid: 100, Share: 50, Net: 50},
+				{Participant: "a", Currency: "USD", Share: 50, Net: -50},
+			},
+			plan: []TransferDTO{
+				{From: "b", To: "a", Amount: 100, Currency: "CNY"},
+				{From: "a", To: "b", Amount: 50, Currency: "USD"},
+			},
+		},
+		{
+			name: "已结清付款只抵减同币种余额",
+			expenses: []SharedExpense{
+				{Amount: 300, Currency: "CNY", PaidBy: "a", Shares: splitEqually(300, []string{"a", "b", "c"})},
+			},
+			settlements: []Settlement{
+				{FromParticipant: "b", ToParticipant: "a", Amount: 100, Currency: "CNY"},
+			},
+			balances: []ParticipantBalanceDTO{
+				{Participant: "a", Currency: "CNY", Paid: 300, Share: 100, Net: 100},
+				{Participant: "b", Currency: "CNY", Share: 100, Net: 0},
+				{Participant: "c", Currency: "CNY", Share: 100, Net: -100},
+			},
+			plan: []TransferDTO{
+				{From: "c", To: "a", Amount: 100, Currency: "CNY"},
+			},
+		},
+		{
+			name: "多个债权人时最大应付者先付最大应收者",
+			expenses: []SharedExpense{
+				{Amount: 400, Currency: "CNY", PaidBy: "a", Shares: splitEqually(400, []string{"a", "b", "c", "d"})},
+				{Amount: 200, Currency: "CNY", PaidBy: "b", Shares: splitEqually(200, []string{"c", "d"})},
+			},
+			balances: []ParticipantBalanceDTO{
+				{Participant: "a", Currency: "CNY", Paid: 400, Share: 100, Net: 300},
+				{Participant: "b", Currency: "CNY", Paid: 200, Share: 100, Net: 100},
+				{Participant: "c", Currency: "CNY", Share: 200, Net: -200},
+				{Participant: "d", Currency: "CNY", Share: 200, Net: -200},
+			},
+			plan: []TransferDTO{
+				{From: "c", To: "a", Amount: 200, Currency: "CNY"},
+				{From: "d", To: "a", Amount: 100, Currency: "CNY"},
+				{From: "d", To: "b", Amount: 100, Currency: "CNY"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := computeBalances(tt.expenses, tt.settlements)
+			if !reflect.DeepEqual(balances, tt.balances) {
+				t.Errorf("balances = %+v, want %+v", balances, tt.balances)
+			}
+			if plan := settlementPlan(balances); !reflect.DeepEqual(plan, tt.plan) {
+				t.Errorf("plan = %+v, want %+v", plan, tt.plan)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package loan
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// prepayment 参与还款计划计算的提前还款
+type prepayment struct {
+	Date   string
+	Amount int64
+	Mode   string
+}
+
+// buildSchedule 生成完整还款计划。利率按每期还款日生效的利率计算；
+// 还款日当天及之前的提前还款在该期之前扣减本金，利率变化或减少月供时按剩余期数重算月供
+func buildSchedule(loan *Loan, rates []LoanRateChange, prepays []prepayment) []InstallmentDTO {
+	first, err := time.Parse(DateLayout, loan.FirstPaymentDate)
+	if err != nil || loan.TermMonths <= 0 {
+		return []InstallmentDTO{}
+	}
+	rates = append([]LoanRateChange(nil), rates...)
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].EffectiveDate < rates[j].EffectiveDate
+	})
+	prepays = append([]prepayment(nil), prepays...)
+	sort.SliceStable(prepays, func(i, j int) bool {
+		return prepays[i].Date < prepays[j].Date
+	})
+
+	schedule := []InstallmentDTO{}
+	balance := loan.Principal
+	left := loan.TermMonths
+	lastRate := -1
+	recompute := true
+	var payment, principalPart int64
+	reprice := func(r float64) {
+		if loan.Method == MethodEqualPrincipal {
+			principalPart = int64(math.Round(float64(balance) / float64(left)))
+		} else {
+			payment = annuity(balance, r, left)
+		}
+		recompute = false
+	}
+	next := 0
+	for period := 1; left > 0 && balance > 0; period++ {
+		date := addMonths(first, period-1).Format(DateLayout)
+		rate := rateAt(loan.AnnualRateBps, rates, date)
+		r := monthlyRate(rate)
+		if rate != lastRate {
+			recompute = true
+			lastRate = rate
+		}
+		// 先按提前还款前的余额确定月供，缩短期限时以此月供计算剩余期数
+		if recompute {
+			reprice(r)
+		}
+
+		var prepaid int64
+		for ; next < len(prepays) && prepays[next].Date <= date; next++ {
+			amount := min(prepays[next].Amount, balance)
+			if amount <= 0 {
+				continue
+			}
+			balance -= amount
+			prepaid += amount
+			if prepays[next].Mode == PrepayReducePayment {
+				recompute = true
+			} else {
+				left = shortenedTerm(loan.Method, balance, r, payment, principalPart, left)
+				recompute = true
+			}
+		}
+		if balance == 0 {
+			schedule = append(schedule, InstallmentDTO{
+				Period:        period,
+				Date:          date,
+				AnnualRateBps: rate,
+				Prepayment:    prepaid,
+			})
+			break
+		}
+
+		if recompute {
+			reprice(r)
+		}
+
+		interest := int64(math.Round(float64(balance) * r))
+		principal := principalPart
+		if loan.Method != MethodEqualPrincipal {
+			principal = payment - interest
+		}
+		if left == 1 || principal > balance {
+			principal = balance
+		}
+		principal = max(principal, 0)
+		balance -= principal
+		left--
+
+		schedule = append(schedule, InstallmentDTO{
+			Period:        period,
+			Date:          date,
+			Payment:       principal + interest,
+			Principal:     principal,
+			Interest:      interest,
+			Balance:       balance,
+			AnnualRateBps: rate,
+			Prepayment:    prepaid,
+		})
+	}
+	return schedule
+}
+
+// annuity 等额本息月供：P·r·(1+r)^n / ((1+r)^n − 1)
+func annuity(balance int64, r float64, n int) int64 {
+	if r == 0 {
+		return int64(math.Ceil(float64(balance) / float64(n)))
+	}
+	f := math.Pow(1+r, float64(n))
+	return int64(math.Round(float64(balance) * r * f / (f - 1)))
+}
+
+// shortenedTerm 缩短期限方式下，按原月供（等额本息）或原每期本金（等额本金）还清剩余本金所需的期数
+func shortenedTerm(method string, balance int64, r float64, payment int64, principalPart int64, left int) int {
+	var n float64
+	if method == MethodEqualPrincipal {
+		if principalPart <= 0 {
+			return left
+		}
+		n = math.Ceil(float64(balance) / float64(principalPart))
+	} else {
+		if payment <= 0 {
+			return left
+		}
+		if r == 0 {
+			n = math.Ceil(float64(balance) / float64(payment))
+		} else {
+			a := float64(payment)
+			if a <= float64(balance)*r {
+				return left
+			}
+			n = math.Ceil(math.Log(a/(a-float64(balance)*r)) / math.Log(1+r))
+		}
+	}
+	return max(min(int(n), left), 1)
+}
+
+func rateAt(base int, rates []LoanRateChange, date string) int {
+	rate := base
+	for _, change := range rates {
+		if change.EffectiveDate > date {
+			break
+		}
+		rate = change.AnnualRateBps
+	}
+	return rate
+}
+
+func monthlyRate(bps int) float64 {
+	return float64(bps) / 10000 / 12
+}
+
+// addMonths 按月推算还款日，月末日期不存在时取当月最后一天
+func addMonths(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	target := time.Date(y, m+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := target.AddDate(0, 1, -1).Day()
+	return time.Date(target.Year(), target.Month(), min(d, last), 0, 0, 0, 0, time.UTC)
+}
+
+func summarize(schedule []InstallmentDTO) ScheduleSummaryDTO {
+	summary := ScheduleSummaryDTO{}
+	for _, row := range schedule {
+		summary.TotalInterest += row.Interest
+		summary.TotalPayment += row.Payment + row.Prepayment
+		if row.Payment > 0 {
+			summary.Periods++
+		}
+		summary.PayoffDate = row.Date
+	}
+	return summary
+}
@@ -0,0 +1,165 @@
+package loan
+
+import (
+	"testing"
+	"time"
+)
+
+func testLoan(principal int64, rateBps int, months int, method string) *Loan {
+	return &Loan{
+		Principal:        principal,
+		AnnualRateBps:    rateBps,
+		TermMonths:       months,
+		Method:           method,
+		StartDate:        "2023-12-15",
+		FirstPaymentDate: "2024-01-15",
+	}
+}
+
+func TestBuildSchedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		loan           *Loan
+		rates          []LoanRateChange
+		prepays        []prepayment
+		periods        int
+		firstPay       int64
+		lastPay        int64
+		firstPrincipal int64
+	}{
+		{
+			name:           "等额本息零利率",
+			loan:           testLoan(12000, 0, 12, MethodEqualInstallment),
+			periods:        12,
+			firstPay:       1000,
+			lastPay:        1000,
+			firstPrincipal: 1000,
+		},
+		{
+			name:           "等额本金",
+			loan:           testLoan(120000, 1200, 12, MethodEqualPrincipal),
+			periods:        12,
+			firstPay:       11200,
+			lastPay:        10100,
+			firstPrincipal: 10000,
+		},
+		{
+			name:           "首期前提前还款缩短期限时按原月供计算期数",
+			loan:           testLoan(12000, 0, 12, MethodEqualInstallment),
+			prepays:        []prepayment{{Date: "2024-01-10", Amount: 6000, Mode: PrepayShortenTerm}},
+			periods:        6,
+			firstPay:       1000,
+			lastPay:        1000,
+			firstPrincipal: 1000,
+		},
+		{
+			name:           "首期前提前还款减少月供时期数不变",
+			loan:           testLoan(12000, 0, 12, MethodEqualInstallment),
+			prepays:        []prepayment{{Date: "2024-01-10", Amount: 6000, Mode: PrepayReducePayment}},
+			periods:        12,
+			firstPay:       500,
+			lastPay:        500,
+			firstPrincipal: 500,
+		},
+		{
+			name:           "利率调整后重算月供",
+			loan:           testLoan(12000, 0, 12, MethodEqualInstallment),
+			rates:          []LoanRateChange{{EffectiveDate: "2024-07-01", AnnualRateBps: 1200}},
+			periods:        12,
+			firstPay:       1000,
+			lastPay:        1035,
+			firstPrincipal: 1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := buildSchedule(tt.loan, tt.rates, tt.prepays)
+			summary := summarize(schedule)
+			if summary.Periods != tt.periods {
+				t.Fatalf("periods = %d, want %d", summary.Periods, tt.periods)
+			}
+			first, last := schedule[0], schedule[len(schedule)-1]
+			if first.Payment != tt.firstPay {
+				t.Errorf("first payment = %d, want %d", first.Payment, tt.firstPay)
+			}
+			if first.Principal != tt.firstPrincipal {
+				t.Errorf("first principal = %d, want %d", first.Principal, tt.firstPrincipal)
+			}
+			if last.Payment != tt.lastPay {
+				t.Errorf("last payment = %d, want %d", last.Payment, tt.lastPay)
+			}
+			if last.Balance != 0 {
+				t.Errorf("last balance = %d, want 0", last.Balance)
+			}
+			repaid := int64(0)
+			for _, row := range schedule {
+				repaid += row.Principal + row.Prepayment
+			}
+			if repaid != tt.loan.Principal {
+				t.Errorf("repaid principal = %d, want %d", repaid, tt.loan.Principal)
+			}
+		})
+	}
+}
+
+func TestBuildScheduleInterestBearing(t *testing.T) {
+	loan := testLoan(1000000, 490, 240, MethodEqualInstallment)
+	schedule := buildSchedule(loan, nil, nil)
+	if len(schedule) != 240 {
+		t.Fatalf("periods = %d, want 240", len(schedule))
+	}
+	payment := annuity(loan.Principal, monthlyRate(loan.AnnualRateBps), loan.TermMonths)
+	for _, row := range schedule[:len(schedule)-1] {
+		if row.Payment != payment {
+			t.Fatalf("period %d payment = %d, want %d", row.Period, row.Payment, payment)
+		}
+	}
+	if last := schedule[len(schedule)-1]; last.Balance != 0 {
+		t.Errorf("last balance = %d, want 0", last.Balance)
+	}
+}
+
+func TestShortenedTerm(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		balance       int64
+		r             float64
+		payment       int64
+		principalPart int64
+		left          int
+		want          int
+	}{
+		{"零利率按月供整除", MethodEqualInstallment, 6000, 0, 1000, 0, 12, 6},
+		{"零利率不足一期向上取整", MethodEqualInstallment, 6500, 0, 1000, 0, 12, 7},
+		{"等额本金按每期本金计算", MethodEqualPrincipal, 50000, 0.01, 0, 10000, 12, 5},
+		{"月供不足以覆盖利息时保持原期数", MethodEqualInstallment, 100000, 0.01, 1000, 0, 12, 12},
+		{"不会超过剩余期数", MethodEqualInstallment, 20000, 0, 1000, 0, 12, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortenedTerm(tt.method, tt.balance, tt.r, tt.payment, tt.principalPart, tt.left); got != tt.want {
+				t.Errorf("shortenedTerm() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		start  string
+		months int
+		want   string
+	}{
+		{"2024-01-31", 1, "2024-02-29"},
+		{"2023-01-31", 1, "2023-02-28"},
+		{"2024-01-31", 2, "2024-03-31"},
+		{"2024-11-15", 3, "2025-02-15"},
+	}
+	for _, tt := range tests {
+		start, _ := time.Parse(DateLayout, tt.start)
+		if got := addMonths(start, tt.months).Format(DateLayout); got != tt.want {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.start, tt.months, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package loan
+
+type LoanDTO struct {
+	Id               string `json:"id"`
+	TenantId         string `json:"tenant_id"`
+	Name             string `json:"name"`
+	Kind             string `json:"kind"`
+	Lender           string `json:"lender"`
+	Principal        int64  `json:"principal"`
+	Currency         string `json:"currency"`
+	RateType         string `json:"rate_type"`
+	AnnualRateBps    int    `json:"annual_rate_bps"`
+	LprSpreadBps     int    `json:"lpr_spread_bps"`
+	TermMonths       int    `json:"term_months"`
+	Method           string `json:"method"`
+	StartDate        string `json:"start_date"`
+	FirstPaymentDate string `json:"first_payment_date"`
+	OwnerUserId      string `json:"owner_user_id"`
+	Status           string `json:"status"`
+	Memo             string `json:"memo"`
+	// 当前执行利率
+	CurrentRateBps int `json:"current_rate_bps"`
+	// 按已录入还款计算的剩余本金
+	OutstandingPrincipal int64              `json:"outstanding_principal"`
+	PaidPeriods          int                `json:"paid_periods"`
+	NextInstallment      *InstallmentDTO    `json:"next_installment"`
+	Summary              ScheduleSummaryDTO `json:"summary"`
+}
+
+type InstallmentDTO struct {
+	Period        int    `json:"period"`
+	Date          string `json:"date"`
+	Payment       int64  `json:"payment"`
+	Principal     int64  `json:"principal"`
+	Interest      int64  `json:"interest"`
+	Balance       int64  `json:"balance"`
+	AnnualRateBps int    `json:"annual_rate_bps"`
+	// 该期之前扣减的提前还款
+	Prepayment int64 `json:"prepayment"`
+}
+
+type ScheduleSummaryDTO struct {
+	Periods       int    `json:"periods"`
+	TotalPayment  int64  `json:"total_payment"`
+	TotalInterest int64  `json:"total_interest"`
+	PayoffDate    string `json:"payoff_date"`
+}
+
+type ScheduleDTO struct {
+	Summary      ScheduleSummaryDTO `json:"summary"`
+	Installments []InstallmentDTO   `json:"installments"`
+}
+
+type CreateLoanDTO struct {
+	TenantId      string `json:"tenant_id" binding:"required"`
+	Name          string `json:"name" binding:"required,max_len=200"`
+	Kind          string `json:"kind" binding:"required,oneof=mortgage car consumer other"`
+	Lender        string `json:"lender" binding:"omitempty,max_len=200"`
+	Principal     int64  `json:"principal" binding:"required,min=1"`
+	Currency      string `json:"currency" binding:"omitempty,len=3"`
+	RateType      string `json:"rate_type" binding:"required,oneof=fixed lpr"`
+	AnnualRateBps int    `json:"annual_rate_bps" binding:"omitempty,min=0,max=10000"`
+	// 浮动利率贷款可只填 LPR 和加点
+	LprBps           int    `json:"lpr_bps" binding:"omitempty,min=0,max=10000"`
+	LprSpreadBps     int    `json:"lpr_spread_bps" binding:"omitempty"`
+	TermMonths       int    `json:"term_months" binding:"required,min=1,max=480"`
+	Method           string `json:"method" binding:"required,oneof=equal_installment equal_principal"`
+	StartDate        string `json:"start_date" binding:"required"`
+	FirstPaymentDate string `json:"first_payment_date" binding:"required"`
+	OwnerUserId      string `json:"owner_user_id" binding:"omitempty"`
+	Memo             string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type UpdateLoanDTO struct {
+	TenantId    string `json:"tenant_id" binding:"required"`
+	Id          string `json:"id"`
+	Name        string `json:"name" binding:"omitempty,max_len=200"`
+	Lender      string `json:"lender" binding:"omitempty,max_len=200"`
+	OwnerUserId string `json:"owner_user_id" binding:"omitempty"`
+	Memo        string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type CreateRateChangeDTO struct {
+	TenantId      string `json:"tenant_id" binding:"required"`
+	LoanId        string `json:"loan_id"`
+	EffectiveDate string `json:"effective_date" binding:"required"`
+	// 直接填写新的年利率，或对浮动利率贷款填写新的 LPR
+	AnnualRateBps int    `json:"annual_rate_bps" binding:"omitempty,min=0,max=10000"`
+	LprBps        int    `json:"lpr_bps" binding:"omitempty,min=0,max=10000"`
+	Memo          string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type RateChangeDTO struct {
+	Id            string `json:"id"`
+	EffectiveDate string `json:"effective_date"`
+	AnnualRateBps int    `json:"annual_rate_bps"`
+	LprBps        int    `json:"lpr_bps"`
+	Memo          string `json:"memo"`
+}
+
+type CreatePaymentDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	LoanId   string `json:"loan_id"`
+	// 为空时按当期计划金额
+	Amount int64 `json:"amount" binding:"omitempty,min=1"`
+	// 为空时为今天
+	Date string `json:"date" binding:"omitempty"`
+	Memo string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type CreatePrepaymentDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	LoanId   string `json:"loan_id"`
+	Amount   int64  `json:"amount" binding:"required,min=1"`
+	Date     string `json:"date" binding:"required"`
+	Mode     string `json:"mode" binding:"required,oneof=shorten_term reduce_payment"`
+	Memo     string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type PaymentDTO struct {
+	Id         string `json:"id"`
+	LoanId     string `json:"loan_id"`
+	Kind       string `json:"kind"`
+	Period     int    `json:"period"`
+	Date       string `json:"date"`
+	Amount     int64  `json:"amount"`
+	Principal  int64  `json:"principal"`
+	Interest   int64  `json:"interest"`
+	PrepayMode string `json:"prepay_mode"`
+	Memo       string `json:"memo"`
+}
+
+type LoanDetailDTO struct {
+	Loan        LoanDTO         `json:"loan"`
+	RateChanges []RateChangeDTO `json:"rate_changes"`
+	Payments    []PaymentDTO    `json:"payments"`
+}
+
+// PrepaymentScenarioDTO 提前还款测算结果，不保存
+type PrepaymentScenarioDTO struct {
+	Current       ScheduleSummaryDTO `json:"current"`
+	Scenario      ScheduleSummaryDTO `json:"scenario"`
+	InterestSaved int64              `json:"interest_saved"`
+	PeriodsSaved  int                `json:"periods_saved"`
+	// 提前还款后的首期月供
+	NewPayment   int64            `json:"new_payment"`
+	Installments []InstallmentDTO `json:"installments"`
+}
+
+type LoanBalance struct {
+	LoanId      string `json:"loan_id"`
+	Name        string `json:"name"`
+	OwnerUserId string `json:"owner_user_id"`
+	Currency    string `json:"currency"`
+	Outstanding int64  `json:"outstanding"`
+}
@@ -0,0 +1,94 @@
+package loan
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	LoanMortgage = "mortgage"
+	LoanCar      = "car"
+	LoanConsumer = "consumer"
+	LoanOther    = "other"
+
+	// 等额本息
+	MethodEqualInstallment = "equal_installment"
+	// 等额本金
+	MethodEqualPrincipal = "equal_principal"
+
+	RateTypeFixed = "fixed"
+	// 按 LPR 加点浮动
+	RateTypeLpr = "lpr"
+
+	LoanStatusActive  = "active"
+	LoanStatusPaidOff = "paid_off"
+
+	PaymentRegular    = "regular"
+	PaymentPrepayment = "prepayment"
+
+	// 提前还款后缩短期限，月供基本不变
+	PrepayShortenTerm = "shorten_term"
+	// 提前还款后期限不变，减少月供
+	PrepayReducePayment = "reduce_payment"
+
+	DateLayout = "2006-01-02"
+)
+
+// Loan 贷款，利率均以基点（万分之一）保存，410 表示年利率 4.10%
+type Loan struct {
+	model.TenantBaseModel
+	Name          string `json:"name" gorm:"size:200;not null"`
+	Kind          string `json:"kind" gorm:"size:20;not null"`
+	Lender        string `json:"lender" gorm:"size:200"`
+	Principal     int64  `json:"principal" gorm:"not null"`
+	Currency      string `json:"currency" gorm:"size:3"`
+	RateType      string `json:"rate_type" gorm:"size:10;not null"`
+	AnnualRateBps int    `json:"annual_rate_bps" gorm:"not null"`
+	// LPR 加点，可为负数
+	LprSpreadBps     int    `json:"lpr_spread_bps"`
+	TermMonths       int    `json:"term_months" gorm:"not null"`
+	Method           string `json:"method" gorm:"size:20;not null"`
+	StartDate        string `json:"start_date" gorm:"size:10;not null"`
+	FirstPaymentDate string `json:"first_payment_date" gorm:"size:10;not null"`
+	OwnerUserId      string `json:"owner_user_id" gorm:"size:32;index"`
+	Status           string `json:"status" gorm:"size:20;not null;index"`
+	Memo             string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Loan) TableName() string {
+	return "finance_loan"
+}
+
+// LoanRateChange 手工录入的利率调整，从生效日起的还款期按新利率计息
+type LoanRateChange struct {
+	model.TenantBaseModel
+	LoanId        string `json:"loan_id" gorm:"size:32;not null;index"`
+	EffectiveDate string `json:"effective_date" gorm:"size:10;not null"`
+	AnnualRateBps int    `json:"annual_rate_bps" gorm:"not null"`
+	// 浮动利率贷款调整时的 LPR
+	LprBps int    `json:"lpr_bps"`
+	Memo   string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *LoanRateChange) TableName() string {
+	return "finance_loan_rate_change"
+}
+
+// LoanPayment 实际还款记录，按还款计划拆分为本金和利息
+type LoanPayment struct {
+	model.TenantBaseModel
+	LoanId string `json:"loan_id" gorm:"size:32;not null;index"`
+	Kind   string `json:"kind" gorm:"size:20;not null"`
+	// 对应还款计划的期数，提前还款为 0
+	Period    int    `json:"period"`
+	Date      string `json:"date" gorm:"size:10;not null"`
+	Amount    int64  `json:"amount" gorm:"not null"`
+	Principal int64  `json:"principal"`
+	Interest  int64  `json:"interest"`
+	// 提前还款方式
+	PrepayMode string `json:"prepay_mode" gorm:"size:20"`
+	Memo       string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *LoanPayment) TableName() string {
+	return "finance_loan_payment"
+}
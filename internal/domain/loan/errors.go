@@ -0,0 +1,14 @@
+package loan
+
+import "errors"
+
+var (
+	ErrLoanNotFound         = errors.New("贷款不存在")
+	ErrLoanPaidOff          = errors.New("贷款已结清")
+	ErrDateInvalid          = errors.New("日期格式无效，应为YYYY-MM-DD")
+	ErrFirstPaymentTooEarly = errors.New("首次还款日不能早于放款日")
+	ErrRateRequired         = errors.New("请填写年利率或LPR")
+	ErrRateTypeMismatch     = errors.New("固定利率贷款不能按LPR调整")
+	ErrPaymentTooLarge      = errors.New("还款金额超过剩余本金")
+	ErrNoInstallmentDue     = errors.New("还款计划已全部还清")
+)
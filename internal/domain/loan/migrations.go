@@ -0,0 +1,27 @@
+package loan
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建贷款表
+	if err := db.AutoMigrate(&Loan{}); err != nil {
+		fmt.Println("创建贷款表失败", err)
+	}
+
+	// 创建贷款利率调整表
+	if err := db.AutoMigrate(&LoanRateChange{}); err != nil {
+		fmt.Println("创建贷款利率调整表失败", err)
+	}
+
+	// 创建贷款还款记录表
+	if err := db.AutoMigrate(&LoanPayment{}); err != nil {
+		fmt.Println("创建贷款还款记录表失败", err)
+	}
+
+	fmt.Println("Loan模块迁移完成")
+}
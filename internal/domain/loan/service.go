@@ -0,0 +1,620 @@
+package loan
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+type LoanService interface {
+	CreateLoan(ctx context.Context, req *request.DataRequest[CreateLoanDTO]) (*response.DataResponse[LoanDTO], error)
+	UpdateLoan(ctx context.Context, req *request.DataRequest[UpdateLoanDTO]) (*response.DataResponse[LoanDTO], error)
+	ListLoans(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]LoanDTO], error)
+	GetLoan(ctx context.Context, tenantId string, id string) (*response.DataResponse[LoanDetailDTO], error)
+	GetSchedule(ctx context.Context, tenantId string, id string) (*response.DataResponse[ScheduleDTO], error)
+	AddRateChange(ctx context.Context, req *request.DataRequest[CreateRateChangeDTO]) (*response.DataResponse[LoanDTO], error)
+	// RecordPayment 录入一期还款，按剩余本金和当期利率拆分本金与利息
+	RecordPayment(ctx context.Context, req *request.DataRequest[CreatePaymentDTO]) (*response.DataResponse[PaymentDTO], error)
+	RecordPrepayment(ctx context.Context, req *request.DataRequest[CreatePrepaymentDTO]) (*response.DataResponse[PaymentDTO], error)
+	// PreviewPrepayment 测算一笔提前还款可节省的利息，不保存
+	PreviewPrepayment(ctx context.Context, req *request.DataRequest[CreatePrepaymentDTO]) (*response.DataResponse[PrepaymentScenarioDTO], error)
+	BalanceReader
+}
+
+// BalanceReader 供净资产等模块读取贷款在某日的剩余本金
+type BalanceReader interface {
+	// LoanBalances 返回 asOf 当日已放款贷款的剩余本金，只计入 asOf（含）之前的还款，asOf 为空时按全部还款；
+	// 没有还款记录的贷款按还款计划估算
+	LoanBalances(ctx context.Context, tenantId string, asOf string) ([]LoanBalance, error)
+}
+
+type service struct {
+	loanRepo    repository.Repository[Loan]
+	rateRepo    repository.Repository[LoanRateChange]
+	paymentRepo repository.Repository[LoanPayment]
+}
+
+func NewLoanService(
+	loanRepo repository.Repository[Loan],
+	rateRepo repository.Repository[LoanRateChange],
+	paymentRepo repository.Repository[LoanPayment],
+) LoanService {
+	return &service{
+		loanRepo:    loanRepo,
+		rateRepo:    rateRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+func (s *service) CreateLoan(ctx context.Context, req *request.DataRequest[CreateLoanDTO]) (*response.DataResponse[LoanDTO], error) {
+	if !validDate(req.Data.StartDate) || !validDate(req.Data.FirstPaymentDate) {
+		return nil, ErrDateInvalid
+	}
+	if req.Data.FirstPaymentDate < req.Data.StartDate {
+		return nil, ErrFirstPaymentTooEarly
+	}
+	rate := req.Data.AnnualRateBps
+	if req.Data.RateType == RateTypeLpr && rate == 0 {
+		if req.Data.LprBps == 0 {
+			return nil, ErrRateRequired
+		}
+		rate = req.Data.LprBps + req.Data.LprSpreadBps
+	}
+
+	loan := &Loan{
+		Name:             strings.TrimSpace(req.Data.Name),
+		Kind:             req.Data.Kind,
+		Lender:           req.Data.Lender,
+		Principal:        req.Data.Principal,
+		Currency:         strings.ToUpper(req.Data.Currency),
+		RateType:         req.Data.RateType,
+		AnnualRateBps:    max(rate, 0),
+		LprSpreadBps:     req.Data.LprSpreadBps,
+		TermMonths:       req.Data.TermMonths,
+		Method:           req.Data.Method,
+		StartDate:        req.Data.StartDate,
+		FirstPaymentDate: req.Data.FirstPaymentDate,
+		OwnerUserId:      req.Data.OwnerUserId,
+		Status:           LoanStatusActive,
+		Memo:             req.Data.Memo,
+		TenantBaseModel:  model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	if req.Data.RateType == RateTypeFixed {
+		loan.LprSpreadBps = 0
+	}
+
+	loan, err := s.loanRepo.Add(ctx, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[LoanDTO]{
+		Data: toLoanDTO(loan, nil, nil, today()),
+	}, nil
+}
+
+func (s *service) UpdateLoan(ctx context.Context, req *request.DataRequest[UpdateLoanDTO]) (*response.DataResponse[LoanDTO], error) {
+	loan, rates, payments, err := s.loadLoan(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		loan.Name = name
+	}
+	if len(req.Data.Lender) > 0 {
+		loan.Lender = req.Data.Lender
+	}
+	if len(req.Data.OwnerUserId) > 0 {
+		loan.OwnerUserId = req.Data.OwnerUserId
+	}
+	if len(req.Data.Memo) > 0 {
+		loan.Memo = req.Data.Memo
+	}
+
+	loan, err = s.loanRepo.Update(ctx, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[LoanDTO]{
+		Data: toLoanDTO(loan, rates, payments, today()),
+	}, nil
+}
+
+func (s *service) ListLoans(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]LoanDTO], error) {
+	loans, err := s.findLoans(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.findRateChanges(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.findPayments(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	ratesByLoan := map[string][]LoanRateChange{}
+	for _, rate := range rates {
+		ratesByLoan[rate.LoanId] = append(ratesByLoan[rate.LoanId], rate)
+	}
+	paymentsByLoan := map[string][]LoanPayment{}
+	for _, payment := range payments {
+		paymentsByLoan[payment.LoanId] = append(paymentsByLoan[payment.LoanId], payment)
+	}
+
+	sort.Slice(loans, func(i, j int) bool {
+		return loans[i].StartDate < loans[j].StartDate
+	})
+	now := today()
+	dtos := []LoanDTO{}
+	for i := range loans {
+		if len(status) > 0 && loans[i].Status != status {
+			continue
+		}
+		dtos = append(dtos, toLoanDTO(&loans[i], ratesByLoan[loans[i].Id], paymentsByLoan[loans[i].Id], now))
+	}
+	return &response.DataResponse[[]LoanDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetLoan(ctx context.Context, tenantId string, id string) (*response.DataResponse[LoanDetailDTO], error) {
+	loan, rates, payments, err := s.loadLoan(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rateDTOs := make([]RateChangeDTO, 0, len(rates))
+	for _, rate := range rates {
+		rateDTOs = append(rateDTOs, RateChangeDTO{
+			Id:            rate.Id,
+			EffectiveDate: rate.EffectiveDate,
+			AnnualRateBps: rate.AnnualRateBps,
+			LprBps:        rate.LprBps,
+			Memo:          rate.Memo,
+		})
+	}
+	paymentDTOs := make([]PaymentDTO, 0, len(payments))
+	for i := range payments {
+		paymentDTOs = append(paymentDTOs, toPaymentDTO(&payments[i]))
+	}
+
+	return &response.DataResponse[LoanDetailDTO]{
+		Data: LoanDetailDTO{
+			Loan:        toLoanDTO(loan, rates, payments, today()),
+			RateChanges: rateDTOs,
+			Payments:    paymentDTOs,
+		},
+	}, nil
+}
+
+func (s *service) GetSchedule(ctx context.Context, tenantId string, id string) (*response.DataResponse[ScheduleDTO], error) {
+	loan, rates, payments, err := s.loadLoan(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+	schedule := buildSchedule(loan, rates, prepaymentsOf(payments))
+
+	return &response.DataResponse[ScheduleDTO]{
+		Data: ScheduleDTO{
+			Summary:      summarize(schedule),
+			Installments: schedule,
+		},
+	}, nil
+}
+
+func (s *service) AddRateChange(ctx context.Context, req *request.DataRequest[CreateRateChangeDTO]) (*response.DataResponse[LoanDTO], error) {
+	if !validDate(req.Data.EffectiveDate) {
+		return nil, ErrDateInvalid
+	}
+	loan, rates, payments, err := s.loadLoan(ctx, req.Data.TenantId, req.Data.LoanId)
+	if err != nil {
+		return nil, err
+	}
+	rate := req.Data.AnnualRateBps
+	if rate == 0 {
+		if req.Data.LprBps == 0 {
+			return nil, ErrRateRequired
+		}
+		if loan.RateType != RateTypeLpr {
+			return nil, ErrRateTypeMismatch
+		}
+		rate = req.Data.LprBps + loan.LprSpreadBps
+	}
+
+	change := &LoanRateChange{
+		LoanId:          loan.Id,
+		EffectiveDate:   req.Data.EffectiveDate,
+		AnnualRateBps:   max(rate, 0),
+		LprBps:          req.Data.LprBps,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(loan.TenantId, util.GenerateId()),
+	}
+	change, err = s.rateRepo.Add(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+	rates = append(rates, *change)
+
+	return &response.DataResponse[LoanDTO]{
+		Data: toLoanDTO(loan, rates, payments, today()),
+	}, nil
+}
+
+func (s *service) RecordPayment(ctx context.Context, req *request.DataRequest[CreatePaymentDTO]) (*response.DataResponse[PaymentDTO], error) {
+	date := req.Data.Date
+	if len(date) == 0 {
+		date = today().Format(DateLayout)
+	} else if !validDate(date) {
+		return nil, ErrDateInvalid
+	}
+	loan, rates, payments, err := s.loadLoan(ctx, req.Data.TenantId, req.Data.LoanId)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Status == LoanStatusPaidOff {
+		return nil, ErrLoanPaidOff
+	}
+
+	outstanding := outstandingPrincipal(loan, payments)
+	period := paidPeriods(payments) + 1
+	var due *InstallmentDTO
+	for _, row := range buildSchedule(loan, rates, prepaymentsOf(payments)) {
+		if row.Period == period && row.Payment > 0 {
+			due = &row
+			break
+		}
+	}
+	if due == nil {
+		return nil, ErrNoInstallmentDue
+	}
+
+	// 利息按实际剩余本金计算，实际还款与计划不一致时以实际为准
+	interest := int64(math.Round(float64(outstanding) * monthlyRate(due.AnnualRateBps)))
+	amount := req.Data.Amount
+	if amount == 0 {
+		amount = min(due.Payment, outstanding+interest)
+	}
+	interest = min(interest, amount)
+	principal := amount - interest
+	if principal > outstanding {
+		return nil, ErrPaymentTooLarge
+	}
+
+	payment := &LoanPayment{
+		LoanId:          loan.Id,
+		Kind:            PaymentRegular,
+		Period:          period,
+		Date:            date,
+		Amount:          amount,
+		Principal:       principal,
+		Interest:        interest,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(loan.TenantId, util.GenerateId()),
+	}
+	return s.addPayment(ctx, loan, payment, outstanding)
+}
+
+func (s *service) RecordPrepayment(ctx context.Context, req *request.DataRequest[CreatePrepaymentDTO]) (*response.DataResponse[PaymentDTO], error) {
+	if !validDate(req.Data.Date) {
+		return nil, ErrDateInvalid
+	}
+	loan, _, payments, err := s.loadLoan(ctx, req.Data.TenantId, req.Data.LoanId)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Status == LoanStatusPaidOff {
+		return nil, ErrLoanPaidOff
+	}
+	outstanding := outstandingPrincipal(loan, payments)
+	if req.Data.Amount > outstanding {
+		return nil, ErrPaymentTooLarge
+	}
+
+	payment := &LoanPayment{
+		LoanId:          loan.Id,
+		Kind:            PaymentPrepayment,
+		Date:            req.Data.Date,
+		Amount:          req.Data.Amount,
+		Principal:       req.Data.Amount,
+		PrepayMode:      req.Data.Mode,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(loan.TenantId, util.GenerateId()),
+	}
+	return s.addPayment(ctx, loan, payment, outstanding)
+}
+
+func (s *service) PreviewPrepayment(ctx context.Context, req *request.DataRequest[CreatePrepaymentDTO]) (*response.DataResponse[PrepaymentScenarioDTO], error) {
+	if !validDate(req.Data.Date) {
+		return nil, ErrDateInvalid
+	}
+	loan, rates, payments, err := s.loadLoan(ctx, req.Data.TenantId, req.Data.LoanId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Data.Amount > outstandingPrincipal(loan, payments) {
+		return nil, ErrPaymentTooLarge
+	}
+
+	prepays := prepaymentsOf(payments)
+	current := summarize(buildSchedule(loan, rates, prepays))
+	prepays = append(prepays, prepayment{Date: req.Data.Date, Amount: req.Data.Amount, Mode: req.Data.Mode})
+	schedule := buildSchedule(loan, rates, prepays)
+	scenario := summarize(schedule)
+
+	result := PrepaymentScenarioDTO{
+		Current:       current,
+		Scenario:      scenario,
+		InterestSaved: current.TotalInterest - scenario.TotalInterest,
+		PeriodsSaved:  current.Periods - scenario.Periods,
+		Installments:  schedule,
+	}
+	for _, row := range schedule {
+		if row.Date >= req.Data.Date && row.Payment > 0 {
+			result.NewPayment = row.Payment
+			break
+		}
+	}
+	return &response.DataResponse[PrepaymentScenarioDTO]{
+		Data: result,
+	}, nil
+}
+
+func (s *service) LoanBalances(ctx context.Context, tenantId string, asOf string) ([]LoanBalance, error) {
+	loans, err := s.findLoans(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.findPayments(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	paymentsByLoan := map[string][]LoanPayment{}
+	for _, payment := range payments {
+		if len(asOf) == 0 || payment.Date <= asOf {
+			paymentsByLoan[payment.LoanId] = append(paymentsByLoan[payment.LoanId], payment)
+		}
+	}
+	rateChanges, err := s.findRateChanges(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	ratesByLoan := map[string][]LoanRateChange{}
+	for _, change := range rateChanges {
+		ratesByLoan[change.LoanId] = append(ratesByLoan[change.LoanId], change)
+	}
+	scheduleDate := asOf
+	if len(scheduleDate) == 0 {
+		scheduleDate = time.Now().Format(DateLayout)
+	}
+
+	balances := []LoanBalance{}
+	for i := range loans {
+		if len(asOf) > 0 && loans[i].StartDate > asOf {
+			continue
+		}
+		outstanding := balanceAt(&loans[i], ratesByLoan[loans[i].Id], paymentsByLoan[loans[i].Id], scheduleDate)
+		balances = append(balances, LoanBalance{
+			LoanId:      loans[i].Id,
+			Name:        loans[i].Name,
+			OwnerUserId: loans[i].OwnerUserId,
+			Currency:    loans[i].Currency,
+			Outstanding: outstanding,
+		})
+	}
+	return balances, nil
+}
+
+func (s *service) addPayment(ctx context.Context, loan *Loan, payment *LoanPayment, outstanding int64) (*response.DataResponse[PaymentDTO], error) {
+	payment, err := s.paymentRepo.Add(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+	if outstanding-payment.Principal <= 0 {
+		loan.Status = LoanStatusPaidOff
+		if _, err := s.loanRepo.Update(ctx, loan); err != nil {
+			return nil, err
+		}
+	}
+
+	return &response.DataResponse[PaymentDTO]{
+		Data: toPaymentDTO(payment),
+	}, nil
+}
+
+func (s *service) loadLoan(ctx context.Context, tenantId string, id string) (*Loan, []LoanRateChange, []LoanPayment, error) {
+	loan, err := s.findLoanById(ctx, tenantId, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rates, err := s.findRateChanges(ctx, "loan_id", loan.Id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	payments, err := s.findPayments(ctx, "loan_id", loan.Id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].EffectiveDate < rates[j].EffectiveDate
+	})
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Date < payments[j].Date
+	})
+	return loan, rates, payments, nil
+}
+
+func outstandingPrincipal(loan *Loan, payments []LoanPayment) int64 {
+	outstanding := loan.Principal
+	for _, payment := range payments {
+		outstanding -= payment.Principal
+	}
+	return max(outstanding, 0)
+}
+
+// balanceAt date 当日的剩余本金：已录入的还款和提前还款以实际金额为准，
+// 计划中已到期但未录入的期次按含提前还款的还款计划扣减本金
+func balanceAt(loan *Loan, rates []LoanRateChange, payments []LoanPayment, date string) int64 {
+	outstanding := outstandingPrincipal(loan, payments)
+	paid := paidPeriods(payments)
+	for _, row := range buildSchedule(loan, rates, prepaymentsOf(payments)) {
+		if row.Date > date {
+			break
+		}
+		if row.Period > paid {
+			outstanding -= row.Principal
+		}
+	}
+	return max(outstanding, 0)
+}
+
+func paidPeriods(payments []LoanPayment) int {
+	periods := 0
+	for _, payment := range payments {
+		if payment.Kind == PaymentRegular {
+			periods = max(periods, payment.Period)
+		}
+	}
+	return periods
+}
+
+func prepaymentsOf(payments []LoanPayment) []prepayment {
+	prepays := []prepayment{}
+	for _, payment := range payments {
+		if payment.Kind == PaymentPrepayment {
+			prepays = append(prepays, prepayment{Date: payment.Date, Amount: payment.Amount, Mode: payment.PrepayMode})
+		}
+	}
+	return prepays
+}
+
+func toLoanDTO(loan *Loan, rates []LoanRateChange, payments []LoanPayment, now time.Time) LoanDTO {
+	schedule := buildSchedule(loan, rates, prepaymentsOf(payments))
+	paid := paidPeriods(payments)
+	dto := LoanDTO{
+		Id:                   loan.Id,
+		TenantId:             loan.TenantId,
+		Name:                 loan.Name,
+		Kind:                 loan.Kind,
+		Lender:               loan.Lender,
+		Principal:            loan.Principal,
+		Currency:             loan.Currency,
+		RateType:             loan.RateType,
+		AnnualRateBps:        loan.AnnualRateBps,
+		LprSpreadBps:         loan.LprSpreadBps,
+		TermMonths:           loan.TermMonths,
+		Method:               loan.Method,
+		StartDate:            loan.StartDate,
+		FirstPaymentDate:     loan.FirstPaymentDate,
+		OwnerUserId:          loan.OwnerUserId,
+		Status:               loan.Status,
+		Memo:                 loan.Memo,
+		CurrentRateBps:       rateAt(loan.AnnualRateBps, rates, now.Format(DateLayout)),
+		OutstandingPrincipal: outstandingPrincipal(loan, payments),
+		PaidPeriods:          paid,
+		Summary:              summarize(schedule),
+	}
+	if loan.Status == LoanStatusActive {
+		for _, row := range schedule {
+			if row.Period > paid && row.Payment > 0 {
+				dto.NextInstallment = &row
+				break
+			}
+		}
+	}
+	return dto
+}
+
+func toPaymentDTO(payment *LoanPayment) PaymentDTO {
+	return PaymentDTO{
+		Id:         payment.Id,
+		LoanId:     payment.LoanId,
+		Kind:       payment.Kind,
+		Period:     payment.Period,
+		Date:       payment.Date,
+		Amount:     payment.Amount,
+		Principal:  payment.Principal,
+		Interest:   payment.Interest,
+		PrepayMode: payment.PrepayMode,
+		Memo:       payment.Memo,
+	}
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func (s *service) findLoanById(ctx context.Context, tenantId string, id string) (*Loan, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	loans, err := s.loanRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(loans) == 0 {
+		return nil, ErrLoanNotFound
+	}
+	return &loans[0], nil
+}
+
+func (s *service) findLoans(ctx context.Context, tenantId string) ([]Loan, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1000,
+		PageNumber:  1,
+	}
+	return s.loanRepo.Query(ctx, query)
+}
+
+func (s *service) findRateChanges(ctx context.Context, field string, value string) ([]LoanRateChange, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.rateRepo.Query(ctx, query)
+}
+
+func (s *service) findPayments(ctx context.Context, field string, value string) ([]LoanPayment, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.paymentRepo.Query(ctx, query)
+}
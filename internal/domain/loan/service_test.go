@@ -0,0 +1,66 @@
+package loan
+
+import "testing"
+
+func TestBalanceAt(t *testing.T) {
+	loan := testLoan(12000, 0, 12, MethodEqualInstallment)
+	tests := []struct {
+		name     string
+		payments []LoanPayment
+		date     string
+		want     int64
+	}{
+		{
+			name: "首期还款日前未还本金",
+			date: "2024-01-10",
+			want: 12000,
+		},
+		{
+			name: "未录入还款时按计划扣减已到期期次",
+			date: "2024-03-20",
+			want: 9000,
+		},
+		{
+			name: "只录入提前还款时已到期期次仍按计划扣减",
+			payments: []LoanPayment{
+				{Kind: PaymentPrepayment, Date: "2024-03-20", Amount: 2000, Principal: 2000, PrepayMode: PrepayReducePayment},
+			},
+			date: "2024-04-20",
+			want: 6222,
+		},
+		{
+			name: "已录入的还款以实际本金为准",
+			payments: []LoanPayment{
+				{Kind: PaymentRegular, Period: 1, Date: "2024-01-15", Amount: 1500, Principal: 1500},
+				{Kind: PaymentRegular, Period: 2, Date: "2024-02-15", Amount: 1500, Principal: 1500},
+			},
+			date: "2024-03-20",
+			want: 8000,
+		},
+		{
+			name: "提前录入的期次不再按计划重复扣减",
+			payments: []LoanPayment{
+				{Kind: PaymentRegular, Period: 1, Date: "2024-01-15", Amount: 1000, Principal: 1000},
+				{Kind: PaymentRegular, Period: 2, Date: "2024-01-15", Amount: 1000, Principal: 1000},
+				{Kind: PaymentRegular, Period: 3, Date: "2024-01-15", Amount: 1000, Principal: 1000},
+			},
+			date: "2024-02-20",
+			want: 9000,
+		},
+		{
+			name: "还清后不为负数",
+			payments: []LoanPayment{
+				{Kind: PaymentPrepayment, Date: "2024-01-01", Amount: 12000, Principal: 12000, PrepayMode: PrepayShortenTerm},
+			},
+			date: "2025-01-01",
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := balanceAt(loan, nil, tt.payments, tt.date); got != tt.want {
+				t.Errorf("balanceAt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
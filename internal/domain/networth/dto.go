@@ -0,0 +1,36 @@
+package networth
+
+type NetWorthDTO struct {
+	Date         string          `json:"date"`
+	BaseCurrency string          `json:"base_currency"`
+	Assets       int64           `json:"assets"`
+	Liabilities  int64           `json:"liabilities"`
+	NetWorth     int64           `json:"net_worth"`
+	Items        []SnapshotItem  `json:"items"`
+	Members      []MemberBalance `json:"members"`
+}
+
+type NetWorthPointDTO struct {
+	Date        string          `json:"date"`
+	Assets      int64           `json:"assets"`
+	Liabilities int64           `json:"liabilities"`
+	NetWorth    int64           `json:"net_worth"`
+	Members     []MemberBalance `json:"members"`
+}
+
+type NetWorthHistoryDTO struct {
+	BaseCurrency string             `json:"base_currency"`
+	Granularity  string             `json:"granularity"`
+	Points       []NetWorthPointDTO `json:"points"`
+}
+
+type TakeSnapshotDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	// 为空时为今天，可补录过去日期的快照
+	Date string `json:"date" binding:"omitempty"`
+}
+
+type NetWorthSettingDTO struct {
+	TenantId  string `json:"tenant_id" binding:"required"`
+	Frequency string `json:"frequency" binding:"required,oneof=daily monthly"`
+}
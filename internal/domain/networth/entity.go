@@ -0,0 +1,62 @@
+package networth
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	FrequencyDaily   = "daily"
+	FrequencyMonthly = "monthly"
+
+	KindAsset     = "asset"
+	KindLiability = "liability"
+
+	DateLayout = "2006-01-02"
+)
+
+// SnapshotItem 快照中的一项资产或负债，BaseAmount 为折算后的本位币金额
+type SnapshotItem struct {
+	Source      string `json:"source"`
+	SourceId    string `json:"source_id"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	OwnerUserId string `json:"owner_user_id"`
+	Currency    string `json:"currency"`
+	Amount      int64  `json:"amount"`
+	BaseAmount  int64  `json:"base_amount"`
+	// 缺少汇率无法折算时为 false，不计入合计
+	Converted bool `json:"converted"`
+}
+
+// MemberBalance 按所有人汇总的净资产，UserId 为空表示家庭共有
+type MemberBalance struct {
+	UserId      string `json:"user_id"`
+	Assets      int64  `json:"assets"`
+	Liabilities int64  `json:"liabilities"`
+	NetWorth    int64  `json:"net_worth"`
+}
+
+// NetWorthSnapshot 某日的净资产快照，每个租户每天最多一条
+type NetWorthSnapshot struct {
+	model.TenantBaseModel
+	SnapshotDate string          `json:"snapshot_date" gorm:"size:10;not null;index"`
+	BaseCurrency string          `json:"base_currency" gorm:"size:3;not null"`
+	Assets       int64           `json:"assets"`
+	Liabilities  int64           `json:"liabilities"`
+	NetWorth     int64           `json:"net_worth"`
+	Items        []SnapshotItem  `json:"items" gorm:"type:text;serializer:json"`
+	Members      []MemberBalance `json:"members" gorm:"type:text;serializer:json"`
+}
+
+func (entity *NetWorthSnapshot) TableName() string {
+	return "finance_networth_snapshot"
+}
+
+type NetWorthSetting struct {
+	model.TenantBaseModel
+	Frequency string `json:"frequency" gorm:"size:10;not null"`
+}
+
+func (entity *NetWorthSetting) TableName() string {
+	return "finance_networth_setting"
+}
@@ -0,0 +1,9 @@
+package networth
+
+import "errors"
+
+var (
+	ErrDateInvalid        = errors.New("日期格式无效，应为YYYY-MM-DD")
+	ErrDateInFuture       = errors.New("不能生成未来日期的快照")
+	ErrGranularityInvalid = errors.New("粒度无效，应为day、week或month")
+)
@@ -0,0 +1,26 @@
+package networth
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建净资产快照表
+	if err := db.AutoMigrate(&NetWorthSnapshot{}); err != nil {
+		fmt.Println("创建净资产快照表失败", err)
+	}
+	// 每个租户每天最多一条快照
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_networth_snapshot_unique ON finance_networth_snapshot (tenant_id, snapshot_date)").Error; err != nil {
+		fmt.Println("创建净资产快照唯一索引失败", err)
+	}
+
+	// 创建净资产设置表
+	if err := db.AutoMigrate(&NetWorthSetting{}); err != nil {
+		fmt.Println("创建净资产设置表失败", err)
+	}
+
+	fmt.Println("NetWorth模块迁移完成")
+}
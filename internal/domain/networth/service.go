@@ -0,0 +1,420 @@
+package networth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/currency"
+)
+
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+
+	snapshotPageSize = 500
+)
+
+type NetWorthService interface {
+	// GetCurrent 按最新数据实时计算净资产，不保存
+	GetCurrent(ctx context.Context, tenantId string) (*response.DataResponse[NetWorthDTO], error)
+	// GetHistory 返回快照序列，按周或按月时取每个周期最后一条快照
+	GetHistory(ctx context.Context, tenantId string, from string, to string, granularity string) (*response.DataResponse[NetWorthHistoryDTO], error)
+	TakeSnapshot(ctx context.Context, req *request.DataRequest[TakeSnapshotDTO]) (*response.DataResponse[NetWorthDTO], error)
+	GetSetting(ctx context.Context, tenantId string) (*response.DataResponse[NetWorthSettingDTO], error)
+	SaveSetting(ctx context.Context, req *request.DataRequest[NetWorthSettingDTO]) (*response.DataResponse[NetWorthSettingDTO], error)
+	// TakeScheduledSnapshots 按各租户的快照频率生成 asOf 当日快照，按月的租户缺少上月末快照时补生成，可重复执行
+	TakeScheduledSnapshots(ctx context.Context, asOf time.Time) (int, error)
+}
+
+type service struct {
+	snapshotRepo repository.Repository[NetWorthSnapshot]
+	settingRepo  repository.Repository[NetWorthSetting]
+	converter    currency.Converter
+	members      auth.MemberDirectory
+	sources      []Source
+}
+
+func NewNetWorthService(
+	snapshotRepo repository.Repository[NetWorthSnapshot],
+	settingRepo repository.Repository[NetWorthSetting],
+	converter currency.Converter,
+	members auth.MemberDirectory,
+	sources ...Source,
+) NetWorthService {
+	return &service{
+		snapshotRepo: snapshotRepo,
+		settingRepo:  settingRepo,
+		converter:    converter,
+		members:      members,
+		sources:      sources,
+	}
+}
+
+func (s *service) GetCurrent(ctx context.Context, tenantId string) (*response.DataResponse[NetWorthDTO], error) {
+	result, err := s.compute(ctx, tenantId, time.Now().Format(DateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[NetWorthDTO]{
+		Data: *result,
+	}, nil
+}
+
+func (s *service) GetHistory(ctx context.Context, tenantId string, from string, to string, granularity string) (*response.DataResponse[NetWorthHistoryDTO], error) {
+	if len(granularity) == 0 {
+		granularity = GranularityMonth
+	}
+	if granularity != GranularityDay && granularity != GranularityWeek && granularity != GranularityMonth {
+		return nil, ErrGranularityInvalid
+	}
+	if (len(from) > 0 && !validDate(from)) || (len(to) > 0 && !validDate(to)) {
+		return nil, ErrDateInvalid
+	}
+	snapshots, err := s.findSnapshots(ctx, tenantId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.converter.BaseCurrency(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []NetWorthPointDTO{}
+	lastPeriod := ""
+	for _, snapshot := range snapshots {
+		point := NetWorthPointDTO{
+			Date:        snapshot.SnapshotDate,
+			Assets:      snapshot.Assets,
+			Liabilities: snapshot.Liabilities,
+			NetWorth:    snapshot.NetWorth,
+			Members:     snapshot.Members,
+		}
+		// 同一周期内后面的快照覆盖前面的
+		period := periodOf(snapshot.SnapshotDate, granularity)
+		if period == lastPeriod && len(points) > 0 {
+			points[len(points)-1] = point
+		} else {
+			points = append(points, point)
+		}
+		lastPeriod = period
+	}
+
+	return &response.DataResponse[NetWorthHistoryDTO]{
+		Data: NetWorthHistoryDTO{
+			BaseCurrency: base,
+			Granularity:  granularity,
+			Points:       points,
+		},
+	}, nil
+}
+
+func (s *service) TakeSnapshot(ctx context.Context, req *request.DataRequest[TakeSnapshotDTO]) (*response.DataResponse[NetWorthDTO], error) {
+	today := time.Now().Format(DateLayout)
+	date := req.Data.Date
+	if len(date) == 0 {
+		date = today
+	} else if !validDate(date) {
+		return nil, ErrDateInvalid
+	} else if date > today {
+		return nil, ErrDateInFuture
+	}
+
+	result, err := s.saveSnapshot(ctx, req.Data.TenantId, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[NetWorthDTO]{
+		Data: *result,
+	}, nil
+}
+
+func (s *service) GetSetting(ctx context.Context, tenantId string) (*response.DataResponse[NetWorthSettingDTO], error) {
+	frequency, err := s.frequency(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[NetWorthSettingDTO]{
+		Data: NetWorthSettingDTO{
+			TenantId:  tenantId,
+			Frequency: frequency,
+		},
+	}, nil
+}
+
+func (s *service) SaveSetting(ctx context.Context, req *request.DataRequest[NetWorthSettingDTO]) (*response.DataResponse[NetWorthSettingDTO], error) {
+	settings, err := s.findSettings(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		setting := &NetWorthSetting{
+			Frequency:       req.Data.Frequency,
+			TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+		}
+		_, err = s.settingRepo.Add(ctx, setting)
+	} else {
+		settings[0].Frequency = req.Data.Frequency
+		_, err = s.settingRepo.Update(ctx, &settings[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[NetWorthSettingDTO]{
+		Data: req.Data,
+	}, nil
+}
+
+func (s *service) TakeScheduledSnapshots(ctx context.Context, asOf time.Time) (int, error) {
+	tenantIds, err := s.members.TenantIds(ctx)
+	if err != nil {
+		return 0, err
+	}
+	today := asOf.Format(DateLayout)
+	monthEnd := asOf.AddDate(0, 0, 1).Day() == 1
+	lastMonthEnd := time.Date(asOf.Year(), asOf.Month(), 0, 0, 0, 0, 0, asOf.Location()).Format(DateLayout)
+
+	taken := 0
+	var errs error
+	// 单个租户失败不影响其他租户
+	for _, tenantId := range tenantIds {
+		date, err := s.scheduledDate(ctx, tenantId, today, monthEnd, lastMonthEnd)
+		if err == nil && len(date) > 0 {
+			_, err = s.saveSnapshot(ctx, tenantId, date)
+			if err == nil {
+				taken++
+			}
+		}
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("net worth snapshot for tenant %s: %w", tenantId, err))
+		}
+	}
+	return taken, errs
+}
+
+// scheduledDate 返回租户本次应生成快照的日期，无需生成时返回空字符串
+func (s *service) scheduledDate(ctx context.Context, tenantId string, today string, monthEnd bool, lastMonthEnd string) (string, error) {
+	frequency, err := s.frequency(ctx, tenantId)
+	if err != nil {
+		return "", err
+	}
+	if frequency != FrequencyMonthly || monthEnd {
+		return today, nil
+	}
+	// 月末任务未执行时补生成上月末快照
+	existing, err := s.findSnapshot(ctx, tenantId, lastMonthEnd)
+	if err != nil || existing != nil {
+		return "", err
+	}
+	return lastMonthEnd, nil
+}
+
+// saveSnapshot 计算并保存快照，同一天已有快照时覆盖
+func (s *service) saveSnapshot(ctx context.Context, tenantId string, date string) (*NetWorthDTO, error) {
+	result, err := s.compute(ctx, tenantId, date)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.findSnapshot(ctx, tenantId, date)
+	if err != nil {
+		return nil, err
+	}
+	created := snapshot == nil
+	if created {
+		snapshot = &NetWorthSnapshot{
+			SnapshotDate:    date,
+			TenantBaseModel: model.NewTenantBaseModel(tenantId, util.GenerateId()),
+		}
+	}
+	snapshot.BaseCurrency = result.BaseCurrency
+	snapshot.Assets = result.Assets
+	snapshot.Liabilities = result.Liabilities
+	snapshot.NetWorth = result.NetWorth
+	snapshot.Items = result.Items
+	snapshot.Members = result.Members
+
+	if created {
+		_, err = s.snapshotRepo.Add(ctx, snapshot)
+	} else {
+		_, err = s.snapshotRepo.Update(ctx, snapshot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// compute 汇总各数据来源在 date 当日的资产和负债，并折算为本位币
+func (s *service) compute(ctx context.Context, tenantId string, date string) (*NetWorthDTO, error) {
+	base, err := s.converter.BaseCurrency(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NetWorthDTO{
+		Date:         date,
+		BaseCurrency: base,
+		Items:        []SnapshotItem{},
+	}
+	members := map[string]*MemberBalance{}
+	for _, source := range s.sources {
+		items, err := source.Items(ctx, tenantId, date)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if len(item.Currency) == 0 {
+				item.Currency = base
+			}
+			item.Currency = strings.ToUpper(item.Currency)
+			amount, err := s.converter.ConvertAmount(ctx, tenantId, item.Amount, item.Currency, base, date)
+			item.Converted = err == nil
+			if item.Converted {
+				item.BaseAmount = amount
+			}
+			result.Items = append(result.Items, item)
+			if !item.Converted {
+				continue
+			}
+
+			member, ok := members[item.OwnerUserId]
+			if !ok {
+				member = &MemberBalance{UserId: item.OwnerUserId}
+				members[item.OwnerUserId] = member
+			}
+			if item.Kind == KindLiability {
+				result.Liabilities += item.BaseAmount
+				member.Liabilities += item.BaseAmount
+			} else {
+				result.Assets += item.BaseAmount
+				member.Assets += item.BaseAmount
+			}
+		}
+	}
+	result.NetWorth = result.Assets - result.Liabilities
+
+	result.Members = make([]MemberBalance, 0, len(members))
+	for _, member := range members {
+		member.NetWorth = member.Assets - member.Liabilities
+		result.Members = append(result.Members, *member)
+	}
+	sort.Slice(result.Members, func(i, j int) bool {
+		return result.Members[i].UserId < result.Members[j].UserId
+	})
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		if result.Items[i].Kind != result.Items[j].Kind {
+			return result.Items[i].Kind == KindAsset
+		}
+		return result.Items[i].BaseAmount > result.Items[j].BaseAmount
+	})
+	return result, nil
+}
+
+func (s *service) frequency(ctx context.Context, tenantId string) (string, error) {
+	settings, err := s.findSettings(ctx, tenantId)
+	if err != nil {
+		return "", err
+	}
+	if len(settings) == 0 {
+		return FrequencyMonthly, nil
+	}
+	return settings[0].Frequency, nil
+}
+
+// periodOf 返回日期所在周期的标识，按周时为 ISO 周
+func periodOf(date string, granularity string) string {
+	switch granularity {
+	case GranularityWeek:
+		d, _ := time.Parse(DateLayout, date)
+		year, week := d.ISOWeek()
+		return fmt.Sprintf("%dW%02d", year, week)
+	case GranularityMonth:
+		return date[:7]
+	default:
+		return date
+	}
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+// findSnapshots 按快照日期顺序分页查询租户在 [from, to] 内的快照，日期为空时不限制
+func (s *service) findSnapshots(ctx context.Context, tenantId string, from string, to string) ([]NetWorthSnapshot, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	if len(from) > 0 {
+		filters = append(filters, query.NewDbQueryFilter("snapshot_date", []interface{}{from}, query.GTE, "String"))
+	}
+	if len(to) > 0 {
+		filters = append(filters, query.NewDbQueryFilter("snapshot_date", []interface{}{to}, query.LTE, "String"))
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	snapshots := []NetWorthSnapshot{}
+	for page := 1; ; page++ {
+		query := &query.DbQuery{
+			QueryWheres: wheres,
+			OrderBys:    []query.DbQueryOrderBy{query.NewDbQueryOrderBy("snapshot_date", false)},
+			PageSize:    snapshotPageSize,
+			PageNumber:  page,
+		}
+		items, err := s.snapshotRepo.Query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, items...)
+		if len(items) < snapshotPageSize {
+			return snapshots, nil
+		}
+	}
+}
+
+func (s *service) findSnapshot(ctx context.Context, tenantId string, date string) (*NetWorthSnapshot, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("snapshot_date", []interface{}{date}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	snapshots, err := s.snapshotRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+	return &snapshots[0], nil
+}
+
+func (s *service) findSettings(ctx context.Context, tenantId string) ([]NetWorthSetting, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	return s.settingRepo.Query(ctx, query)
+}
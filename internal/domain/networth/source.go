@@ -0,0 +1,217 @@
+package networth
+
+import (
+	"context"
+
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/currency"
+	"github.com/loongkirin/go-family-finance/internal/domain/fixedasset"
+	"github.com/loongkirin/go-family-finance/internal/domain/investment"
+	"github.com/loongkirin/go-family-finance/internal/domain/iou"
+	"github.com/loongkirin/go-family-finance/internal/domain/loan"
+	"github.com/loongkirin/go-family-finance/internal/domain/reimbursement"
+	"gorm.io/gorm"
+)
+
+const (
+	SourceInvestment    = "investment"
+	SourceLoan          = "loan"
+	SourceIou           = "iou"
+	SourceFixedAsset    = "fixed_asset"
+	SourceReimbursement = "reimbursement"
+)
+
+// Source 净资产的数据来源，返回 asOf 当日的资产和负债，Amount 为原币金额
+type Source interface {
+	Items(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error)
+}
+
+type SourceFunc func(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error)
+
+func (f SourceFunc) Items(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error) {
+	return f(ctx, tenantId, asOf)
+}
+
+// NewDefaultNetWorthService 以全部数据来源创建净资产服务。接口查询和定时快照都通过它创建，
+// 新增来源只需改这一处，两者的计算口径保持一致
+func NewDefaultNetWorthService(db *gorm.DB) NetWorthService {
+	currencyService := currency.NewCurrencyService(
+		repository.NewRepository[currency.ExchangeRate](db),
+		repository.NewRepository[currency.CurrencySetting](db),
+	)
+	return NewNetWorthService(
+		repository.NewRepository[NetWorthSnapshot](db),
+		repository.NewRepository[NetWorthSetting](db),
+		currencyService,
+		auth.NewMemberDirectory(repository.NewRepository[auth.User](db)),
+		InvestmentSource(investment.NewInvestmentService(
+			repository.NewRepository[investment.Portfolio](db),
+			repository.NewRepository[investment.Security](db),
+			repository.NewRepository[investment.InvestmentTransaction](db),
+			repository.NewRepository[investment.SecurityPrice](db),
+			currencyService,
+		)),
+		LoanSource(loan.NewLoanService(
+			repository.NewRepository[loan.Loan](db),
+			repository.NewRepository[loan.LoanRateChange](db),
+			repository.NewRepository[loan.LoanPayment](db),
+		)),
+		IouSource(iou.NewIouService(
+			repository.NewRepository[iou.Iou](db),
+			repository.NewRepository[iou.SharedExpense](db),
+			repository.NewRepository[iou.Settlement](db),
+		)),
+		FixedAssetSource(fixedasset.NewFixedAssetService(
+			repository.NewRepository[fixedasset.FixedAsset](db),
+			repository.NewRepository[fixedasset.AssetValuation](db),
+		)),
+		ReimbursementSource(reimbursement.NewReimbursementService(
+			repository.NewRepository[reimbursement.Claim](db),
+			repository.NewRepository[reimbursement.Receipt](db),
+		)),
+	)
+}
+
+// InvestmentSource 投资账户市值计为资产
+func InvestmentSource(valuer investment.PortfolioValuer) Source {
+	return SourceFunc(func(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error) {
+		values, err := valuer.PortfolioValues(ctx, tenantId, asOf)
+		if err != nil {
+			return nil, err
+		}
+		items := []SnapshotItem{}
+		for _, value := range values {
+			if value.MarketValue == 0 {
+				continue
+			}
+			items = append(items, SnapshotItem{
+				Source:      SourceInvestment,
+				SourceId:    value.PortfolioId,
+				Name:        value.Name,
+				Kind:        KindAsset,
+				OwnerUserId: value.OwnerUserId,
+				Currency:    value.Currency,
+				Amount:      value.MarketValue,
+			})
+		}
+		return items, nil
+	})
+}
+
+// LoanSource 贷款剩余本金计为负债
+func LoanSource(reader loan.BalanceReader) Source {
+	return SourceFunc(func(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error) {
+		balances, err := reader.LoanBalances(ctx, tenantId, asOf)
+		if err != nil {
+			return nil, err
+		}
+		items := []SnapshotItem{}
+		for _, balance := range balances {
+			if balance.Outstanding == 0 {
+				continue
+			}
+			items = append(items, SnapshotItem{
+				Source:      SourceLoan,
+				SourceId:    balance.LoanId,
+				Name:        balance.Name,
+				Kind:        KindLiability,
+				OwnerUserId: balance.OwnerUserId,
+				Currency:    balance.Currency,
+				Amount:      balance.Outstanding,
+			})
+		}
+		return items, nil
+	})
+}
+
+// IouSource 借出计为资产、借入计为负债；对方为家庭成员时同时计入对方，全家合计相互抵消
+func IouSource(reader iou.BalanceReader) Source {
+	return SourceFunc(func(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error) {
+		balances, err := reader.OpenIous(ctx, tenantId, asOf)
+		if err != nil {
+			return nil, err
+		}
+		items := []SnapshotItem{}
+		for _, balance := range balances {
+			own, other := KindAsset, KindLiability
+			if balance.Direction == iou.DirectionBorrowed {
+				own, other = KindLiability, KindAsset
+			}
+			name := balance.CounterpartyName
+			if len(name) == 0 {
+				name = balance.CounterpartyUserId
+			}
+			items = append(items, SnapshotItem{
+				Source:      SourceIou,
+				SourceId:    balance.IouId,
+				Name:        name,
+				Kind:        own,
+				OwnerUserId: balance.UserId,
+				Currency:    balance.Currency,
+				Amount:      balance.Outstanding,
+			})
+			if len(balance.CounterpartyUserId) > 0 {
+				items = append(items, SnapshotItem{
+					Source:      SourceIou,
+					SourceId:    balance.IouId,
+					Name:        balance.UserId,
+					Kind:        other,
+					OwnerUserId: balance.CounterpartyUserId,
+					Currency:    balance.Currency,
+					Amount:      balance.Outstanding,
+				})
+			}
+		}
+		return items, nil
+	})
+}
+
+// FixedAssetSource 房产、车辆等固定资产按折旧后价值计为资产
+func FixedAssetSource(reader fixedasset.ValueReader) Source {
+	return SourceFunc(func(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error) {
+		values, err := reader.AssetValues(ctx, tenantId, asOf)
+		if err != nil {
+			return nil, err
+		}
+		items := []SnapshotItem{}
+		for _, value := range values {
+			if value.Value == 0 {
+				continue
+			}
+			items = append(items, SnapshotItem{
+				Source:      SourceFixedAsset,
+				SourceId:    value.AssetId,
+				Name:        value.Name,
+				Kind:        KindAsset,
+				OwnerUserId: value.OwnerUserId,
+				Currency:    value.Currency,
+				Amount:      value.Value,
+			})
+		}
+		return items, nil
+	})
+}
+
+// ReimbursementSource 尚未到账的报销款计为资产
+func ReimbursementSource(reader reimbursement.ReceivableReader) Source {
+	return SourceFunc(func(ctx context.Context, tenantId string, asOf string) ([]SnapshotItem, error) {
+		receivables, err := reader.Receivables(ctx, tenantId, asOf)
+		if err != nil {
+			return nil, err
+		}
+		items := []SnapshotItem{}
+		for _, receivable := range receivables {
+			items = append(items, SnapshotItem{
+				Source:      SourceReimbursement,
+				SourceId:    receivable.ClaimId,
+				Name:        receivable.Title,
+				Kind:        KindAsset,
+				OwnerUserId: receivable.UserId,
+				Currency:    receivable.Currency,
+				Amount:      receivable.Outstanding,
+			})
+		}
+		return items, nil
+	})
+}
@@ -0,0 +1,20 @@
+package notification
+
+import (
+	"context"
+)
+
+// Message 待发送的通知，UserIds 中的每个用户都会收到一条站内信并推送到其订阅的渠道
+type Message struct {
+	TenantId string
+	UserIds  []string
+	Category string
+	Title    string
+	Content  string
+}
+
+// Channel 站内信之外的推送渠道
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, subscription *Subscription, notification *Notification) error
+}
@@ -0,0 +1,23 @@
+package notification
+
+import "time"
+
+type SMTPConfig struct {
+	Host     string        `mapstructure:"host" json:"host" yaml:"host"`
+	Port     int           `mapstructure:"port" json:"port" yaml:"port"`
+	Username string        `mapstructure:"username" json:"username" yaml:"username"`
+	Password string        `mapstructure:"password" json:"password" yaml:"password"`
+	From     string        `mapstructure:"from" json:"from" yaml:"from"`
+	Timeout  time.Duration `mapstructure:"timeout" json:"timeout" yaml:"timeout"`
+}
+
+type WebhookConfig struct {
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout" yaml:"timeout"`
+}
+
+type NotificationConfig struct {
+	Workers   int           `mapstructure:"workers" json:"workers" yaml:"workers"`
+	QueueSize int           `mapstructure:"queue_size" json:"queue_size" yaml:"queue_size"`
+	SMTP      SMTPConfig    `mapstructure:"smtp" json:"smtp" yaml:"smtp"`
+	Webhook   WebhookConfig `mapstructure:"webhook" json:"webhook" yaml:"webhook"`
+}
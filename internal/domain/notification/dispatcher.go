@@ -0,0 +1,187 @@
+package notification
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/logger"
+	"github.com/loongkirin/gdk/util"
+)
+
+// Notifier 异步发送通知，调用方不会被渠道发送阻塞
+type Notifier interface {
+	Notify(msg Message)
+}
+
+type Dispatcher struct {
+	notificationRepo repository.Repository[Notification]
+	subscriptionRepo repository.Repository[Subscription]
+	channels         map[string]Channel
+	logger           logger.Logger
+	workers          int
+	queue            chan Message
+	wg               sync.WaitGroup
+	mu               sync.RWMutex
+	stopped          bool
+}
+
+func NewDispatcher(
+	notificationRepo repository.Repository[Notification],
+	subscriptionRepo repository.Repository[Subscription],
+	cfg NotificationConfig,
+	log logger.Logger,
+) *Dispatcher {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 2
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	channels := map[string]Channel{}
+	webhook := NewWebhookChannel(cfg.Webhook)
+	channels[webhook.Name()] = webhook
+	if len(cfg.SMTP.Host) > 0 {
+		email := NewSMTPChannel(cfg.SMTP)
+		channels[email.Name()] = email
+	}
+
+	return &Dispatcher{
+		notificationRepo: notificationRepo,
+		subscriptionRepo: subscriptionRepo,
+		channels:         channels,
+		logger:           log,
+		workers:          workers,
+		queue:            make(chan Message, queueSize),
+	}
+}
+
+// RegisterChannel 注册或替换推送渠道，需在 Start 之前调用
+func (d *Dispatcher) RegisterChannel(channel Channel) {
+	d.channels[channel.Name()] = channel
+}
+
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for msg := range d.queue {
+				d.deliver(msg)
+			}
+		}()
+	}
+}
+
+// Stop 停止接收新通知并等待队列中的通知发送完毕
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// Notify 将通知放入发送队列，Stop 之后的通知会被丢弃
+func (d *Dispatcher) Notify(msg Message) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.stopped {
+		d.logger.Error("notification dispatcher is stopped, message dropped", logger.Fields{
+			"tenant_id": msg.TenantId,
+			"category":  msg.Category,
+			"title":     msg.Title,
+		})
+		return
+	}
+	select {
+	case d.queue <- msg:
+	default:
+		d.logger.Error("notification queue is full, message dropped", logger.Fields{
+			"tenant_id": msg.TenantId,
+			"category":  msg.Category,
+			"title":     msg.Title,
+		})
+	}
+}
+
+func (d *Dispatcher) deliver(msg Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for _, userId := range msg.UserIds {
+		notification := &Notification{
+			UserId:          userId,
+			Category:        msg.Category,
+			Title:           msg.Title,
+			Content:         msg.Content,
+			NotifiedAt:      time.Now().UnixMilli(),
+			TenantBaseModel: model.NewTenantBaseModel(msg.TenantId, util.GenerateId()),
+		}
+		notification, err := d.notificationRepo.Add(ctx, notification)
+		if err != nil {
+			d.logger.Error("failed to save notification", logger.Fields{"user_id": userId, "error": err})
+			continue
+		}
+
+		subscriptions, err := d.findActiveSubscriptions(ctx, msg.TenantId, userId)
+		if err != nil {
+			d.logger.Error("failed to load notification subscriptions", logger.Fields{"user_id": userId, "error": err})
+			continue
+		}
+		for i := range subscriptions {
+			channel, ok := d.channels[subscriptions[i].Channel]
+			if !ok {
+				d.logger.Error("failed to send notification", logger.Fields{
+					"user_id": userId,
+					"channel": subscriptions[i].Channel,
+					"error":   ErrChannelNotConfigured,
+				})
+				continue
+			}
+			if err := channel.Send(ctx, &subscriptions[i], notification); err != nil {
+				d.logger.Error("failed to send notification", logger.Fields{
+					"user_id": userId,
+					"channel": channel.Name(),
+					"error":   err,
+				})
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) findActiveSubscriptions(ctx context.Context, tenantId string, userId string) ([]Subscription, error) {
+	subscriptions, err := findSubscriptions(ctx, d.subscriptionRepo, tenantId, userId)
+	if err != nil {
+		return nil, err
+	}
+	active := subscriptions[:0]
+	for _, s := range subscriptions {
+		if s.Active {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+func findSubscriptions(ctx context.Context, repo repository.Repository[Subscription], tenantId string, userId string) ([]Subscription, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("user_id", []interface{}{userId}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    100,
+		PageNumber:  1,
+	}
+	return repo.Query(ctx, query)
+}
@@ -0,0 +1,45 @@
+package notification
+
+type NotificationDTO struct {
+	Id         string `json:"id"`
+	UserId     string `json:"user_id"`
+	Category   string `json:"category"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	NotifiedAt int64  `json:"notified_at"`
+	Read       bool   `json:"read"`
+	ReadAt     int64  `json:"read_at"`
+}
+
+type NotificationListDTO struct {
+	Items       []NotificationDTO `json:"items"`
+	UnreadCount int               `json:"unread_count"`
+}
+
+type MarkReadDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	UserId   string `json:"user_id" binding:"required"`
+	// 为空时将该用户全部通知标记为已读
+	Ids []string `json:"ids" binding:"omitempty"`
+}
+
+type MarkReadResultDTO struct {
+	Updated int `json:"updated"`
+}
+
+type SubscriptionDTO struct {
+	Id      string `json:"id"`
+	UserId  string `json:"user_id"`
+	Channel string `json:"channel"`
+	Target  string `json:"target"`
+	Active  bool   `json:"active"`
+}
+
+type SaveSubscriptionDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	UserId   string `json:"user_id" binding:"required"`
+	Channel  string `json:"channel" binding:"required,oneof=email webhook"`
+	Target   string `json:"target" binding:"required,max_len=500"`
+	Secret   string `json:"secret" binding:"omitempty,max_len=100"`
+	Active   *bool  `json:"active" binding:"omitempty"`
+}
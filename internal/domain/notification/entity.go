@@ -0,0 +1,41 @@
+package notification
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+type Notification struct {
+	model.TenantBaseModel
+	UserId     string `json:"user_id" gorm:"size:32;not null;index"`
+	Category   string `json:"category" gorm:"size:50;not null"`
+	Title      string `json:"title" gorm:"size:200;not null"`
+	Content    string `json:"content" gorm:"type:text"`
+	NotifiedAt int64  `json:"notified_at" gorm:"index"`
+	Read       bool   `json:"read" gorm:"default:false"`
+	ReadAt     int64  `json:"read_at"`
+}
+
+func (entity *Notification) TableName() string {
+	return "finance_notification"
+}
+
+// Subscription 用户在站内信之外额外订阅的推送渠道
+type Subscription struct {
+	model.TenantBaseModel
+	UserId  string `json:"user_id" gorm:"size:32;not null;index"`
+	Channel string `json:"channel" gorm:"size:20;not null"`
+	// 邮件渠道为收件地址，webhook 渠道为回调 URL
+	Target string `json:"target" gorm:"size:500;not null"`
+	// webhook 签名密钥
+	Secret string `json:"secret" gorm:"size:100"`
+	Active bool   `json:"active" gorm:"default:true"`
+}
+
+func (entity *Subscription) TableName() string {
+	return "finance_notification_subscription"
+}
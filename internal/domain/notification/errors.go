@@ -0,0 +1,13 @@
+package notification
+
+import "errors"
+
+var (
+	ErrNotificationNotFound = errors.New("通知不存在")
+	ErrChannelInvalid       = errors.New("不支持的通知渠道")
+	ErrChannelNotConfigured = errors.New("通知渠道未配置")
+	ErrTargetRequired       = errors.New("通知接收地址不能为空")
+	ErrWebhookTargetInvalid = errors.New("webhook 地址必须是 https 公网地址")
+	ErrEmailTargetInvalid   = errors.New("邮件地址无效")
+	ErrUserIdRequired       = errors.New("用户ID不能为空")
+)
@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建通知表
+	if err := db.AutoMigrate(&Notification{}); err != nil {
+		fmt.Println("创建通知表失败", err)
+	}
+
+	// 创建通知订阅表
+	if err := db.AutoMigrate(&Subscription{}); err != nil {
+		fmt.Println("创建通知订阅表失败", err)
+	}
+
+	fmt.Println("Notification模块迁移完成")
+}
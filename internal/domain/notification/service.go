@@ -0,0 +1,235 @@
+package notification
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+const (
+	maxInboxSize         = 200
+	notificationPageSize = 500
+)
+
+type NotificationService interface {
+	ListNotifications(ctx context.Context, tenantId string, userId string, unreadOnly bool) (*response.DataResponse[NotificationListDTO], error)
+	MarkRead(ctx context.Context, req *request.DataRequest[MarkReadDTO]) (*response.DataResponse[MarkReadResultDTO], error)
+	ListSubscriptions(ctx context.Context, tenantId string, userId string) (*response.DataResponse[[]SubscriptionDTO], error)
+	SaveSubscription(ctx context.Context, req *request.DataRequest[SaveSubscriptionDTO]) (*response.DataResponse[SubscriptionDTO], error)
+}
+
+type service struct {
+	notificationRepo repository.Repository[Notification]
+	subscriptionRepo repository.Repository[Subscription]
+}
+
+func NewNotificationService(
+	notificationRepo repository.Repository[Notification],
+	subscriptionRepo repository.Repository[Subscription],
+) NotificationService {
+	return &service{
+		notificationRepo: notificationRepo,
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+func (s *service) ListNotifications(ctx context.Context, tenantId string, userId string, unreadOnly bool) (*response.DataResponse[NotificationListDTO], error) {
+	if len(userId) == 0 {
+		return nil, ErrUserIdRequired
+	}
+	notifications, err := s.findNotifications(ctx, tenantId, userId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].NotifiedAt > notifications[j].NotifiedAt
+	})
+
+	result := NotificationListDTO{Items: []NotificationDTO{}}
+	for i := range notifications {
+		if !notifications[i].Read {
+			result.UnreadCount++
+		} else if unreadOnly {
+			continue
+		}
+		if len(result.Items) < maxInboxSize {
+			result.Items = append(result.Items, toNotificationDTO(&notifications[i]))
+		}
+	}
+
+	return &response.DataResponse[NotificationListDTO]{
+		Data: result,
+	}, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, req *request.DataRequest[MarkReadDTO]) (*response.DataResponse[MarkReadResultDTO], error) {
+	notifications, err := s.findNotifications(ctx, req.Data.TenantId, req.Data.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Data.Ids) > 0 {
+		for _, id := range req.Data.Ids {
+			if !slices.ContainsFunc(notifications, func(n Notification) bool { return n.Id == id }) {
+				return nil, ErrNotificationNotFound
+			}
+		}
+	}
+
+	result := MarkReadResultDTO{}
+	now := time.Now().UnixMilli()
+	for i := range notifications {
+		n := &notifications[i]
+		if n.Read || (len(req.Data.Ids) > 0 && !slices.Contains(req.Data.Ids, n.Id)) {
+			continue
+		}
+		n.Read = true
+		n.ReadAt = now
+		if _, err := s.notificationRepo.Update(ctx, n); err != nil {
+			return nil, err
+		}
+		result.Updated++
+	}
+
+	return &response.DataResponse[MarkReadResultDTO]{
+		Data: result,
+	}, nil
+}
+
+func (s *service) ListSubscriptions(ctx context.Context, tenantId string, userId string) (*response.DataResponse[[]SubscriptionDTO], error) {
+	if len(userId) == 0 {
+		return nil, ErrUserIdRequired
+	}
+	subscriptions, err := findSubscriptions(ctx, s.subscriptionRepo, tenantId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]SubscriptionDTO, 0, len(subscriptions))
+	for i := range subscriptions {
+		dtos = append(dtos, toSubscriptionDTO(&subscriptions[i]))
+	}
+	return &response.DataResponse[[]SubscriptionDTO]{
+		Data: dtos,
+	}, nil
+}
+
+// SaveSubscription 按用户、渠道和接收地址新增或更新订阅
+func (s *service) SaveSubscription(ctx context.Context, req *request.DataRequest[SaveSubscriptionDTO]) (*response.DataResponse[SubscriptionDTO], error) {
+	target := strings.TrimSpace(req.Data.Target)
+	if len(target) == 0 {
+		return nil, ErrTargetRequired
+	}
+	if req.Data.Channel != ChannelEmail && req.Data.Channel != ChannelWebhook {
+		return nil, ErrChannelInvalid
+	}
+	switch req.Data.Channel {
+	case ChannelEmail:
+		address, err := normalizeEmailTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		target = address
+	case ChannelWebhook:
+		if err := validateWebhookTarget(target); err != nil {
+			return nil, err
+		}
+	}
+	subscriptions, err := findSubscriptions(ctx, s.subscriptionRepo, req.Data.TenantId, req.Data.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription *Subscription
+	for i := range subscriptions {
+		if subscriptions[i].Channel == req.Data.Channel && subscriptions[i].Target == target {
+			subscription = &subscriptions[i]
+			break
+		}
+	}
+
+	if subscription == nil {
+		subscription = &Subscription{
+			UserId:          req.Data.UserId,
+			Channel:         req.Data.Channel,
+			Target:          target,
+			Secret:          req.Data.Secret,
+			Active:          req.Data.Active == nil || *req.Data.Active,
+			TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+		}
+		subscription, err = s.subscriptionRepo.Add(ctx, subscription)
+	} else {
+		if len(req.Data.Secret) > 0 {
+			subscription.Secret = req.Data.Secret
+		}
+		if req.Data.Active != nil {
+			subscription.Active = *req.Data.Active
+		}
+		subscription, err = s.subscriptionRepo.Update(ctx, subscription)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[SubscriptionDTO]{
+		Data: toSubscriptionDTO(subscription),
+	}, nil
+}
+
+// findNotifications 按创建时间倒序分页读取用户的全部通知
+func (s *service) findNotifications(ctx context.Context, tenantId string, userId string) ([]Notification, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("user_id", []interface{}{userId}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	notifications := []Notification{}
+	for page := 1; ; page++ {
+		query := &query.DbQuery{
+			QueryWheres: wheres,
+			OrderBys:    []query.DbQueryOrderBy{query.NewDbQueryOrderBy("created_at", true), query.NewDbQueryOrderBy("id", true)},
+			PageSize:    notificationPageSize,
+			PageNumber:  page,
+		}
+		items, err := s.notificationRepo.Query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, items...)
+		if len(items) < notificationPageSize {
+			return notifications, nil
+		}
+	}
+}
+
+func toNotificationDTO(notification *Notification) NotificationDTO {
+	return NotificationDTO{
+		Id:         notification.Id,
+		UserId:     notification.UserId,
+		Category:   notification.Category,
+		Title:      notification.Title,
+		Content:    notification.Content,
+		NotifiedAt: notification.NotifiedAt,
+		Read:       notification.Read,
+		ReadAt:     notification.ReadAt,
+	}
+}
+
+func toSubscriptionDTO(subscription *Subscription) SubscriptionDTO {
+	return SubscriptionDTO{
+		Id:      subscription.Id,
+		UserId:  subscription.UserId,
+		Channel: subscription.Channel,
+		Target:  subscription.Target,
+		Active:  subscription.Active,
+	}
+}
@@ -0,0 +1,136 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const SignatureHeader = "X-Family-Finance-Signature"
+
+type webhookPayload struct {
+	Id       string `json:"id"`
+	TenantId string `json:"tenant_id"`
+	UserId   string `json:"user_id"`
+	Category string `json:"category"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	SentAt   int64  `json:"sent_at"`
+}
+
+type webhookChannel struct {
+	client *http.Client
+}
+
+// NewWebhookChannel 创建 webhook 渠道，订阅配置了密钥时使用 HMAC-SHA256 对请求体签名
+func NewWebhookChannel(cfg WebhookConfig) Channel {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// 在建立连接时再次校验解析出的地址，防止 DNS 重绑定绕过保存时的校验
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return ErrWebhookTargetInvalid
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+	}
+	return &webhookChannel{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// validateWebhookTarget 只允许 https 且解析到公网地址的 webhook，避免被用来访问内网服务
+func validateWebhookTarget(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "https" || len(u.Hostname()) == 0 || u.User != nil {
+		return ErrWebhookTargetInvalid
+	}
+	ips, err := net.LookupIP(u.Hostname())
+	if err != nil || len(ips) == 0 {
+		return ErrWebhookTargetInvalid
+	}
+	for _, ip := range ips {
+		if !publicIP(ip) {
+			return ErrWebhookTargetInvalid
+		}
+	}
+	return nil
+}
+
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast()
+}
+
+func (ch *webhookChannel) Name() string {
+	return ChannelWebhook
+}
+
+func (ch *webhookChannel) Send(ctx context.Context, subscription *Subscription, notification *Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		Id:       notification.Id,
+		TenantId: notification.TenantId,
+		UserId:   notification.UserId,
+		Category: notification.Category,
+		Title:    notification.Title,
+		Content:  notification.Content,
+		SentAt:   time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := validateWebhookTarget(subscription.Target); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(subscription.Secret) > 0 {
+		mac := hmac.New(sha256.New, []byte(subscription.Secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := ch.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded %s", subscription.Target, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package payee
+
+type PayeeAliasDTO struct {
+	Pattern   string `json:"pattern" binding:"required"`
+	MatchType string `json:"match_type" binding:"omitempty,oneof=contains prefix regex"`
+}
+
+type PayeeDTO struct {
+	Id       string          `json:"id"`
+	TenantId string          `json:"tenant_id"`
+	Name     string          `json:"name"`
+	Aliases  []PayeeAliasDTO `json:"aliases"`
+	Active   bool            `json:"active"`
+}
+
+type CreatePayeeDTO struct {
+	TenantId string          `json:"tenant_id" binding:"required"`
+	Name     string          `json:"name" binding:"required,max_len=200"`
+	Aliases  []PayeeAliasDTO `json:"aliases" binding:"omitempty,dive"`
+}
+
+type UpdatePayeeDTO struct {
+	TenantId string          `json:"tenant_id" binding:"required"`
+	Id       string          `json:"id"`
+	Name     string          `json:"name" binding:"omitempty,max_len=200"`
+	Aliases  []PayeeAliasDTO `json:"aliases" binding:"omitempty,dive"`
+	Active   *bool           `json:"active" binding:"omitempty"`
+}
+
+type MatchPayeeDTO struct {
+	TenantId    string `json:"tenant_id" binding:"required"`
+	Description string `json:"description" binding:"required"`
+}
+
+type PayeeMatchDTO struct {
+	Description string    `json:"description"`
+	Matched     bool      `json:"matched"`
+	Pattern     string    `json:"pattern"`
+	Payee       *PayeeDTO `json:"payee"`
+}
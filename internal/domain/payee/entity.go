@@ -0,0 +1,27 @@
+package payee
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	AliasMatchContains = "contains"
+	AliasMatchPrefix   = "prefix"
+	AliasMatchRegex    = "regex"
+)
+
+type PayeeAlias struct {
+	Pattern   string `json:"pattern"`
+	MatchType string `json:"match_type"`
+}
+
+type Payee struct {
+	model.TenantBaseModel
+	Name    string       `json:"name" gorm:"size:200;not null"`
+	Aliases []PayeeAlias `json:"aliases" gorm:"type:text;serializer:json"`
+	Active  bool         `json:"active" gorm:"default:true"`
+}
+
+func (entity *Payee) TableName() string {
+	return "finance_payee"
+}
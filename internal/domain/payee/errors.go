@@ -0,0 +1,13 @@
+package payee
+
+import "errors"
+
+var (
+	ErrPayeeNotFound         = errors.New("收款方不存在")
+	ErrPayeeExists           = errors.New("收款方已存在")
+	ErrPayeeNameRequired     = errors.New("收款方名称不能为空")
+	ErrAliasPatternRequired  = errors.New("别名规则不能为空")
+	ErrAliasPatternInvalid   = errors.New("别名规则无效")
+	ErrAliasMatchTypeInvalid = errors.New("别名匹配方式无效")
+	ErrDescriptionRequired   = errors.New("交易描述不能为空")
+)
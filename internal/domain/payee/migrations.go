@@ -0,0 +1,17 @@
+package payee
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建收款方表
+	if err := db.AutoMigrate(&Payee{}); err != nil {
+		fmt.Println("创建收款方表失败", err)
+	}
+
+	fmt.Println("Payee模块迁移完成")
+}
@@ -0,0 +1,300 @@
+package payee
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+const payeePageSize = 500
+
+type PayeeService interface {
+	CreatePayee(ctx context.Context, req *request.DataRequest[CreatePayeeDTO]) (*response.DataResponse[PayeeDTO], error)
+	UpdatePayee(ctx context.Context, req *request.DataRequest[UpdatePayeeDTO]) (*response.DataResponse[PayeeDTO], error)
+	GetPayee(ctx context.Context, tenantId string, id string) (*response.DataResponse[PayeeDTO], error)
+	ListPayees(ctx context.Context, tenantId string) (*response.DataResponse[[]PayeeDTO], error)
+	MatchPayee(ctx context.Context, req *request.DataRequest[MatchPayeeDTO]) (*response.DataResponse[PayeeMatchDTO], error)
+}
+
+type service struct {
+	payeeRepo repository.Repository[Payee]
+}
+
+func NewPayeeService(payeeRepo repository.Repository[Payee]) PayeeService {
+	return &service{
+		payeeRepo: payeeRepo,
+	}
+}
+
+func (s *service) CreatePayee(ctx context.Context, req *request.DataRequest[CreatePayeeDTO]) (*response.DataResponse[PayeeDTO], error) {
+	name := strings.TrimSpace(req.Data.Name)
+	if len(name) == 0 {
+		return nil, ErrPayeeNameRequired
+	}
+	aliases, err := toAliases(req.Data.Aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNameAvailable(ctx, req.Data.TenantId, name, ""); err != nil {
+		return nil, err
+	}
+
+	payee := &Payee{
+		Name:            name,
+		Aliases:         aliases,
+		Active:          true,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	payee, err = s.payeeRepo.Add(ctx, payee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PayeeDTO]{
+		Data: toPayeeDTO(payee),
+	}, nil
+}
+
+func (s *service) UpdatePayee(ctx context.Context, req *request.DataRequest[UpdatePayeeDTO]) (*response.DataResponse[PayeeDTO], error) {
+	payee, err := s.findPayeeById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		if !strings.EqualFold(name, payee.Name) {
+			if err := s.checkNameAvailable(ctx, payee.TenantId, name, payee.Id); err != nil {
+				return nil, err
+			}
+		}
+		payee.Name = name
+	}
+	if req.Data.Aliases != nil {
+		aliases, err := toAliases(req.Data.Aliases)
+		if err != nil {
+			return nil, err
+		}
+		payee.Aliases = aliases
+	}
+	if req.Data.Active != nil {
+		payee.Active = *req.Data.Active
+	}
+
+	payee, err = s.payeeRepo.Update(ctx, payee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PayeeDTO]{
+		Data: toPayeeDTO(payee),
+	}, nil
+}
+
+func (s *service) GetPayee(ctx context.Context, tenantId string, id string) (*response.DataResponse[PayeeDTO], error) {
+	payee, err := s.findPayeeById(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PayeeDTO]{
+		Data: toPayeeDTO(payee),
+	}, nil
+}
+
+func (s *service) ListPayees(ctx context.Context, tenantId string) (*response.DataResponse[[]PayeeDTO], error) {
+	payees, err := s.findPayees(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]PayeeDTO, 0, len(payees))
+	for i := range payees {
+		dtos = append(dtos, toPayeeDTO(&payees[i]))
+	}
+	return &response.DataResponse[[]PayeeDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) MatchPayee(ctx context.Context, req *request.DataRequest[MatchPayeeDTO]) (*response.DataResponse[PayeeMatchDTO], error) {
+	if len(strings.TrimSpace(req.Data.Description)) == 0 {
+		return nil, ErrDescriptionRequired
+	}
+	payees, err := s.findPayees(ctx, req.Data.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := PayeeMatchDTO{
+		Description: req.Data.Description,
+	}
+	if payee, pattern := Match(payees, req.Data.Description); payee != nil {
+		dto := toPayeeDTO(payee)
+		result.Matched = true
+		result.Pattern = pattern
+		result.Payee = &dto
+	}
+
+	return &response.DataResponse[PayeeMatchDTO]{
+		Data: result,
+	}, nil
+}
+
+// checkNameAvailable 校验租户内收款方名称不重复，excludeId 为正在修改的收款方
+func (s *service) checkNameAvailable(ctx context.Context, tenantId string, name string, excludeId string) error {
+	payees, err := s.findPayees(ctx, tenantId)
+	if err != nil {
+		return err
+	}
+	for _, p := range payees {
+		if p.Id != excludeId && strings.EqualFold(p.Name, name) {
+			return ErrPayeeExists
+		}
+	}
+	return nil
+}
+
+// Match 根据原始交易描述匹配收款方，多个规则命中时取匹配内容最长的一个
+func Match(payees []Payee, description string) (*Payee, string) {
+	text := normalize(description)
+	var matched *Payee
+	matchedPattern := ""
+	best := 0
+	for i := range payees {
+		if !payees[i].Active {
+			continue
+		}
+		rules := append([]PayeeAlias{{Pattern: payees[i].Name, MatchType: AliasMatchContains}}, payees[i].Aliases...)
+		for _, rule := range rules {
+			if n := matchLength(rule, text); n > best {
+				best = n
+				matched = &payees[i]
+				matchedPattern = rule.Pattern
+			}
+		}
+	}
+	return matched, matchedPattern
+}
+
+func matchLength(alias PayeeAlias, text string) int {
+	pattern := normalize(alias.Pattern)
+	if len(pattern) == 0 {
+		return 0
+	}
+	switch alias.MatchType {
+	case AliasMatchPrefix:
+		if strings.HasPrefix(text, pattern) {
+			return len(pattern)
+		}
+	case AliasMatchRegex:
+		re, err := regexp.Compile("(?i)" + alias.Pattern)
+		if err != nil {
+			return 0
+		}
+		if loc := re.FindStringIndex(text); loc != nil {
+			return loc[1] - loc[0]
+		}
+	default:
+		if strings.Contains(text, pattern) {
+			return len(pattern)
+		}
+	}
+	return 0
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func toAliases(dtos []PayeeAliasDTO) ([]PayeeAlias, error) {
+	aliases := make([]PayeeAlias, 0, len(dtos))
+	for _, dto := range dtos {
+		pattern := strings.TrimSpace(dto.Pattern)
+		if len(pattern) == 0 {
+			return nil, ErrAliasPatternRequired
+		}
+		matchType := dto.MatchType
+		if len(matchType) == 0 {
+			matchType = AliasMatchContains
+		}
+		switch matchType {
+		case AliasMatchContains, AliasMatchPrefix:
+		case AliasMatchRegex:
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, ErrAliasPatternInvalid
+			}
+		default:
+			return nil, ErrAliasMatchTypeInvalid
+		}
+		aliases = append(aliases, PayeeAlias{Pattern: pattern, MatchType: matchType})
+	}
+	return aliases, nil
+}
+
+func toPayeeDTO(payee *Payee) PayeeDTO {
+	aliases := make([]PayeeAliasDTO, 0, len(payee.Aliases))
+	for _, alias := range payee.Aliases {
+		aliases = append(aliases, PayeeAliasDTO{Pattern: alias.Pattern, MatchType: alias.MatchType})
+	}
+	return PayeeDTO{
+		Id:       payee.Id,
+		TenantId: payee.TenantId,
+		Name:     payee.Name,
+		Aliases:  aliases,
+		Active:   payee.Active,
+	}
+}
+
+func (s *service) findPayeeById(ctx context.Context, tenantId string, id string) (*Payee, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	payees, err := s.payeeRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(payees) == 0 {
+		return nil, ErrPayeeNotFound
+	}
+	return &payees[0], nil
+}
+
+// findPayees 按 id 分页读取租户下的全部收款方
+func (s *service) findPayees(ctx context.Context, tenantId string) ([]Payee, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	payees := []Payee{}
+	for page := 1; ; page++ {
+		query := &query.DbQuery{
+			QueryWheres: wheres,
+			OrderBys:    []query.DbQueryOrderBy{query.NewDbQueryOrderBy("id", false)},
+			PageSize:    payeePageSize,
+			PageNumber:  page,
+		}
+		items, err := s.payeeRepo.Query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, items...)
+		if len(items) < payeePageSize {
+			return payees, nil
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package recurring
+
+type ScheduleDTO struct {
+	ScheduleKind  string `json:"schedule_kind" binding:"required,oneof=monthly_day every_n_weeks last_business_day cron"`
+	DayOfMonth    int    `json:"day_of_month" binding:"omitempty,min=1,max=31"`
+	IntervalWeeks int    `json:"interval_weeks" binding:"omitempty,min=1"`
+	CronExpr      string `json:"cron_expr" binding:"omitempty,max_len=100"`
+	StartDate     string `json:"start_date" binding:"required"`
+	EndDate       string `json:"end_date" binding:"omitempty"`
+}
+
+type TemplateDTO struct {
+	Id                string `json:"id"`
+	TenantId          string `json:"tenant_id"`
+	Name              string `json:"name"`
+	Direction         string `json:"direction"`
+	Amount            int64  `json:"amount"`
+	Currency          string `json:"currency"`
+	PayeeId           string `json:"payee_id"`
+	Memo              string `json:"memo"`
+	LastGeneratedDate string `json:"last_generated_date"`
+	Active            bool   `json:"active"`
+	ScheduleDTO
+}
+
+type CreateTemplateDTO struct {
+	TenantId  string `json:"tenant_id" binding:"required"`
+	Name      string `json:"name" binding:"required,max_len=200"`
+	Direction string `json:"direction" binding:"required,oneof=income expense"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"`
+	Currency  string `json:"currency" binding:"omitempty,len=3"`
+	PayeeId   string `json:"payee_id" binding:"omitempty"`
+	Memo      string `json:"memo" binding:"omitempty,max_len=500"`
+	ScheduleDTO
+}
+
+type UpdateTemplateDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	Id       string `json:"id"`
+	Name     string `json:"name" binding:"omitempty,max_len=200"`
+	Amount   int64  `json:"amount" binding:"omitempty,gt=0"`
+	PayeeId  string `json:"payee_id" binding:"omitempty"`
+	Memo     string `json:"memo" binding:"omitempty,max_len=500"`
+	Active   *bool  `json:"active" binding:"omitempty"`
+	// 为空时保持原周期规则
+	Schedule *ScheduleDTO `json:"schedule" binding:"omitempty"`
+}
+
+type PreviewScheduleDTO struct {
+	// 从该日期（含）开始预览，为空时从今天开始
+	From  string `json:"from" binding:"omitempty"`
+	Count int    `json:"count" binding:"omitempty,min=1,max=120"`
+	ScheduleDTO
+}
+
+type PreviewDTO struct {
+	Dates []string `json:"dates"`
+}
+
+type OccurrenceDTO struct {
+	Id         string `json:"id"`
+	TemplateId string `json:"template_id"`
+	DueDate    string `json:"due_date"`
+	Name       string `json:"name"`
+	Direction  string `json:"direction"`
+	Amount     int64  `json:"amount"`
+	Currency   string `json:"currency"`
+	PayeeId    string `json:"payee_id"`
+	Memo       string `json:"memo"`
+	Status     string `json:"status"`
+	HandledBy  string `json:"handled_by"`
+	HandledAt  int64  `json:"handled_at"`
+}
+
+type HandleOccurrenceDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	Id       string `json:"id"`
+	UserId   string `json:"user_id" binding:"required"`
+	Action   string `json:"action" binding:"required,oneof=confirm skip"`
+	// 确认时可修正实际金额
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
@@ -0,0 +1,62 @@
+package recurring
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	DirectionIncome  = "income"
+	DirectionExpense = "expense"
+
+	OccurrencePending   = "pending"
+	OccurrenceConfirmed = "confirmed"
+	OccurrenceSkipped   = "skipped"
+)
+
+// RecurringTemplate 工资、房租、房贷、水电、保费等周期性收支模板
+type RecurringTemplate struct {
+	model.TenantBaseModel
+	Name          string `json:"name" gorm:"size:200;not null"`
+	Direction     string `json:"direction" gorm:"size:20;not null"`
+	Amount        int64  `json:"amount" gorm:"not null"`
+	Currency      string `json:"currency" gorm:"size:3"`
+	PayeeId       string `json:"payee_id" gorm:"size:32"`
+	Memo          string `json:"memo" gorm:"size:500"`
+	ScheduleKind  string `json:"schedule_kind" gorm:"size:30;not null"`
+	DayOfMonth    int    `json:"day_of_month"`
+	IntervalWeeks int    `json:"interval_weeks"`
+	CronExpr      string `json:"cron_expr" gorm:"size:100"`
+	StartDate     string `json:"start_date" gorm:"size:10;not null"`
+	EndDate       string `json:"end_date" gorm:"size:10"`
+	// 已生成待确认记录的最后日期
+	LastGeneratedDate string `json:"last_generated_date" gorm:"size:10"`
+	Active            bool   `json:"active" gorm:"default:true"`
+}
+
+func (entity *RecurringTemplate) TableName() string {
+	return "finance_recurring_template"
+}
+
+func (entity *RecurringTemplate) Schedule() (*Schedule, error) {
+	return NewSchedule(entity.ScheduleKind, entity.StartDate, entity.EndDate, entity.DayOfMonth, entity.IntervalWeeks, entity.CronExpr)
+}
+
+// RecurringOccurrence 模板在某个到期日生成的待确认记录，同一模板同一天只会生成一条
+type RecurringOccurrence struct {
+	model.TenantBaseModel
+	TemplateId string `json:"template_id" gorm:"size:32;not null;uniqueIndex:idx_recurring_occurrence"`
+	DueDate    string `json:"due_date" gorm:"size:10;not null;uniqueIndex:idx_recurring_occurrence"`
+	Name       string `json:"name" gorm:"size:200;not null"`
+	Direction  string `json:"direction" gorm:"size:20;not null"`
+	Amount     int64  `json:"amount" gorm:"not null"`
+	Currency   string `json:"currency" gorm:"size:3"`
+	PayeeId    string `json:"payee_id" gorm:"size:32"`
+	Memo       string `json:"memo" gorm:"size:500"`
+	Status     string `json:"status" gorm:"size:20;not null;index"`
+	HandledBy  string `json:"handled_by" gorm:"size:32"`
+	HandledAt  int64  `json:"handled_at"`
+}
+
+func (entity *RecurringOccurrence) TableName() string {
+	return "finance_recurring_occurrence"
+}
@@ -0,0 +1,16 @@
+package recurring
+
+import "errors"
+
+var (
+	ErrTemplateNotFound     = errors.New("周期模板不存在")
+	ErrOccurrenceNotFound   = errors.New("待确认记录不存在")
+	ErrOccurrenceHandled    = errors.New("该记录已处理")
+	ErrScheduleKindInvalid  = errors.New("不支持的周期类型")
+	ErrStartDateInvalid     = errors.New("开始日期格式无效，应为YYYY-MM-DD")
+	ErrEndDateInvalid       = errors.New("结束日期无效")
+	ErrDayOfMonthInvalid    = errors.New("每月日期必须在1-31之间")
+	ErrIntervalWeeksInvalid = errors.New("间隔周数必须大于0")
+	ErrCronInvalid          = errors.New("cron表达式无效")
+	ErrAmountInvalid        = errors.New("金额必须大于0")
+)
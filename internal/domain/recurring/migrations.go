@@ -0,0 +1,22 @@
+package recurring
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建周期模板表
+	if err := db.AutoMigrate(&RecurringTemplate{}); err != nil {
+		fmt.Println("创建周期模板表失败", err)
+	}
+
+	// 创建周期待确认记录表
+	if err := db.AutoMigrate(&RecurringOccurrence{}); err != nil {
+		fmt.Println("创建周期待确认记录表失败", err)
+	}
+
+	fmt.Println("Recurring模块迁移完成")
+}
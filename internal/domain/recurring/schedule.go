@@ -0,0 +1,256 @@
+package recurring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ScheduleMonthlyDay      = "monthly_day"
+	ScheduleEveryNWeeks     = "every_n_weeks"
+	ScheduleLastBusinessDay = "last_business_day"
+	ScheduleCron            = "cron"
+
+	DateLayout = "2006-01-02"
+
+	// cron 表达式最多向后搜索的天数
+	maxCronSearchDays = 366 * 5
+)
+
+// Schedule 以天为粒度的重复规则
+type Schedule struct {
+	Kind          string
+	StartDate     time.Time
+	EndDate       time.Time
+	DayOfMonth    int
+	IntervalWeeks int
+	cron          *cronExpr
+}
+
+// NewSchedule 创建重复规则，endDate 为空表示不结束。
+// cron 表达式为三段式 "日 月 周"，支持 *、列表、范围、步长，日字段支持 L 表示月末，例如 "L 3,6,9,12 *"
+func NewSchedule(kind string, startDate string, endDate string, dayOfMonth int, intervalWeeks int, cron string) (*Schedule, error) {
+	start, err := time.Parse(DateLayout, startDate)
+	if err != nil {
+		return nil, ErrStartDateInvalid
+	}
+	s := &Schedule{
+		Kind:          kind,
+		StartDate:     start,
+		DayOfMonth:    dayOfMonth,
+		IntervalWeeks: intervalWeeks,
+	}
+	if len(endDate) > 0 {
+		if s.EndDate, err = time.Parse(DateLayout, endDate); err != nil || s.EndDate.Before(start) {
+			return nil, ErrEndDateInvalid
+		}
+	}
+
+	switch kind {
+	case ScheduleMonthlyDay:
+		if dayOfMonth < 1 || dayOfMonth > 31 {
+			return nil, ErrDayOfMonthInvalid
+		}
+	case ScheduleEveryNWeeks:
+		if intervalWeeks < 1 {
+			return nil, ErrIntervalWeeksInvalid
+		}
+	case ScheduleLastBusinessDay:
+	case ScheduleCron:
+		if s.cron, err = parseCron(cron); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrScheduleKindInvalid
+	}
+	return s, nil
+}
+
+// OnOrAfter 返回不早于 date 的第一次发生日期，超过结束日期时返回 false
+func (s *Schedule) OnOrAfter(date time.Time) (time.Time, bool) {
+	if date.Before(s.StartDate) {
+		date = s.StartDate
+	}
+
+	var next time.Time
+	switch s.Kind {
+	case ScheduleMonthlyDay:
+		next = monthDay(date.Year(), date.Month(), s.DayOfMonth)
+		if next.Before(date) {
+			next = monthDay(date.Year(), date.Month()+1, s.DayOfMonth)
+		}
+	case ScheduleLastBusinessDay:
+		next = lastBusinessDay(date.Year(), date.Month())
+		if next.Before(date) {
+			next = lastBusinessDay(date.Year(), date.Month()+1)
+		}
+	case ScheduleEveryNWeeks:
+		step := 7 * s.IntervalWeeks
+		days := int(date.Sub(s.StartDate).Hours() / 24)
+		periods := (days + step - 1) / step
+		next = s.StartDate.AddDate(0, 0, periods*step)
+	case ScheduleCron:
+		found := false
+		for i := 0; i < maxCronSearchDays; i++ {
+			if d := date.AddDate(0, 0, i); s.cron.match(d) {
+				next, found = d, true
+				break
+			}
+		}
+		if !found {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	if !s.EndDate.IsZero() && next.After(s.EndDate) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Next 返回不早于 from 的最多 n 次发生日期
+func (s *Schedule) Next(from time.Time, n int) []time.Time {
+	dates := []time.Time{}
+	for len(dates) < n {
+		next, ok := s.OnOrAfter(from)
+		if !ok {
+			break
+		}
+		dates = append(dates, next)
+		from = next.AddDate(0, 0, 1)
+	}
+	return dates
+}
+
+// Between 返回 [from, to] 区间内的全部发生日期
+func (s *Schedule) Between(from time.Time, to time.Time) []time.Time {
+	dates := []time.Time{}
+	for {
+		next, ok := s.OnOrAfter(from)
+		if !ok || next.After(to) {
+			break
+		}
+		dates = append(dates, next)
+		from = next.AddDate(0, 0, 1)
+	}
+	return dates
+}
+
+// monthDay 返回指定月份的第 day 天，月份天数不足时取月末
+func monthDay(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	if day > last.Day() {
+		return last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// lastBusinessDay 返回月内最后一个周一至周五，不含法定节假日调整
+func lastBusinessDay(year int, month time.Month) time.Time {
+	d := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	for d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+type cronField struct {
+	any    bool
+	values map[int]bool
+}
+
+type cronExpr struct {
+	dayOfMonth cronField
+	lastDay    bool
+	month      cronField
+	dayOfWeek  cronField
+}
+
+func parseCron(expr string) (*cronExpr, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 3 {
+		return nil, ErrCronInvalid
+	}
+	c := &cronExpr{}
+	domField := fields[0]
+	if domField == "L" {
+		c.lastDay = true
+	} else {
+		var err error
+		if c.dayOfMonth, err = parseCronField(domField, 1, 31); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if c.month, err = parseCronField(fields[1], 1, 12); err != nil {
+		return nil, err
+	}
+	if c.dayOfWeek, err = parseCronField(fields[2], 0, 7); err != nil {
+		return nil, err
+	}
+	// 周日既可写 0 也可写 7
+	if c.dayOfWeek.values[7] {
+		c.dayOfWeek.values[0] = true
+	}
+	return c, nil
+}
+
+func parseCronField(field string, lo int, hi int) (cronField, error) {
+	if field == "*" {
+		return cronField{any: true}, nil
+	}
+	values := map[int]bool{}
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return cronField{}, fmt.Errorf("%w: %s", ErrCronInvalid, item)
+			}
+		}
+		from, to := lo, hi
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = strconv.Atoi(startPart); err != nil {
+				return cronField{}, fmt.Errorf("%w: %s", ErrCronInvalid, item)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(endPart); err != nil {
+					return cronField{}, fmt.Errorf("%w: %s", ErrCronInvalid, item)
+				}
+			} else if hasStep {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return cronField{}, fmt.Errorf("%w: %s", ErrCronInvalid, item)
+		}
+		for v := from; v <= to; v += step {
+			values[v] = true
+		}
+	}
+	return cronField{values: values}, nil
+}
+
+func (c *cronExpr) match(d time.Time) bool {
+	if !c.month.any && !c.month.values[int(d.Month())] {
+		return false
+	}
+	domMatch := c.dayOfMonth.any || c.dayOfMonth.values[d.Day()]
+	if c.lastDay {
+		domMatch = d.AddDate(0, 0, 1).Day() == 1
+	}
+	dowMatch := c.dayOfWeek.any || c.dayOfWeek.values[int(d.Weekday())]
+	// 与标准 cron 一致：日和周同时限定时满足其一即可
+	if (c.lastDay || !c.dayOfMonth.any) && !c.dayOfWeek.any {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
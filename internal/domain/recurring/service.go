@@ -0,0 +1,437 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+const (
+	defaultPreviewCount = 12
+	templatePageSize    = 500
+)
+
+type RecurringService interface {
+	CreateTemplate(ctx context.Context, req *request.DataRequest[CreateTemplateDTO]) (*response.DataResponse[TemplateDTO], error)
+	UpdateTemplate(ctx context.Context, req *request.DataRequest[UpdateTemplateDTO]) (*response.DataResponse[TemplateDTO], error)
+	ListTemplates(ctx context.Context, tenantId string) (*response.DataResponse[[]TemplateDTO], error)
+	PreviewTemplate(ctx context.Context, tenantId string, id string, count int) (*response.DataResponse[PreviewDTO], error)
+	PreviewSchedule(ctx context.Context, req *request.DataRequest[PreviewScheduleDTO]) (*response.DataResponse[PreviewDTO], error)
+	ListOccurrences(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]OccurrenceDTO], error)
+	HandleOccurrence(ctx context.Context, req *request.DataRequest[HandleOccurrenceDTO]) (*response.DataResponse[OccurrenceDTO], error)
+	// GenerateDueOccurrences 为所有租户的有效模板生成截至 asOf 的待确认记录，可重复执行
+	GenerateDueOccurrences(ctx context.Context, asOf time.Time) (int, error)
+}
+
+type service struct {
+	templateRepo   repository.Repository[RecurringTemplate]
+	occurrenceRepo repository.Repository[RecurringOccurrence]
+}
+
+func NewRecurringService(
+	templateRepo repository.Repository[RecurringTemplate],
+	occurrenceRepo repository.Repository[RecurringOccurrence],
+) RecurringService {
+	return &service{
+		templateRepo:   templateRepo,
+		occurrenceRepo: occurrenceRepo,
+	}
+}
+
+func (s *service) CreateTemplate(ctx context.Context, req *request.DataRequest[CreateTemplateDTO]) (*response.DataResponse[TemplateDTO], error) {
+	if req.Data.Amount <= 0 {
+		return nil, ErrAmountInvalid
+	}
+	template := &RecurringTemplate{
+		Name:            strings.TrimSpace(req.Data.Name),
+		Direction:       req.Data.Direction,
+		Amount:          req.Data.Amount,
+		Currency:        strings.ToUpper(req.Data.Currency),
+		PayeeId:         req.Data.PayeeId,
+		Memo:            req.Data.Memo,
+		Active:          true,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	applySchedule(template, &req.Data.ScheduleDTO)
+	schedule, err := template.Schedule()
+	if err != nil {
+		return nil, err
+	}
+	skipPastOccurrences(template, schedule)
+
+	template, err = s.templateRepo.Add(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[TemplateDTO]{
+		Data: toTemplateDTO(template),
+	}, nil
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, req *request.DataRequest[UpdateTemplateDTO]) (*response.DataResponse[TemplateDTO], error) {
+	template, err := s.findTemplateById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Data.Name); len(name) > 0 {
+		template.Name = name
+	}
+	if req.Data.Amount > 0 {
+		template.Amount = req.Data.Amount
+	}
+	if len(req.Data.PayeeId) > 0 {
+		template.PayeeId = req.Data.PayeeId
+	}
+	if len(req.Data.Memo) > 0 {
+		template.Memo = req.Data.Memo
+	}
+	if req.Data.Active != nil {
+		template.Active = *req.Data.Active
+	}
+	if req.Data.Schedule != nil {
+		applySchedule(template, req.Data.Schedule)
+		schedule, err := template.Schedule()
+		if err != nil {
+			return nil, err
+		}
+		if len(template.LastGeneratedDate) == 0 {
+			skipPastOccurrences(template, schedule)
+		}
+	}
+
+	template, err = s.templateRepo.Update(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[TemplateDTO]{
+		Data: toTemplateDTO(template),
+	}, nil
+}
+
+// skipPastOccurrences 开始日期早于今天时不补生成历史记录
+func skipPastOccurrences(template *RecurringTemplate, schedule *Schedule) {
+	if schedule.StartDate.Before(today()) {
+		template.LastGeneratedDate = today().AddDate(0, 0, -1).Format(DateLayout)
+	}
+}
+
+func (s *service) ListTemplates(ctx context.Context, tenantId string) (*response.DataResponse[[]TemplateDTO], error) {
+	templates, err := s.findTemplates(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]TemplateDTO, 0, len(templates))
+	for i := range templates {
+		dtos = append(dtos, toTemplateDTO(&templates[i]))
+	}
+	return &response.DataResponse[[]TemplateDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) PreviewTemplate(ctx context.Context, tenantId string, id string, count int) (*response.DataResponse[PreviewDTO], error) {
+	template, err := s.findTemplateById(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := template.Schedule()
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[PreviewDTO]{
+		Data: preview(schedule, today(), count),
+	}, nil
+}
+
+func (s *service) PreviewSchedule(ctx context.Context, req *request.DataRequest[PreviewScheduleDTO]) (*response.DataResponse[PreviewDTO], error) {
+	schedule, err := NewSchedule(req.Data.ScheduleKind, req.Data.StartDate, req.Data.EndDate, req.Data.DayOfMonth, req.Data.IntervalWeeks, req.Data.CronExpr)
+	if err != nil {
+		return nil, err
+	}
+	from := today()
+	if len(req.Data.From) > 0 {
+		if from, err = time.Parse(DateLayout, req.Data.From); err != nil {
+			return nil, ErrStartDateInvalid
+		}
+	}
+
+	return &response.DataResponse[PreviewDTO]{
+		Data: preview(schedule, from, req.Data.Count),
+	}, nil
+}
+
+func (s *service) ListOccurrences(ctx context.Context, tenantId string, status string) (*response.DataResponse[[]OccurrenceDTO], error) {
+	occurrences, err := s.findOccurrences(ctx, "tenant_id", tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].DueDate < occurrences[j].DueDate
+	})
+
+	dtos := []OccurrenceDTO{}
+	for i := range occurrences {
+		if len(status) > 0 && occurrences[i].Status != status {
+			continue
+		}
+		dtos = append(dtos, toOccurrenceDTO(&occurrences[i]))
+	}
+	return &response.DataResponse[[]OccurrenceDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) HandleOccurrence(ctx context.Context, req *request.DataRequest[HandleOccurrenceDTO]) (*response.DataResponse[OccurrenceDTO], error) {
+	occurrences, err := s.findOccurrences(ctx, "id", req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(occurrences) == 0 || occurrences[0].TenantId != req.Data.TenantId {
+		return nil, ErrOccurrenceNotFound
+	}
+	occurrence := &occurrences[0]
+	if occurrence.Status != OccurrencePending {
+		return nil, ErrOccurrenceHandled
+	}
+
+	occurrence.Status = OccurrenceSkipped
+	if req.Data.Action == "confirm" {
+		occurrence.Status = OccurrenceConfirmed
+		if req.Data.Amount > 0 {
+			occurrence.Amount = req.Data.Amount
+		}
+	}
+	occurrence.HandledBy = req.Data.UserId
+	occurrence.HandledAt = time.Now().UnixMilli()
+
+	occurrence, err = s.occurrenceRepo.Update(ctx, occurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[OccurrenceDTO]{
+		Data: toOccurrenceDTO(occurrence),
+	}, nil
+}
+
+func (s *service) GenerateDueOccurrences(ctx context.Context, asOf time.Time) (int, error) {
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+
+	created := 0
+	var errs error
+	// 分页遍历全部租户的模板，单个模板失败不影响其他模板
+	for page := 1; ; page++ {
+		templates, err := s.findTemplatePage(ctx, page)
+		if err != nil {
+			return created, errors.Join(errs, err)
+		}
+		for i := range templates {
+			template := &templates[i]
+			if !template.Active {
+				continue
+			}
+			n, err := s.generateForTemplate(ctx, template, asOf)
+			created += n
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("recurring template %s: %w", template.Id, err))
+			}
+		}
+		if len(templates) < templatePageSize {
+			break
+		}
+	}
+	return created, errs
+}
+
+func (s *service) generateForTemplate(ctx context.Context, template *RecurringTemplate, asOf time.Time) (int, error) {
+	schedule, err := template.Schedule()
+	if err != nil {
+		return 0, err
+	}
+	from := schedule.StartDate
+	if len(template.LastGeneratedDate) > 0 {
+		last, err := time.Parse(DateLayout, template.LastGeneratedDate)
+		if err == nil {
+			from = last.AddDate(0, 0, 1)
+		}
+	}
+	dates := schedule.Between(from, asOf)
+	if len(dates) == 0 {
+		return 0, nil
+	}
+
+	existing, err := s.findOccurrences(ctx, "template_id", template.Id)
+	if err != nil {
+		return 0, err
+	}
+	generated := map[string]bool{}
+	for _, o := range existing {
+		generated[o.DueDate] = true
+	}
+
+	created := 0
+	for _, date := range dates {
+		dueDate := date.Format(DateLayout)
+		if generated[dueDate] {
+			continue
+		}
+		occurrence := &RecurringOccurrence{
+			TemplateId:      template.Id,
+			DueDate:         dueDate,
+			Name:            template.Name,
+			Direction:       template.Direction,
+			Amount:          template.Amount,
+			Currency:        template.Currency,
+			PayeeId:         template.PayeeId,
+			Memo:            template.Memo,
+			Status:          OccurrencePending,
+			TenantBaseModel: model.NewTenantBaseModel(template.TenantId, util.GenerateId()),
+		}
+		if _, err := s.occurrenceRepo.Add(ctx, occurrence); err != nil {
+			return created, err
+		}
+		created++
+	}
+
+	template.LastGeneratedDate = dates[len(dates)-1].Format(DateLayout)
+	if _, err := s.templateRepo.Update(ctx, template); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+func preview(schedule *Schedule, from time.Time, count int) PreviewDTO {
+	if count <= 0 {
+		count = defaultPreviewCount
+	}
+	dates := []string{}
+	for _, d := range schedule.Next(from, count) {
+		dates = append(dates, d.Format(DateLayout))
+	}
+	return PreviewDTO{Dates: dates}
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func applySchedule(template *RecurringTemplate, dto *ScheduleDTO) {
+	template.ScheduleKind = dto.ScheduleKind
+	template.DayOfMonth = dto.DayOfMonth
+	template.IntervalWeeks = dto.IntervalWeeks
+	template.CronExpr = strings.TrimSpace(dto.CronExpr)
+	template.StartDate = dto.StartDate
+	template.EndDate = dto.EndDate
+}
+
+func toTemplateDTO(template *RecurringTemplate) TemplateDTO {
+	return TemplateDTO{
+		Id:                template.Id,
+		TenantId:          template.TenantId,
+		Name:              template.Name,
+		Direction:         template.Direction,
+		Amount:            template.Amount,
+		Currency:          template.Currency,
+		PayeeId:           template.PayeeId,
+		Memo:              template.Memo,
+		LastGeneratedDate: template.LastGeneratedDate,
+		Active:            template.Active,
+		ScheduleDTO: ScheduleDTO{
+			ScheduleKind:  template.ScheduleKind,
+			DayOfMonth:    template.DayOfMonth,
+			IntervalWeeks: template.IntervalWeeks,
+			CronExpr:      template.CronExpr,
+			StartDate:     template.StartDate,
+			EndDate:       template.EndDate,
+		},
+	}
+}
+
+func toOccurrenceDTO(occurrence *RecurringOccurrence) OccurrenceDTO {
+	return OccurrenceDTO{
+		Id:         occurrence.Id,
+		TemplateId: occurrence.TemplateId,
+		DueDate:    occurrence.DueDate,
+		Name:       occurrence.Name,
+		Direction:  occurrence.Direction,
+		Amount:     occurrence.Amount,
+		Currency:   occurrence.Currency,
+		PayeeId:    occurrence.PayeeId,
+		Memo:       occurrence.Memo,
+		Status:     occurrence.Status,
+		HandledBy:  occurrence.HandledBy,
+		HandledAt:  occurrence.HandledAt,
+	}
+}
+
+func (s *service) findTemplateById(ctx context.Context, tenantId string, id string) (*RecurringTemplate, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	templates, err := s.templateRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+	return &templates[0], nil
+}
+
+func (s *service) findTemplates(ctx context.Context, tenantId string) ([]RecurringTemplate, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.templateRepo.Query(ctx, query)
+}
+
+func (s *service) findTemplatePage(ctx context.Context, page int) ([]RecurringTemplate, error) {
+	query := &query.DbQuery{
+		QueryWheres: []query.DbQueryWhere{},
+		OrderBys:    []query.DbQueryOrderBy{query.NewDbQueryOrderBy("id", false)},
+		PageSize:    templatePageSize,
+		PageNumber:  page,
+	}
+	return s.templateRepo.Query(ctx, query)
+}
+
+func (s *service) findOccurrences(ctx context.Context, field string, value string) ([]RecurringOccurrence, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.occurrenceRepo.Query(ctx, query)
+}
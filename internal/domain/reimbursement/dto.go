@@ -0,0 +1,115 @@
+package reimbursement
+
+type ClaimDTO struct {
+	Id               string `json:"id"`
+	TenantId         string `json:"tenant_id"`
+	UserId           string `json:"user_id"`
+	PayerType        string `json:"payer_type"`
+	PayerName        string `json:"payer_name"`
+	Title            string `json:"title"`
+	Category         string `json:"category"`
+	ExpenseDate      string `json:"expense_date"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	Status           string `json:"status"`
+	SourceType       string `json:"source_type"`
+	SourceId         string `json:"source_id"`
+	SubmittedDate    string `json:"submitted_date"`
+	ReimbursedAmount int64  `json:"reimbursed_amount"`
+	Outstanding      int64  `json:"outstanding"`
+	ClosedDate       string `json:"closed_date"`
+	Memo             string `json:"memo"`
+}
+
+type CreateClaimDTO struct {
+	TenantId    string `json:"tenant_id" binding:"required"`
+	UserId      string `json:"user_id" binding:"required"`
+	PayerType   string `json:"payer_type" binding:"required,oneof=employer insurance person"`
+	PayerName   string `json:"payer_name" binding:"required,max_len=100"`
+	Title       string `json:"title" binding:"required,max_len=200"`
+	Category    string `json:"category" binding:"omitempty,max_len=50"`
+	ExpenseDate string `json:"expense_date" binding:"required"`
+	Amount      int64  `json:"amount" binding:"required,min=1"`
+	Currency    string `json:"currency" binding:"omitempty,len=3"`
+	SourceType  string `json:"source_type" binding:"omitempty,max_len=50"`
+	SourceId    string `json:"source_id" binding:"omitempty"`
+	Memo        string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type UpdateClaimDTO struct {
+	TenantId   string `json:"tenant_id" binding:"required"`
+	Id         string `json:"id"`
+	PayerName  string `json:"payer_name" binding:"omitempty,max_len=100"`
+	Title      string `json:"title" binding:"omitempty,max_len=200"`
+	Category   string `json:"category" binding:"omitempty,max_len=50"`
+	Amount     int64  `json:"amount" binding:"omitempty,min=1"`
+	SourceType string `json:"source_type" binding:"omitempty,max_len=50"`
+	SourceId   string `json:"source_id" binding:"omitempty"`
+	Memo       string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type SubmitClaimDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	Id       string `json:"id"`
+	// 为空时为今天
+	Date string `json:"date" binding:"omitempty"`
+}
+
+type CloseClaimDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	Id       string `json:"id"`
+	// 为空时为今天
+	Date string `json:"date" binding:"omitempty"`
+	Memo string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type ReceiptDTO struct {
+	Id        string `json:"id"`
+	ClaimId   string `json:"claim_id"`
+	Date      string `json:"date"`
+	Amount    int64  `json:"amount"`
+	Reference string `json:"reference"`
+	Memo      string `json:"memo"`
+}
+
+type CreateReceiptDTO struct {
+	TenantId string `json:"tenant_id" binding:"required"`
+	ClaimId  string `json:"claim_id"`
+	// 为空时为全部待报销金额
+	Amount int64 `json:"amount" binding:"omitempty,min=1"`
+	// 为空时为今天
+	Date      string `json:"date" binding:"omitempty"`
+	Reference string `json:"reference" binding:"omitempty,max_len=100"`
+	Memo      string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type ClaimDetailDTO struct {
+	Claim    ClaimDTO     `json:"claim"`
+	Receipts []ReceiptDTO `json:"receipts"`
+}
+
+// PayerReceivableDTO 某一报销方尚未到账的金额
+type PayerReceivableDTO struct {
+	PayerType   string `json:"payer_type"`
+	PayerName   string `json:"payer_name"`
+	Currency    string `json:"currency"`
+	ClaimCount  int    `json:"claim_count"`
+	ToSubmit    int64  `json:"to_submit"`
+	Submitted   int64  `json:"submitted"`
+	Outstanding int64  `json:"outstanding"`
+	// 最早一笔未报销支出的日期
+	OldestExpenseDate string `json:"oldest_expense_date"`
+}
+
+type OutstandingReportDTO struct {
+	Payers []PayerReceivableDTO `json:"payers"`
+	Claims []ClaimDTO           `json:"claims"`
+}
+
+type Receivable struct {
+	ClaimId     string `json:"claim_id"`
+	Title       string `json:"title"`
+	UserId      string `json:"user_id"`
+	Currency    string `json:"currency"`
+	Outstanding int64  `json:"outstanding"`
+}
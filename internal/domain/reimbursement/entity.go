@@ -0,0 +1,61 @@
+package reimbursement
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+const (
+	PayerEmployer  = "employer"
+	PayerInsurance = "insurance"
+	PayerPerson    = "person"
+
+	StatusToSubmit            = "to_submit"
+	StatusSubmitted           = "submitted"
+	StatusPartiallyReimbursed = "partially_reimbursed"
+	StatusReimbursed          = "reimbursed"
+	// 剩余部分不再报销，例如保险只赔付了部分费用
+	StatusClosed = "closed"
+
+	DateLayout = "2006-01-02"
+)
+
+// Claim 一笔待由单位、保险或他人报销的支出
+type Claim struct {
+	model.TenantBaseModel
+	UserId    string `json:"user_id" gorm:"size:32;not null;index"`
+	PayerType string `json:"payer_type" gorm:"size:20;not null"`
+	PayerName string `json:"payer_name" gorm:"size:100;not null"`
+	Title     string `json:"title" gorm:"size:200;not null"`
+	// 报销类别，如 medical、travel
+	Category    string `json:"category" gorm:"size:50"`
+	ExpenseDate string `json:"expense_date" gorm:"size:10;not null"`
+	Amount      int64  `json:"amount" gorm:"not null"`
+	Currency    string `json:"currency" gorm:"size:3"`
+	Status      string `json:"status" gorm:"size:30;not null;index"`
+	// 原始支出，如 bill、shared_expense 及其 ID
+	SourceType       string `json:"source_type" gorm:"size:50;index:idx_reimbursement_source"`
+	SourceId         string `json:"source_id" gorm:"size:32;index:idx_reimbursement_source"`
+	SubmittedDate    string `json:"submitted_date" gorm:"size:10"`
+	ReimbursedAmount int64  `json:"reimbursed_amount"`
+	ClosedDate       string `json:"closed_date" gorm:"size:10"`
+	Memo             string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Claim) TableName() string {
+	return "finance_reimbursement_claim"
+}
+
+// Receipt 报销到账记录
+type Receipt struct {
+	model.TenantBaseModel
+	ClaimId string `json:"claim_id" gorm:"size:32;not null;index"`
+	Date    string `json:"date" gorm:"size:10;not null"`
+	Amount  int64  `json:"amount" gorm:"not null"`
+	// 到账流水号等外部参考
+	Reference string `json:"reference" gorm:"size:100"`
+	Memo      string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *Receipt) TableName() string {
+	return "finance_reimbursement_receipt"
+}
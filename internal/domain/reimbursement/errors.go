@@ -0,0 +1,14 @@
+package reimbursement
+
+import "errors"
+
+var (
+	ErrClaimNotFound         = errors.New("报销单不存在")
+	ErrClaimFinished         = errors.New("报销单已结束")
+	ErrClaimAlreadySubmitted = errors.New("报销单已提交")
+	ErrDateInvalid           = errors.New("日期格式无效，应为YYYY-MM-DD")
+	ErrDateBeforeExpense     = errors.New("日期不能早于支出日期")
+	ErrReceiptTooLarge       = errors.New("到账金额超过待报销金额")
+	ErrAmountBelowReimbursed = errors.New("报销金额不能低于已到账金额")
+	ErrSourceIncomplete      = errors.New("原始支出类型和ID需同时填写")
+)
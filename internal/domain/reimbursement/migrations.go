@@ -0,0 +1,22 @@
+package reimbursement
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建报销单表
+	if err := db.AutoMigrate(&Claim{}); err != nil {
+		fmt.Println("创建报销单表失败", err)
+	}
+
+	// 创建报销到账表
+	if err := db.AutoMigrate(&Receipt{}); err != nil {
+		fmt.Println("创建报销到账表失败", err)
+	}
+
+	fmt.Println("Reimbursement模块迁移完成")
+}
@@ -0,0 +1,522 @@
+package reimbursement
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+)
+
+type ReimbursementService interface {
+	CreateClaim(ctx context.Context, req *request.DataRequest[CreateClaimDTO]) (*response.DataResponse[ClaimDTO], error)
+	UpdateClaim(ctx context.Context, req *request.DataRequest[UpdateClaimDTO]) (*response.DataResponse[ClaimDTO], error)
+	// ListClaims 按成员和状态筛选报销单，参数为空时不筛选
+	ListClaims(ctx context.Context, tenantId string, userId string, status string) (*response.DataResponse[[]ClaimDTO], error)
+	GetClaim(ctx context.Context, tenantId string, id string) (*response.DataResponse[ClaimDetailDTO], error)
+	SubmitClaim(ctx context.Context, req *request.DataRequest[SubmitClaimDTO]) (*response.DataResponse[ClaimDTO], error)
+	// RecordReceipt 记录一笔报销到账，并按累计到账金额更新状态
+	RecordReceipt(ctx context.Context, req *request.DataRequest[CreateReceiptDTO]) (*response.DataResponse[ClaimDTO], error)
+	// CloseClaim 结束报销单，未到账部分不再计入应收
+	CloseClaim(ctx context.Context, req *request.DataRequest[CloseClaimDTO]) (*response.DataResponse[ClaimDTO], error)
+	// GetOutstanding 按报销方汇总尚未到账的金额，userId 为空时汇总全家
+	GetOutstanding(ctx context.Context, tenantId string, userId string) (*response.DataResponse[OutstandingReportDTO], error)
+	ReceivableReader
+}
+
+// ReceivableReader 供净资产等模块读取某日尚未到账的报销款
+type ReceivableReader interface {
+	// Receivables 返回 asOf 当日未到账的报销金额，只计入 asOf（含）之前的到账，asOf 为空时按全部到账
+	Receivables(ctx context.Context, tenantId string, asOf string) ([]Receivable, error)
+}
+
+type service struct {
+	claimRepo   repository.Repository[Claim]
+	receiptRepo repository.Repository[Receipt]
+}
+
+func NewReimbursementService(
+	claimRepo repository.Repository[Claim],
+	receiptRepo repository.Repository[Receipt],
+) ReimbursementService {
+	return &service{
+		claimRepo:   claimRepo,
+		receiptRepo: receiptRepo,
+	}
+}
+
+func (s *service) CreateClaim(ctx context.Context, req *request.DataRequest[CreateClaimDTO]) (*response.DataResponse[ClaimDTO], error) {
+	if !validDate(req.Data.ExpenseDate) {
+		return nil, ErrDateInvalid
+	}
+	if (len(req.Data.SourceType) == 0) != (len(req.Data.SourceId) == 0) {
+		return nil, ErrSourceIncomplete
+	}
+
+	claim := &Claim{
+		UserId:          req.Data.UserId,
+		PayerType:       req.Data.PayerType,
+		PayerName:       strings.TrimSpace(req.Data.PayerName),
+		Title:           strings.TrimSpace(req.Data.Title),
+		Category:        req.Data.Category,
+		ExpenseDate:     req.Data.ExpenseDate,
+		Amount:          req.Data.Amount,
+		Currency:        strings.ToUpper(req.Data.Currency),
+		Status:          StatusToSubmit,
+		SourceType:      req.Data.SourceType,
+		SourceId:        req.Data.SourceId,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	claim, err := s.claimRepo.Add(ctx, claim)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[ClaimDTO]{
+		Data: toClaimDTO(claim),
+	}, nil
+}
+
+func (s *service) UpdateClaim(ctx context.Context, req *request.DataRequest[UpdateClaimDTO]) (*response.DataResponse[ClaimDTO], error) {
+	if (len(req.Data.SourceType) == 0) != (len(req.Data.SourceId) == 0) {
+		return nil, ErrSourceIncomplete
+	}
+	claim, err := s.findClaimById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status == StatusClosed {
+		return nil, ErrClaimFinished
+	}
+
+	if name := strings.TrimSpace(req.Data.PayerName); len(name) > 0 {
+		claim.PayerName = name
+	}
+	if title := strings.TrimSpace(req.Data.Title); len(title) > 0 {
+		claim.Title = title
+	}
+	if len(req.Data.Category) > 0 {
+		claim.Category = req.Data.Category
+	}
+	if req.Data.Amount > 0 {
+		if req.Data.Amount < claim.ReimbursedAmount {
+			return nil, ErrAmountBelowReimbursed
+		}
+		claim.Amount = req.Data.Amount
+	}
+	if len(req.Data.SourceType) > 0 {
+		claim.SourceType = req.Data.SourceType
+		claim.SourceId = req.Data.SourceId
+	}
+	if len(req.Data.Memo) > 0 {
+		claim.Memo = req.Data.Memo
+	}
+	claim.Status = statusOf(claim)
+
+	claim, err = s.claimRepo.Update(ctx, claim)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[ClaimDTO]{
+		Data: toClaimDTO(claim),
+	}, nil
+}
+
+func (s *service) ListClaims(ctx context.Context, tenantId string, userId string, status string) (*response.DataResponse[[]ClaimDTO], error) {
+	claims, err := s.findClaims(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].ExpenseDate > claims[j].ExpenseDate
+	})
+
+	dtos := []ClaimDTO{}
+	for i := range claims {
+		if len(userId) > 0 && claims[i].UserId != userId {
+			continue
+		}
+		if len(status) > 0 && claims[i].Status != status {
+			continue
+		}
+		dtos = append(dtos, toClaimDTO(&claims[i]))
+	}
+	return &response.DataResponse[[]ClaimDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) GetClaim(ctx context.Context, tenantId string, id string) (*response.DataResponse[ClaimDetailDTO], error) {
+	claim, err := s.findClaimById(ctx, tenantId, id)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := s.findReceipts(ctx, "claim_id", claim.Id)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(receipts, func(i, j int) bool {
+		return receipts[i].Date < receipts[j].Date
+	})
+
+	receiptDTOs := make([]ReceiptDTO, 0, len(receipts))
+	for _, receipt := range receipts {
+		receiptDTOs = append(receiptDTOs, ReceiptDTO{
+			Id:        receipt.Id,
+			ClaimId:   receipt.ClaimId,
+			Date:      receipt.Date,
+			Amount:    receipt.Amount,
+			Reference: receipt.Reference,
+			Memo:      receipt.Memo,
+		})
+	}
+
+	return &response.DataResponse[ClaimDetailDTO]{
+		Data: ClaimDetailDTO{
+			Claim:    toClaimDTO(claim),
+			Receipts: receiptDTOs,
+		},
+	}, nil
+}
+
+func (s *service) SubmitClaim(ctx context.Context, req *request.DataRequest[SubmitClaimDTO]) (*response.DataResponse[ClaimDTO], error) {
+	date, err := dateOrToday(req.Data.Date)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := s.findClaimById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if finished(claim) {
+		return nil, ErrClaimFinished
+	}
+	if len(claim.SubmittedDate) > 0 {
+		return nil, ErrClaimAlreadySubmitted
+	}
+	if date < claim.ExpenseDate {
+		return nil, ErrDateBeforeExpense
+	}
+
+	claim.SubmittedDate = date
+	claim.Status = statusOf(claim)
+	claim, err = s.claimRepo.Update(ctx, claim)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[ClaimDTO]{
+		Data: toClaimDTO(claim),
+	}, nil
+}
+
+func (s *service) RecordReceipt(ctx context.Context, req *request.DataRequest[CreateReceiptDTO]) (*response.DataResponse[ClaimDTO], error) {
+	date, err := dateOrToday(req.Data.Date)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := s.findClaimById(ctx, req.Data.TenantId, req.Data.ClaimId)
+	if err != nil {
+		return nil, err
+	}
+	if finished(claim) {
+		return nil, ErrClaimFinished
+	}
+	if date < claim.ExpenseDate {
+		return nil, ErrDateBeforeExpense
+	}
+	receipts, err := s.findReceipts(ctx, "claim_id", claim.Id)
+	if err != nil {
+		return nil, err
+	}
+	outstanding := claim.Amount - receivedAmount(receipts)
+	amount := req.Data.Amount
+	if amount == 0 {
+		amount = outstanding
+	}
+	if amount > outstanding {
+		return nil, ErrReceiptTooLarge
+	}
+
+	receipt := &Receipt{
+		ClaimId:         claim.Id,
+		Date:            date,
+		Amount:          amount,
+		Reference:       req.Data.Reference,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(claim.TenantId, util.GenerateId()),
+	}
+	if _, err := s.receiptRepo.Add(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	// 按全部到账记录重新汇总，避免并发到账互相覆盖
+	receipts, err = s.findReceipts(ctx, "claim_id", claim.Id)
+	if err != nil {
+		return nil, err
+	}
+	claim.ReimbursedAmount = receivedAmount(receipts)
+	claim.Status = statusOf(claim)
+	claim, err = s.claimRepo.Update(ctx, claim)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[ClaimDTO]{
+		Data: toClaimDTO(claim),
+	}, nil
+}
+
+func (s *service) CloseClaim(ctx context.Context, req *request.DataRequest[CloseClaimDTO]) (*response.DataResponse[ClaimDTO], error) {
+	date, err := dateOrToday(req.Data.Date)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := s.findClaimById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if finished(claim) {
+		return nil, ErrClaimFinished
+	}
+	if date < claim.ExpenseDate {
+		return nil, ErrDateBeforeExpense
+	}
+
+	claim.Status = StatusClosed
+	claim.ClosedDate = date
+	if len(req.Data.Memo) > 0 {
+		claim.Memo = req.Data.Memo
+	}
+	claim, err = s.claimRepo.Update(ctx, claim)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[ClaimDTO]{
+		Data: toClaimDTO(claim),
+	}, nil
+}
+
+func (s *service) GetOutstanding(ctx context.Context, tenantId string, userId string) (*response.DataResponse[OutstandingReportDTO], error) {
+	claims, err := s.findClaims(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].ExpenseDate < claims[j].ExpenseDate
+	})
+
+	report := OutstandingReportDTO{
+		Payers: []PayerReceivableDTO{},
+		Claims: []ClaimDTO{},
+	}
+	index := map[string]int{}
+	for i := range claims {
+		claim := &claims[i]
+		if finished(claim) || (len(userId) > 0 && claim.UserId != userId) {
+			continue
+		}
+		outstanding := claim.Amount - claim.ReimbursedAmount
+		if outstanding <= 0 {
+			continue
+		}
+		report.Claims = append(report.Claims, toClaimDTO(claim))
+
+		key := claim.PayerType + "|" + claim.PayerName + "|" + claim.Currency
+		pos, ok := index[key]
+		if !ok {
+			pos = len(report.Payers)
+			index[key] = pos
+			report.Payers = append(report.Payers, PayerReceivableDTO{
+				PayerType:         claim.PayerType,
+				PayerName:         claim.PayerName,
+				Currency:          claim.Currency,
+				OldestExpenseDate: claim.ExpenseDate,
+			})
+		}
+		payer := &report.Payers[pos]
+		payer.ClaimCount++
+		payer.Outstanding += outstanding
+		if claim.Status == StatusToSubmit {
+			payer.ToSubmit += outstanding
+		} else {
+			payer.Submitted += outstanding
+		}
+	}
+	sort.SliceStable(report.Payers, func(i, j int) bool {
+		return report.Payers[i].Outstanding > report.Payers[j].Outstanding
+	})
+
+	return &response.DataResponse[OutstandingReportDTO]{
+		Data: report,
+	}, nil
+}
+
+func (s *service) Receivables(ctx context.Context, tenantId string, asOf string) ([]Receivable, error) {
+	claims, err := s.findClaims(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	received := map[string]int64{}
+	if len(asOf) > 0 {
+		receipts, err := s.findReceipts(ctx, "tenant_id", tenantId)
+		if err != nil {
+			return nil, err
+		}
+		for _, receipt := range receipts {
+			if receipt.Date <= asOf {
+				received[receipt.ClaimId] += receipt.Amount
+			}
+		}
+	}
+
+	receivables := []Receivable{}
+	for i := range claims {
+		claim := &claims[i]
+		outstanding := claim.Amount - claim.ReimbursedAmount
+		if len(asOf) > 0 {
+			if claim.ExpenseDate > asOf || (len(claim.ClosedDate) > 0 && claim.ClosedDate <= asOf) {
+				continue
+			}
+			outstanding = claim.Amount - received[claim.Id]
+		} else if claim.Status == StatusClosed {
+			continue
+		}
+		if outstanding <= 0 {
+			continue
+		}
+		receivables = append(receivables, Receivable{
+			ClaimId:     claim.Id,
+			Title:       claim.Title,
+			UserId:      claim.UserId,
+			Currency:    claim.Currency,
+			Outstanding: outstanding,
+		})
+	}
+	return receivables, nil
+}
+
+func receivedAmount(receipts []Receipt) int64 {
+	var total int64
+	for _, receipt := range receipts {
+		total += receipt.Amount
+	}
+	return total
+}
+
+// statusOf 按提交和到账情况推算状态，已结束的报销单保持不变
+func statusOf(claim *Claim) string {
+	switch {
+	case claim.Status == StatusClosed:
+		return StatusClosed
+	case claim.ReimbursedAmount >= claim.Amount:
+		return StatusReimbursed
+	case claim.ReimbursedAmount > 0:
+		return StatusPartiallyReimbursed
+	case len(claim.SubmittedDate) > 0:
+		return StatusSubmitted
+	default:
+		return StatusToSubmit
+	}
+}
+
+func finished(claim *Claim) bool {
+	return claim.Status == StatusReimbursed || claim.Status == StatusClosed
+}
+
+func toClaimDTO(claim *Claim) ClaimDTO {
+	outstanding := claim.Amount - claim.ReimbursedAmount
+	if claim.Status == StatusClosed {
+		outstanding = 0
+	}
+	return ClaimDTO{
+		Id:               claim.Id,
+		TenantId:         claim.TenantId,
+		UserId:           claim.UserId,
+		PayerType:        claim.PayerType,
+		PayerName:        claim.PayerName,
+		Title:            claim.Title,
+		Category:         claim.Category,
+		ExpenseDate:      claim.ExpenseDate,
+		Amount:           claim.Amount,
+		Currency:         claim.Currency,
+		Status:           claim.Status,
+		SourceType:       claim.SourceType,
+		SourceId:         claim.SourceId,
+		SubmittedDate:    claim.SubmittedDate,
+		ReimbursedAmount: claim.ReimbursedAmount,
+		Outstanding:      outstanding,
+		ClosedDate:       claim.ClosedDate,
+		Memo:             claim.Memo,
+	}
+}
+
+func dateOrToday(date string) (string, error) {
+	if len(date) == 0 {
+		return time.Now().Format(DateLayout), nil
+	}
+	if !validDate(date) {
+		return "", ErrDateInvalid
+	}
+	return date, nil
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
+
+func (s *service) findClaimById(ctx context.Context, tenantId string, id string) (*Claim, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	claims, err := s.claimRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims) == 0 {
+		return nil, ErrClaimNotFound
+	}
+	return &claims[0], nil
+}
+
+func (s *service) findClaims(ctx context.Context, tenantId string) ([]Claim, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.claimRepo.Query(ctx, query)
+}
+
+func (s *service) findReceipts(ctx context.Context, field string, value string) ([]Receipt, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter(field, []interface{}{value}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.receiptRepo.Query(ctx, query)
+}
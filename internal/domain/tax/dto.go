@@ -0,0 +1,95 @@
+package tax
+
+type DeductionRecordDTO struct {
+	Id           string `json:"id"`
+	TenantId     string `json:"tenant_id"`
+	UserId       string `json:"user_id"`
+	Year         int    `json:"year"`
+	Category     string `json:"category"`
+	Variant      string `json:"variant"`
+	Dependent    string `json:"dependent"`
+	StartMonth   int    `json:"start_month"`
+	EndMonth     int    `json:"end_month"`
+	SharePercent int    `json:"share_percent"`
+	Amount       int64  `json:"amount"`
+	SourceType   string `json:"source_type"`
+	SourceId     string `json:"source_id"`
+	Memo         string `json:"memo"`
+}
+
+type CreateDeductionRecordDTO struct {
+	TenantId  string `json:"tenant_id" binding:"required"`
+	UserId    string `json:"user_id" binding:"required"`
+	Year      int    `json:"year" binding:"required,min=2019,max=2100"`
+	Category  string `json:"category" binding:"required,oneof=children_education continuing_education serious_illness housing_loan_interest housing_rent elderly_support infant_care"`
+	Variant   string `json:"variant" binding:"omitempty,max_len=20"`
+	Dependent string `json:"dependent" binding:"omitempty,max_len=100"`
+	// 为空时为全年
+	StartMonth int `json:"start_month" binding:"omitempty,min=1,max=12"`
+	EndMonth   int `json:"end_month" binding:"omitempty,min=1,max=12"`
+	// 为空时为 100
+	SharePercent int    `json:"share_percent" binding:"omitempty,min=1,max=100"`
+	Amount       int64  `json:"amount" binding:"omitempty,min=0"`
+	SourceType   string `json:"source_type" binding:"omitempty,max_len=50"`
+	SourceId     string `json:"source_id" binding:"omitempty"`
+	Memo         string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type UpdateDeductionRecordDTO struct {
+	TenantId     string `json:"tenant_id" binding:"required"`
+	Id           string `json:"id"`
+	Dependent    string `json:"dependent" binding:"omitempty,max_len=100"`
+	StartMonth   int    `json:"start_month" binding:"omitempty,min=1,max=12"`
+	EndMonth     int    `json:"end_month" binding:"omitempty,min=1,max=12"`
+	SharePercent int    `json:"share_percent" binding:"omitempty,min=1,max=100"`
+	Amount       *int64 `json:"amount" binding:"omitempty,min=0"`
+	SourceType   string `json:"source_type" binding:"omitempty,max_len=50"`
+	SourceId     string `json:"source_id" binding:"omitempty"`
+	Memo         string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type DeductionRuleDTO struct {
+	Year            int    `json:"year"`
+	Category        string `json:"category"`
+	Variant         string `json:"variant"`
+	MonthlyAmount   int64  `json:"monthly_amount"`
+	AnnualAmount    int64  `json:"annual_amount"`
+	Threshold       int64  `json:"threshold"`
+	AnnualCap       int64  `json:"annual_cap"`
+	MaxSharePercent int    `json:"max_share_percent"`
+	Memo            string `json:"memo"`
+	// false 表示使用内置标准
+	Configured bool `json:"configured"`
+}
+
+type SaveDeductionRuleDTO struct {
+	TenantId        string `json:"tenant_id" binding:"required"`
+	Year            int    `json:"year" binding:"required,min=2019,max=2100"`
+	Category        string `json:"category" binding:"required,oneof=children_education continuing_education serious_illness housing_loan_interest housing_rent elderly_support infant_care"`
+	Variant         string `json:"variant" binding:"omitempty,max_len=20"`
+	MonthlyAmount   int64  `json:"monthly_amount" binding:"omitempty,min=0"`
+	AnnualAmount    int64  `json:"annual_amount" binding:"omitempty,min=0"`
+	Threshold       int64  `json:"threshold" binding:"omitempty,min=0"`
+	AnnualCap       int64  `json:"annual_cap" binding:"omitempty,min=0"`
+	MaxSharePercent int    `json:"max_share_percent" binding:"omitempty,min=1,max=100"`
+	Memo            string `json:"memo" binding:"omitempty,max_len=500"`
+}
+
+type DeductionItemDTO struct {
+	Category  string `json:"category"`
+	Variant   string `json:"variant"`
+	Dependent string `json:"dependent"`
+	// 可扣除的月数，按年定额或按实际发生额扣除的项目为 0
+	Months int `json:"months"`
+	// 实际发生金额合计
+	Expense   int64 `json:"expense"`
+	Deduction int64 `json:"deduction"`
+}
+
+type MemberSummaryDTO struct {
+	UserId   string             `json:"user_id"`
+	Year     int                `json:"year"`
+	Items    []DeductionItemDTO `json:"items"`
+	Total    int64              `json:"total"`
+	Warnings []string           `json:"warnings"`
+}
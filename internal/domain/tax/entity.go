@@ -0,0 +1,75 @@
+package tax
+
+import (
+	"github.com/loongkirin/gdk/database/model"
+)
+
+// 个人所得税专项附加扣除类别
+const (
+	CategoryChildrenEducation   = "children_education"
+	CategoryContinuingEducation = "continuing_education"
+	CategorySeriousIllness      = "serious_illness"
+	CategoryHousingLoanInterest = "housing_loan_interest"
+	CategoryHousingRent         = "housing_rent"
+	CategoryElderlySupport      = "elderly_support"
+	CategoryInfantCare          = "infant_care"
+
+	// 继续教育：学历（学位）教育按月扣除，职业资格证书按年定额扣除
+	VariantDegree      = "degree"
+	VariantCertificate = "certificate"
+	// 住房租金：直辖市、省会等城市 / 市辖区户籍人口超过 100 万的城市 / 其他城市
+	VariantTier1 = "tier1"
+	VariantTier2 = "tier2"
+	VariantTier3 = "tier3"
+	// 赡养老人：独生子女 / 非独生子女分摊
+	VariantOnlyChild = "only_child"
+	VariantShared    = "shared"
+)
+
+// DeductionRecord 成员一年内某项专项附加扣除的申报信息
+type DeductionRecord struct {
+	model.TenantBaseModel
+	// 申报扣除的成员
+	UserId   string `json:"user_id" gorm:"size:32;not null;index"`
+	Year     int    `json:"year" gorm:"not null;index"`
+	Category string `json:"category" gorm:"size:30;not null"`
+	Variant  string `json:"variant" gorm:"size:20"`
+	// 子女、老人或患者等相关人员；学历继续教育填写所读学历（学位），按学历分别计算 48 个月期限
+	Dependent  string `json:"dependent" gorm:"size:100"`
+	StartMonth int    `json:"start_month"`
+	EndMonth   int    `json:"end_month"`
+	// 本人扣除比例（百分比），如子女教育父母各扣 50%
+	SharePercent int `json:"share_percent"`
+	// 按实际发生额计算的项目填写，如大病医疗自付金额
+	Amount int64 `json:"amount"`
+	// 相关记录，如 loan、reimbursement 及其 ID
+	SourceType string `json:"source_type" gorm:"size:50"`
+	SourceId   string `json:"source_id" gorm:"size:32"`
+	Memo       string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *DeductionRecord) TableName() string {
+	return "finance_tax_deduction_record"
+}
+
+// DeductionRule 家庭自定义的某年扣除标准，未配置时使用内置标准
+type DeductionRule struct {
+	model.TenantBaseModel
+	Year     int    `json:"year" gorm:"not null;index"`
+	Category string `json:"category" gorm:"size:30;not null"`
+	Variant  string `json:"variant" gorm:"size:20"`
+	// 每月定额
+	MonthlyAmount int64 `json:"monthly_amount"`
+	// 每年定额
+	AnnualAmount int64 `json:"annual_amount"`
+	// 按实际发生额扣除时的起付线和年度上限
+	Threshold int64 `json:"threshold"`
+	AnnualCap int64 `json:"annual_cap"`
+	// 单人最高扣除比例（百分比）
+	MaxSharePercent int    `json:"max_share_percent"`
+	Memo            string `json:"memo" gorm:"size:500"`
+}
+
+func (entity *DeductionRule) TableName() string {
+	return "finance_tax_deduction_rule"
+}
@@ -0,0 +1,14 @@
+package tax
+
+import "errors"
+
+var (
+	ErrRecordNotFound      = errors.New("扣除记录不存在")
+	ErrVariantInvalid      = errors.New("扣除类别与细分类型不匹配")
+	ErrCategoryNotInEffect = errors.New("该年度尚未设立此扣除项目")
+	ErrMonthRangeInvalid   = errors.New("扣除月份范围无效")
+	ErrAmountRequired      = errors.New("请填写实际发生金额")
+	ErrDependentRequired   = errors.New("请填写子女姓名")
+	ErrShareTooLarge       = errors.New("扣除比例超过该项目允许的上限")
+	ErrSourceIncomplete    = errors.New("相关记录类型和ID需同时填写")
+)
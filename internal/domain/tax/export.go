@@ -0,0 +1,47 @@
+package tax
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+)
+
+// renderSummaryCSV 导出年度扣除汇总，带 BOM 以便 Excel 正确识别中文
+func renderSummaryCSV(summaries []MemberSummaryDTO, memberNames map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf")
+	writer := csv.NewWriter(&buf)
+	rows := [][]string{{"年度", "成员", "扣除项目", "细分类型", "相关人员", "扣除月数", "实际发生金额", "可扣除金额"}}
+	for _, summary := range summaries {
+		member := memberNames[summary.UserId]
+		if len(member) == 0 {
+			member = summary.UserId
+		}
+		year := strconv.Itoa(summary.Year)
+		for _, item := range summary.Items {
+			rows = append(rows, []string{
+				year,
+				member,
+				categoryNames[item.Category],
+				variantNames[item.Variant],
+				item.Dependent,
+				strconv.Itoa(item.Months),
+				formatAmount(item.Expense),
+				formatAmount(item.Deduction),
+			})
+		}
+		rows = append(rows, []string{year, member, "合计", "", "", "", "", formatAmount(summary.Total)})
+		for _, warning := range summary.Warnings {
+			rows = append(rows, []string{year, member, "提示", "", "", "", "", warning})
+		}
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%.2f", float64(amount)/100)
+}
@@ -0,0 +1,22 @@
+package tax
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate 执行数据库迁移
+func Migrate(db *gorm.DB) {
+	// 创建专项附加扣除记录表
+	if err := db.AutoMigrate(&DeductionRecord{}); err != nil {
+		fmt.Println("创建专项附加扣除记录表失败", err)
+	}
+
+	// 创建专项附加扣除标准表
+	if err := db.AutoMigrate(&DeductionRule{}); err != nil {
+		fmt.Println("创建专项附加扣除标准表失败", err)
+	}
+
+	fmt.Println("Tax模块迁移完成")
+}
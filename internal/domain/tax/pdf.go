@@ -0,0 +1,130 @@
+package tax
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+)
+
+// A4 纵向页面，单位为点
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 40
+	pdfFontSize   = 9
+	pdfLineHeight = 16
+)
+
+// 各列左侧横坐标，与 CSV 导出列一致
+var pdfColumns = []int{40, 75, 135, 235, 320, 400, 445, 510}
+
+// renderSummaryPDF 导出年度扣除汇总 PDF。使用阅读器内置的 STSong-Light 中文字体，不嵌入字体文件
+func renderSummaryPDF(summaries []MemberSummaryDTO, memberNames map[string]string) ([]byte, error) {
+	header := []string{"年度", "成员", "扣除项目", "细分类型", "相关人员", "月数", "发生金额", "可扣除金额"}
+	rows := [][]string{}
+	for _, summary := range summaries {
+		member := memberNames[summary.UserId]
+		if len(member) == 0 {
+			member = summary.UserId
+		}
+		year := strconv.Itoa(summary.Year)
+		for _, item := range summary.Items {
+			rows = append(rows, []string{
+				year,
+				member,
+				categoryNames[item.Category],
+				variantNames[item.Variant],
+				item.Dependent,
+				strconv.Itoa(item.Months),
+				formatAmount(item.Expense),
+				formatAmount(item.Deduction),
+			})
+		}
+		rows = append(rows, []string{year, member, "合计", "", "", "", "", formatAmount(summary.Total)})
+		for _, warning := range summary.Warnings {
+			rows = append(rows, []string{year, member, "提示：" + warning})
+		}
+	}
+
+	perPage := (pdfPageHeight-2*pdfMargin)/pdfLineHeight - 2
+	pages := [][][]string{}
+	for start := 0; start < len(rows) || len(pages) == 0; start += perPage {
+		pages = append(pages, rows[start:min(start+perPage, len(rows))])
+	}
+
+	contents := make([][]byte, 0, len(pages))
+	for i, page := range pages {
+		var stream bytes.Buffer
+		stream.WriteString("BT\n")
+		y := pdfPageHeight - pdfMargin
+		writePDFText(&stream, pdfMargin, y, 14, "个人所得税专项附加扣除汇总")
+		y -= 2 * pdfLineHeight
+		writePDFRow(&stream, y, header)
+		for _, row := range page {
+			y -= pdfLineHeight
+			writePDFRow(&stream, y, row)
+		}
+		writePDFText(&stream, pdfPageWidth-pdfMargin-40, pdfMargin/2, pdfFontSize, fmt.Sprintf("%d / %d", i+1, len(pages)))
+		stream.WriteString("ET\n")
+		contents = append(contents, stream.Bytes())
+	}
+
+	// 对象编号：1 目录，2 页面树，3-5 字体，之后每页依次为页面和内容流
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor 5 0 R /DW 1000 " +
+			"/W [1 95 500] >>",
+		"<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+			"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+	}
+	kids := ""
+	for i, content := range contents {
+		pageId := len(objects) + 1
+		kids += fmt.Sprintf("%d 0 R ", pageId)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, pageId+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), contents[i]),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(contents))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes(), nil
+}
+
+func writePDFRow(stream *bytes.Buffer, y int, row []string) {
+	for i, cell := range row {
+		if i < len(pdfColumns) && len(cell) > 0 {
+			writePDFText(stream, pdfColumns[i], y, pdfFontSize, cell)
+		}
+	}
+}
+
+// writePDFText 按 UniGB-UCS2-H 编码输出文字，基本平面以外的字符以问号代替
+func writePDFText(stream *bytes.Buffer, x int, y int, size int, text string) {
+	fmt.Fprintf(stream, "/F1 %d Tf 1 0 0 1 %d %d Tm <", size, x, y)
+	for _, r := range text {
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(stream, "%04X", r)
+	}
+	stream.WriteString("> Tj\n")
+}
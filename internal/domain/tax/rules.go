@@ -0,0 +1,355 @@
+package tax
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 学历（学位）继续教育同一学历的扣除期限最长 48 个月
+const degreeMaxMonths = 48
+
+// 2019 年起执行的内置扣除标准，金额单位为分
+var rules2019 = []DeductionRule{
+	{Category: CategoryChildrenEducation, MonthlyAmount: 100000, MaxSharePercent: 100},
+	{Category: CategoryContinuingEducation, Variant: VariantDegree, MonthlyAmount: 40000, MaxSharePercent: 100},
+	{Category: CategoryContinuingEducation, Variant: VariantCertificate, AnnualAmount: 360000, MaxSharePercent: 100},
+	{Category: CategorySeriousIllness, Threshold: 1500000, AnnualCap: 8000000, MaxSharePercent: 100},
+	{Category: CategoryHousingLoanInterest, MonthlyAmount: 100000, MaxSharePercent: 100},
+	{Category: CategoryHousingRent, Variant: VariantTier1, MonthlyAmount: 150000, MaxSharePercent: 100},
+	{Category: CategoryHousingRent, Variant: VariantTier2, MonthlyAmount: 110000, MaxSharePercent: 100},
+	{Category: CategoryHousingRent, Variant: VariantTier3, MonthlyAmount: 80000, MaxSharePercent: 100},
+	{Category: CategoryElderlySupport, Variant: VariantOnlyChild, MonthlyAmount: 200000, MaxSharePercent: 100},
+	{Category: CategoryElderlySupport, Variant: VariantShared, MonthlyAmount: 200000, MaxSharePercent: 50},
+}
+
+// 2022 年新增 3 岁以下婴幼儿照护
+var rules2022 = append(append([]DeductionRule(nil), rules2019...),
+	DeductionRule{Category: CategoryInfantCare, MonthlyAmount: 100000, MaxSharePercent: 100},
+)
+
+// 2023 年起提高子女教育、赡养老人和婴幼儿照护标准
+var rules2023 = []DeductionRule{
+	{Category: CategoryChildrenEducation, MonthlyAmount: 200000, MaxSharePercent: 100},
+	{Category: CategoryContinuingEducation, Variant: VariantDegree, MonthlyAmount: 40000, MaxSharePercent: 100},
+	{Category: CategoryContinuingEducation, Variant: VariantCertificate, AnnualAmount: 360000, MaxSharePercent: 100},
+	{Category: CategorySeriousIllness, Threshold: 1500000, AnnualCap: 8000000, MaxSharePercent: 100},
+	{Category: CategoryHousingLoanInterest, MonthlyAmount: 100000, MaxSharePercent: 100},
+	{Category: CategoryHousingRent, Variant: VariantTier1, MonthlyAmount: 150000, MaxSharePercent: 100},
+	{Category: CategoryHousingRent, Variant: VariantTier2, MonthlyAmount: 110000, MaxSharePercent: 100},
+	{Category: CategoryHousingRent, Variant: VariantTier3, MonthlyAmount: 80000, MaxSharePercent: 100},
+	{Category: CategoryElderlySupport, Variant: VariantOnlyChild, MonthlyAmount: 300000, MaxSharePercent: 100},
+	{Category: CategoryElderlySupport, Variant: VariantShared, MonthlyAmount: 300000, MaxSharePercent: 50},
+	{Category: CategoryInfantCare, MonthlyAmount: 200000, MaxSharePercent: 100},
+}
+
+// defaultRules 返回某年的内置扣除标准，家庭配置可覆盖
+func defaultRules(year int) []DeductionRule {
+	switch {
+	case year >= 2023:
+		return rules2023
+	case year == 2022:
+		return rules2022
+	default:
+		return rules2019
+	}
+}
+
+var categoryNames = map[string]string{
+	CategoryChildrenEducation:   "子女教育",
+	CategoryContinuingEducation: "继续教育",
+	CategorySeriousIllness:      "大病医疗",
+	CategoryHousingLoanInterest: "住房贷款利息",
+	CategoryHousingRent:         "住房租金",
+	CategoryElderlySupport:      "赡养老人",
+	CategoryInfantCare:          "3岁以下婴幼儿照护",
+}
+
+var variantNames = map[string]string{
+	VariantDegree:      "学历（学位）教育",
+	VariantCertificate: "职业资格证书",
+	VariantTier1:       "一类城市",
+	VariantTier2:       "二类城市",
+	VariantTier3:       "三类城市",
+	VariantOnlyChild:   "独生子女",
+	VariantShared:      "非独生子女",
+}
+
+func ruleKey(category string, variant string) string {
+	return category + "|" + variant
+}
+
+// perDependent 按子女或患者分别计算的项目
+func perDependent(category string) bool {
+	return category == CategoryChildrenEducation || category == CategoryInfantCare || category == CategorySeriousIllness
+}
+
+// effectiveRules 返回某年生效的扣除标准，家庭配置优先于内置标准
+func effectiveRules(year int, configured []DeductionRule) []DeductionRuleDTO {
+	index := map[string]*DeductionRule{}
+	for i := range configured {
+		if configured[i].Year == year {
+			index[ruleKey(configured[i].Category, configured[i].Variant)] = &configured[i]
+		}
+	}
+
+	defaults := defaultRules(year)
+	rules := make([]DeductionRuleDTO, 0, len(defaults))
+	for i := range defaults {
+		rule, ok := index[ruleKey(defaults[i].Category, defaults[i].Variant)]
+		if !ok {
+			rule = &defaults[i]
+		}
+		rules = append(rules, DeductionRuleDTO{
+			Year:            year,
+			Category:        rule.Category,
+			Variant:         rule.Variant,
+			MonthlyAmount:   rule.MonthlyAmount,
+			AnnualAmount:    rule.AnnualAmount,
+			Threshold:       rule.Threshold,
+			AnnualCap:       rule.AnnualCap,
+			MaxSharePercent: rule.MaxSharePercent,
+			Memo:            rule.Memo,
+			Configured:      ok,
+		})
+	}
+	return rules
+}
+
+// checkVariant 校验类别和细分类型在该年度是否有效
+func checkVariant(year int, category string, variant string) error {
+	for _, rule := range defaultRules(year) {
+		if rule.Category == category && rule.Variant == variant {
+			return nil
+		}
+	}
+	for _, rule := range rules2023 {
+		if rule.Category == category && rule.Variant == variant {
+			return ErrCategoryNotInEffect
+		}
+	}
+	return ErrVariantInvalid
+}
+
+type deductionGroup struct {
+	item   DeductionItemDTO
+	rule   DeductionRuleDTO
+	shares [13]int
+	// 学历继续教育按所读学历（学位）分别记录申报月份
+	programs map[string]*[13]bool
+}
+
+// computeSummary 计算成员某年各项可扣除金额。按月扣除的项目同一月份只计一次，取最高比例；
+// degreeMonthsUsed 为以前年度各学历（学位）已扣除的学历继续教育月数
+func computeSummary(userId string, year int, records []DeductionRecord, rules []DeductionRuleDTO, degreeMonthsUsed map[string]int) MemberSummaryDTO {
+	ruleIndex := map[string]DeductionRuleDTO{}
+	for _, rule := range rules {
+		ruleIndex[ruleKey(rule.Category, rule.Variant)] = rule
+	}
+
+	groups := []*deductionGroup{}
+	groupIndex := map[string]*deductionGroup{}
+	for _, record := range records {
+		rule, ok := ruleIndex[ruleKey(record.Category, record.Variant)]
+		if !ok {
+			continue
+		}
+		key := ruleKey(record.Category, record.Variant)
+		if perDependent(record.Category) {
+			key += "|" + record.Dependent
+		}
+		group, ok := groupIndex[key]
+		if !ok {
+			group = &deductionGroup{
+				item: DeductionItemDTO{
+					Category: record.Category,
+					Variant:  record.Variant,
+				},
+				rule: rule,
+			}
+			if perDependent(record.Category) {
+				group.item.Dependent = record.Dependent
+			}
+			groupIndex[key] = group
+			groups = append(groups, group)
+		}
+		group.item.Expense += record.Amount
+		share := min(record.SharePercent, rule.MaxSharePercent)
+		if isDegree(record) && share > 0 {
+			if group.programs == nil {
+				group.programs = map[string]*[13]bool{}
+			}
+			if group.programs[record.Dependent] == nil {
+				group.programs[record.Dependent] = &[13]bool{}
+			}
+		}
+		for month := max(record.StartMonth, 1); month <= min(record.EndMonth, 12); month++ {
+			group.shares[month] = max(group.shares[month], share)
+			if program := group.programs[record.Dependent]; isDegree(record) && program != nil {
+				program[month] = true
+			}
+		}
+	}
+
+	summary := MemberSummaryDTO{
+		UserId:   userId,
+		Year:     year,
+		Items:    make([]DeductionItemDTO, 0, len(groups)),
+		Warnings: []string{},
+	}
+	for _, group := range groups {
+		rule := group.rule
+		switch {
+		case rule.MonthlyAmount > 0:
+			used := map[string]int{}
+			for program, months := range degreeMonthsUsed {
+				used[program] = months
+			}
+			for month := 1; month <= 12; month++ {
+				if group.shares[month] == 0 {
+					continue
+				}
+				if group.programs != nil && !claimDegreeMonth(group.programs, used, month) {
+					continue
+				}
+				group.item.Months++
+				group.item.Deduction += rule.MonthlyAmount * int64(group.shares[month]) / 100
+			}
+		case rule.AnnualAmount > 0:
+			group.item.Deduction = rule.AnnualAmount
+		default:
+			deduction := max(group.item.Expense-rule.Threshold, 0)
+			if rule.AnnualCap > 0 {
+				deduction = min(deduction, rule.AnnualCap)
+			}
+			group.item.Deduction = deduction
+		}
+		summary.Items = append(summary.Items, group.item)
+		summary.Total += group.item.Deduction
+		summary.Warnings = append(summary.Warnings, degreeWarnings(group.programs, degreeMonthsUsed)...)
+	}
+
+	if monthsOverlap(groups, CategoryHousingLoanInterest, "", CategoryHousingRent, "") {
+		summary.Warnings = append(summary.Warnings, "住房贷款利息和住房租金不能在同一月份同时扣除")
+	}
+	if monthsOverlap(groups, CategoryElderlySupport, VariantOnlyChild, CategoryElderlySupport, VariantShared) {
+		summary.Warnings = append(summary.Warnings, "赡养老人不能在同一月份同时按独生子女和非独生子女扣除")
+	}
+	return summary
+}
+
+func isDegree(record DeductionRecord) bool {
+	return record.Category == CategoryContinuingEducation && record.Variant == VariantDegree
+}
+
+// claimDegreeMonth 将某月计入当月在读且未满期限的学历（学位），同时在读多个学历时该月只扣除一次
+func claimDegreeMonth(programs map[string]*[13]bool, used map[string]int, month int) bool {
+	claimed := false
+	for program, months := range programs {
+		if !months[month] {
+			continue
+		}
+		if used[program] >= degreeMaxMonths {
+			continue
+		}
+		used[program]++
+		claimed = true
+	}
+	return claimed
+}
+
+// degreeWarnings 提示本年度申报月份中已超过 48 个月期限的学历（学位）
+func degreeWarnings(programs map[string]*[13]bool, degreeMonthsUsed map[string]int) []string {
+	names := []string{}
+	for program, months := range programs {
+		count := 0
+		for month := 1; month <= 12; month++ {
+			if months[month] {
+				count++
+			}
+		}
+		if degreeMonthsUsed[program]+count > degreeMaxMonths {
+			names = append(names, program)
+		}
+	}
+	sort.Strings(names)
+	warnings := make([]string, 0, len(names))
+	for _, name := range names {
+		if len(name) == 0 {
+			warnings = append(warnings, "学历（学位）继续教育扣除期限已满48个月")
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("学历（学位）继续教育（%s）扣除期限已满48个月", name))
+	}
+	return warnings
+}
+
+// monthsOverlap 判断两类按月扣除的项目是否有重叠月份，variant 为空时匹配该类别全部细分类型
+func monthsOverlap(groups []*deductionGroup, categoryA string, variantA string, categoryB string, variantB string) bool {
+	var a, b [13]bool
+	for _, group := range groups {
+		for month := 1; month <= 12; month++ {
+			if group.shares[month] == 0 {
+				continue
+			}
+			if group.item.Category == categoryA && (len(variantA) == 0 || group.item.Variant == variantA) {
+				a[month] = true
+			}
+			if group.item.Category == categoryB && (len(variantB) == 0 || group.item.Variant == variantB) {
+				b[month] = true
+			}
+		}
+	}
+	for month := 1; month <= 12; month++ {
+		if a[month] && b[month] {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedDependentWarnings 检查同一子女在家庭成员之间的扣除比例合计是否超过 100%
+func sharedDependentWarnings(records []DeductionRecord) map[string][]string {
+	type monthKey struct {
+		category  string
+		dependent string
+		month     int
+	}
+	shares := map[monthKey]map[string]int{}
+	for _, record := range records {
+		if record.Category != CategoryChildrenEducation && record.Category != CategoryInfantCare {
+			continue
+		}
+		for month := max(record.StartMonth, 1); month <= min(record.EndMonth, 12); month++ {
+			key := monthKey{record.Category, record.Dependent, month}
+			if shares[key] == nil {
+				shares[key] = map[string]int{}
+			}
+			shares[key][record.UserId] = max(shares[key][record.UserId], record.SharePercent)
+		}
+	}
+
+	warned := map[string]bool{}
+	warnings := map[string][]string{}
+	for key, byUser := range shares {
+		total := 0
+		for _, share := range byUser {
+			total += share
+		}
+		if total <= 100 {
+			continue
+		}
+		for userId := range byUser {
+			flag := userId + "|" + key.category + "|" + key.dependent
+			if warned[flag] {
+				continue
+			}
+			warned[flag] = true
+			warnings[userId] = append(warnings[userId],
+				fmt.Sprintf("%s（%s）家庭成员合计扣除比例超过100%%", categoryNames[key.category], key.dependent))
+		}
+	}
+	for userId := range warnings {
+		sort.Strings(warnings[userId])
+	}
+	return warnings
+}
@@ -0,0 +1,227 @@
+package tax
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		year     int
+		category string
+		variant  string
+		want     int64
+		wantOk   bool
+	}{
+		{"2019年子女教育", 2019, CategoryChildrenEducation, "", 100000, true},
+		{"2021年没有婴幼儿照护", 2021, CategoryInfantCare, "", 0, false},
+		{"2022年新增婴幼儿照护", 2022, CategoryInfantCare, "", 100000, true},
+		{"2023年提高子女教育标准", 2023, CategoryChildrenEducation, "", 200000, true},
+		{"2023年提高赡养老人标准", 2024, CategoryElderlySupport, VariantOnlyChild, 300000, true},
+		{"学历继续教育标准不变", 2024, CategoryContinuingEducation, VariantDegree, 40000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int64
+			ok := false
+			for _, rule := range defaultRules(tt.year) {
+				if rule.Category == tt.category && rule.Variant == tt.variant {
+					got, ok = rule.MonthlyAmount, true
+				}
+			}
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("monthly amount = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCheckVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		year     int
+		category string
+		variant  string
+		want     error
+	}{
+		{"有效的细分类型", 2023, CategoryHousingRent, VariantTier1, nil},
+		{"该年度尚未实施", 2021, CategoryInfantCare, "", ErrCategoryNotInEffect},
+		{"细分类型无效", 2023, CategoryHousingRent, VariantDegree, ErrVariantInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkVariant(tt.year, tt.category, tt.variant); err != tt.want {
+				t.Errorf("checkVariant() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveRules(t *testing.T) {
+	configured := []DeductionRule{
+		{Year: 2023, Category: CategoryChildrenEducation, MonthlyAmount: 150000, MaxSharePercent: 100},
+		{Year: 2022, Category: CategoryHousingLoanInterest, MonthlyAmount: 1, MaxSharePercent: 100},
+	}
+	for _, rule := range effectiveRules(2023, configured) {
+		switch rule.Category {
+		case CategoryChildrenEducation:
+			if rule.MonthlyAmount != 150000 || !rule.Configured {
+				t.Errorf("children education = (%d, %v), want (150000, true)", rule.MonthlyAmount, rule.Configured)
+			}
+		case CategoryHousingLoanInterest:
+			if rule.MonthlyAmount != 100000 || rule.Configured {
+				t.Errorf("housing loan interest = (%d, %v), want (100000, false)", rule.MonthlyAmount, rule.Configured)
+			}
+		}
+	}
+}
+
+func TestComputeSummary(t *testing.T) {
+	rules := effectiveRules(2023, nil)
+	tests := []struct {
+		name         string
+		records      []DeductionRecord
+		degreeUsed   map[string]int
+		wantItems    []DeductionItemDTO
+		wantTotal    int64
+		wantWarnings []string
+	}{
+		{
+			name: "同一子女重复申报的月份只计一次并取最高比例",
+			records: []DeductionRecord{
+				{Category: CategoryChildrenEducation, Dependent: "小明", StartMonth: 1, EndMonth: 6, SharePercent: 50},
+				{Category: CategoryChildrenEducation, Dependent: "小明", StartMonth: 4, EndMonth: 12, SharePercent: 100},
+			},
+			wantItems: []DeductionItemDTO{
+				{Category: CategoryChildrenEducation, Dependent: "小明", Months: 12, Deduction: 3*100000 + 9*200000},
+			},
+			wantTotal:    2100000,
+			wantWarnings: []string{},
+		},
+		{
+			name: "大病医疗扣除起付线并受年度上限限制",
+			records: []DeductionRecord{
+				{Category: CategorySeriousIllness, Dependent: "本人", Amount: 5000000, SharePercent: 100},
+				{Category: CategorySeriousIllness, Dependent: "本人", Amount: 6000000, SharePercent: 100},
+			},
+			wantItems: []DeductionItemDTO{
+				{Category: CategorySeriousIllness, Dependent: "本人", Expense: 11000000, Deduction: 8000000},
+			},
+			wantTotal:    8000000,
+			wantWarnings: []string{},
+		},
+		{
+			name: "职业资格证书按年定额",
+			records: []DeductionRecord{
+				{Category: CategoryContinuingEducation, Variant: VariantCertificate, StartMonth: 5, EndMonth: 5, SharePercent: 100},
+			},
+			wantItems: []DeductionItemDTO{
+				{Category: CategoryContinuingEducation, Variant: VariantCertificate, Deduction: 360000},
+			},
+			wantTotal:    360000,
+			wantWarnings: []string{},
+		},
+		{
+			name: "住房贷款利息和租金同月申报时提示",
+			records: []DeductionRecord{
+				{Category: CategoryHousingLoanInterest, StartMonth: 1, EndMonth: 6, SharePercent: 100},
+				{Category: CategoryHousingRent, Variant: VariantTier2, StartMonth: 6, EndMonth: 12, SharePercent: 100},
+			},
+			wantItems: []DeductionItemDTO{
+				{Category: CategoryHousingLoanInterest, Months: 6, Deduction: 600000},
+				{Category: CategoryHousingRent, Variant: VariantTier2, Months: 7, Deduction: 770000},
+			},
+			wantTotal:    1370000,
+			wantWarnings: []string{"住房贷款利息和住房租金不能在同一月份同时扣除"},
+		},
+		{
+			name: "学历继续教育剩余期限不足一年时只扣除剩余月数",
+			records: []DeductionRecord{
+				{Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "本科", StartMonth: 1, EndMonth: 12, SharePercent: 100},
+			},
+			degreeUsed: map[string]int{"本科": 44},
+			wantItems: []DeductionItemDTO{
+				{Category: CategoryContinuingEducation, Variant: VariantDegree, Months: 4, Deduction: 160000},
+			},
+			wantTotal:    160000,
+			wantWarnings: []string{"学历（学位）继续教育（本科）扣除期限已满48个月"},
+		},
+		{
+			name: "新的学历不受以前学历已扣除月数影响",
+			records: []DeductionRecord{
+				{Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "硕士", StartMonth: 9, EndMonth: 12, SharePercent: 100},
+			},
+			degreeUsed: map[string]int{"本科": 48},
+			wantItems: []DeductionItemDTO{
+				{Category: CategoryContinuingEducation, Variant: VariantDegree, Months: 4, Deduction: 160000},
+			},
+			wantTotal:    160000,
+			wantWarnings: []string{},
+		},
+		{
+			name: "前一学历期满后接读的学历继续扣除且同月只扣一次",
+			records: []DeductionRecord{
+				{Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "本科", StartMonth: 1, EndMonth: 8, SharePercent: 100},
+				{Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "硕士", StartMonth: 7, EndMonth: 12, SharePercent: 100},
+			},
+			degreeUsed: map[string]int{"本科": 45},
+			wantItems: []DeductionItemDTO{
+				{Category: CategoryContinuingEducation, Variant: VariantDegree, Months: 9, Deduction: 360000},
+			},
+			wantTotal:    360000,
+			wantWarnings: []string{"学历（学位）继续教育（本科）扣除期限已满48个月"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := computeSummary("u", 2023, tt.records, rules, tt.degreeUsed)
+			if !reflect.DeepEqual(summary.Items, tt.wantItems) {
+				t.Errorf("items = %+v, want %+v", summary.Items, tt.wantItems)
+			}
+			if summary.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", summary.Total, tt.wantTotal)
+			}
+			if !reflect.DeepEqual(summary.Warnings, tt.wantWarnings) {
+				t.Errorf("warnings = %v, want %v", summary.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestDegreeMonthsBefore(t *testing.T) {
+	records := []DeductionRecord{
+		{UserId: "u", Year: 2020, Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "本科", StartMonth: 9, EndMonth: 12, SharePercent: 100},
+		{UserId: "u", Year: 2020, Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "本科", StartMonth: 11, EndMonth: 12, SharePercent: 100},
+		{UserId: "u", Year: 2021, Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "本科", StartMonth: 1, EndMonth: 12, SharePercent: 100},
+		{UserId: "u", Year: 2022, Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "硕士", StartMonth: 9, EndMonth: 12, SharePercent: 100},
+		{UserId: "u", Year: 2023, Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "硕士", StartMonth: 1, EndMonth: 12, SharePercent: 100},
+		{UserId: "v", Year: 2021, Category: CategoryContinuingEducation, Variant: VariantDegree, Dependent: "本科", StartMonth: 1, EndMonth: 12, SharePercent: 100},
+		{UserId: "u", Year: 2021, Category: CategoryContinuingEducation, Variant: VariantCertificate, StartMonth: 1, EndMonth: 12, SharePercent: 100},
+	}
+	want := map[string]int{"本科": 16, "硕士": 4}
+	if got := degreeMonthsBefore(records, "u", 2023); !reflect.DeepEqual(got, want) {
+		t.Errorf("degreeMonthsBefore() = %v, want %v", got, want)
+	}
+}
+
+func TestRenderSummaryPDF(t *testing.T) {
+	summaries := []MemberSummaryDTO{{
+		UserId: "u",
+		Year:   2023,
+		Items:  []DeductionItemDTO{{Category: CategoryChildrenEducation, Dependent: "小明", Months: 12, Deduction: 2400000}},
+		Total:  2400000,
+	}}
+	data, err := renderSummaryPDF(summaries, map[string]string{"u": "张三"})
+	if err != nil {
+		t.Fatalf("renderSummaryPDF() error = %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Errorf("pdf header or trailer missing")
+	}
+	// 张三 的 UCS-2 编码
+	if !bytes.Contains(data, []byte("<5F204E09>")) {
+		t.Errorf("member name not rendered")
+	}
+}
@@ -0,0 +1,454 @@
+package tax
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loongkirin/gdk/database/model"
+	"github.com/loongkirin/gdk/database/query"
+	"github.com/loongkirin/gdk/database/repository"
+	"github.com/loongkirin/gdk/net/http/request"
+	"github.com/loongkirin/gdk/net/http/response"
+	"github.com/loongkirin/gdk/util"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+)
+
+type TaxService interface {
+	CreateRecord(ctx context.Context, req *request.DataRequest[CreateDeductionRecordDTO]) (*response.DataResponse[DeductionRecordDTO], error)
+	UpdateRecord(ctx context.Context, req *request.DataRequest[UpdateDeductionRecordDTO]) (*response.DataResponse[DeductionRecordDTO], error)
+	// ListRecords 返回某年的扣除记录，year 为 0 时为今年，userId 为空时返回全家
+	ListRecords(ctx context.Context, tenantId string, year int, userId string) (*response.DataResponse[[]DeductionRecordDTO], error)
+	// ListRules 返回某年生效的扣除标准
+	ListRules(ctx context.Context, tenantId string, year int) (*response.DataResponse[[]DeductionRuleDTO], error)
+	SaveRule(ctx context.Context, req *request.DataRequest[SaveDeductionRuleDTO]) (*response.DataResponse[DeductionRuleDTO], error)
+	// GetSummary 按成员汇总某年可扣除金额，供年度汇算参考
+	GetSummary(ctx context.Context, tenantId string, year int, userId string) (*response.DataResponse[[]MemberSummaryDTO], error)
+	ExportSummaryCSV(ctx context.Context, tenantId string, year int, userId string) ([]byte, error)
+	ExportSummaryPDF(ctx context.Context, tenantId string, year int, userId string) ([]byte, error)
+}
+
+type service struct {
+	recordRepo repository.Repository[DeductionRecord]
+	ruleRepo   repository.Repository[DeductionRule]
+	members    auth.MemberDirectory
+}
+
+func NewTaxService(
+	recordRepo repository.Repository[DeductionRecord],
+	ruleRepo repository.Repository[DeductionRule],
+	members auth.MemberDirectory,
+) TaxService {
+	return &service{
+		recordRepo: recordRepo,
+		ruleRepo:   ruleRepo,
+		members:    members,
+	}
+}
+
+func (s *service) CreateRecord(ctx context.Context, req *request.DataRequest[CreateDeductionRecordDTO]) (*response.DataResponse[DeductionRecordDTO], error) {
+	record := &DeductionRecord{
+		UserId:          req.Data.UserId,
+		Year:            req.Data.Year,
+		Category:        req.Data.Category,
+		Variant:         req.Data.Variant,
+		Dependent:       strings.TrimSpace(req.Data.Dependent),
+		StartMonth:      req.Data.StartMonth,
+		EndMonth:        req.Data.EndMonth,
+		SharePercent:    req.Data.SharePercent,
+		Amount:          req.Data.Amount,
+		SourceType:      req.Data.SourceType,
+		SourceId:        req.Data.SourceId,
+		Memo:            req.Data.Memo,
+		TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+	}
+	if record.StartMonth == 0 {
+		record.StartMonth = 1
+	}
+	if record.EndMonth == 0 {
+		record.EndMonth = 12
+	}
+	if record.SharePercent == 0 {
+		record.SharePercent = 100
+	}
+	if err := s.validateRecord(ctx, record); err != nil {
+		return nil, err
+	}
+
+	record, err := s.recordRepo.Add(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[DeductionRecordDTO]{
+		Data: toRecordDTO(record),
+	}, nil
+}
+
+func (s *service) UpdateRecord(ctx context.Context, req *request.DataRequest[UpdateDeductionRecordDTO]) (*response.DataResponse[DeductionRecordDTO], error) {
+	record, err := s.findRecordById(ctx, req.Data.TenantId, req.Data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if dependent := strings.TrimSpace(req.Data.Dependent); len(dependent) > 0 {
+		record.Dependent = dependent
+	}
+	if req.Data.StartMonth > 0 {
+		record.StartMonth = req.Data.StartMonth
+	}
+	if req.Data.EndMonth > 0 {
+		record.EndMonth = req.Data.EndMonth
+	}
+	if req.Data.SharePercent > 0 {
+		record.SharePercent = req.Data.SharePercent
+	}
+	if req.Data.Amount != nil {
+		record.Amount = *req.Data.Amount
+	}
+	if len(req.Data.SourceType) > 0 || len(req.Data.SourceId) > 0 {
+		record.SourceType = req.Data.SourceType
+		record.SourceId = req.Data.SourceId
+	}
+	if len(req.Data.Memo) > 0 {
+		record.Memo = req.Data.Memo
+	}
+	if err := s.validateRecord(ctx, record); err != nil {
+		return nil, err
+	}
+
+	record, err = s.recordRepo.Update(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[DeductionRecordDTO]{
+		Data: toRecordDTO(record),
+	}, nil
+}
+
+func (s *service) ListRecords(ctx context.Context, tenantId string, year int, userId string) (*response.DataResponse[[]DeductionRecordDTO], error) {
+	records, err := s.findRecords(ctx, tenantId, yearOrCurrent(year), userId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].UserId != records[j].UserId {
+			return records[i].UserId < records[j].UserId
+		}
+		if records[i].Category != records[j].Category {
+			return records[i].Category < records[j].Category
+		}
+		return records[i].StartMonth < records[j].StartMonth
+	})
+
+	dtos := make([]DeductionRecordDTO, 0, len(records))
+	for i := range records {
+		dtos = append(dtos, toRecordDTO(&records[i]))
+	}
+	return &response.DataResponse[[]DeductionRecordDTO]{
+		Data: dtos,
+	}, nil
+}
+
+func (s *service) ListRules(ctx context.Context, tenantId string, year int) (*response.DataResponse[[]DeductionRuleDTO], error) {
+	year = yearOrCurrent(year)
+	rules, err := s.loadRules(ctx, tenantId, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[[]DeductionRuleDTO]{
+		Data: rules,
+	}, nil
+}
+
+func (s *service) SaveRule(ctx context.Context, req *request.DataRequest[SaveDeductionRuleDTO]) (*response.DataResponse[DeductionRuleDTO], error) {
+	if err := checkVariant(req.Data.Year, req.Data.Category, req.Data.Variant); err != nil {
+		return nil, err
+	}
+	configured, err := s.findRules(ctx, req.Data.TenantId, req.Data.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	var rule *DeductionRule
+	for i := range configured {
+		if configured[i].Category == req.Data.Category && configured[i].Variant == req.Data.Variant {
+			rule = &configured[i]
+			break
+		}
+	}
+	created := rule == nil
+	if created {
+		rule = &DeductionRule{
+			Year:            req.Data.Year,
+			Category:        req.Data.Category,
+			Variant:         req.Data.Variant,
+			TenantBaseModel: model.NewTenantBaseModel(req.Data.TenantId, util.GenerateId()),
+		}
+	}
+	rule.MonthlyAmount = req.Data.MonthlyAmount
+	rule.AnnualAmount = req.Data.AnnualAmount
+	rule.Threshold = req.Data.Threshold
+	rule.AnnualCap = req.Data.AnnualCap
+	rule.MaxSharePercent = req.Data.MaxSharePercent
+	if rule.MaxSharePercent == 0 {
+		rule.MaxSharePercent = 100
+	}
+	rule.Memo = req.Data.Memo
+
+	if created {
+		rule, err = s.ruleRepo.Add(ctx, rule)
+	} else {
+		rule, err = s.ruleRepo.Update(ctx, rule)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[DeductionRuleDTO]{
+		Data: effectiveRule(rule.Year, rule.Category, rule.Variant, []DeductionRule{*rule}),
+	}, nil
+}
+
+func (s *service) GetSummary(ctx context.Context, tenantId string, year int, userId string) (*response.DataResponse[[]MemberSummaryDTO], error) {
+	summaries, err := s.summarize(ctx, tenantId, yearOrCurrent(year), userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataResponse[[]MemberSummaryDTO]{
+		Data: summaries,
+	}, nil
+}
+
+func (s *service) ExportSummaryCSV(ctx context.Context, tenantId string, year int, userId string) ([]byte, error) {
+	summaries, err := s.summarize(ctx, tenantId, yearOrCurrent(year), userId)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.members.MemberNames(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	return renderSummaryCSV(summaries, names)
+}
+
+func (s *service) ExportSummaryPDF(ctx context.Context, tenantId string, year int, userId string) ([]byte, error) {
+	summaries, err := s.summarize(ctx, tenantId, yearOrCurrent(year), userId)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.members.MemberNames(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	return renderSummaryPDF(summaries, names)
+}
+
+func (s *service) summarize(ctx context.Context, tenantId string, year int, userId string) ([]MemberSummaryDTO, error) {
+	rules, err := s.loadRules(ctx, tenantId, year)
+	if err != nil {
+		return nil, err
+	}
+	// 比例校验需要全家的记录，学历继续教育期限需要以前年度的记录
+	all, err := s.findTenantRecords(ctx, tenantId, "")
+	if err != nil {
+		return nil, err
+	}
+	records := recordsOfYear(all, year)
+
+	recordsByUser := map[string][]DeductionRecord{}
+	userIds := []string{}
+	for _, record := range records {
+		if _, ok := recordsByUser[record.UserId]; !ok {
+			userIds = append(userIds, record.UserId)
+		}
+		recordsByUser[record.UserId] = append(recordsByUser[record.UserId], record)
+	}
+	sort.Strings(userIds)
+	warnings := sharedDependentWarnings(records)
+
+	summaries := []MemberSummaryDTO{}
+	for _, id := range userIds {
+		if len(userId) > 0 && id != userId {
+			continue
+		}
+		summary := computeSummary(id, year, recordsByUser[id], rules, degreeMonthsBefore(all, id, year))
+		summary.Warnings = append(summary.Warnings, warnings[id]...)
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func (s *service) validateRecord(ctx context.Context, record *DeductionRecord) error {
+	if err := checkVariant(record.Year, record.Category, record.Variant); err != nil {
+		return err
+	}
+	if record.StartMonth < 1 || record.EndMonth > 12 || record.StartMonth > record.EndMonth {
+		return ErrMonthRangeInvalid
+	}
+	if (len(record.SourceType) == 0) != (len(record.SourceId) == 0) {
+		return ErrSourceIncomplete
+	}
+	if (record.Category == CategoryChildrenEducation || record.Category == CategoryInfantCare) && len(record.Dependent) == 0 {
+		return ErrDependentRequired
+	}
+
+	configured, err := s.findRules(ctx, record.TenantId, record.Year)
+	if err != nil {
+		return err
+	}
+	rule := effectiveRule(record.Year, record.Category, record.Variant, configured)
+	if rule.MonthlyAmount == 0 && rule.AnnualAmount == 0 && record.Amount <= 0 {
+		return ErrAmountRequired
+	}
+	if record.SharePercent > rule.MaxSharePercent {
+		return ErrShareTooLarge
+	}
+	return nil
+}
+
+func (s *service) loadRules(ctx context.Context, tenantId string, year int) ([]DeductionRuleDTO, error) {
+	configured, err := s.findRules(ctx, tenantId, year)
+	if err != nil {
+		return nil, err
+	}
+	return effectiveRules(year, configured), nil
+}
+
+func effectiveRule(year int, category string, variant string, configured []DeductionRule) DeductionRuleDTO {
+	for _, rule := range effectiveRules(year, configured) {
+		if rule.Category == category && rule.Variant == variant {
+			return rule
+		}
+	}
+	return DeductionRuleDTO{}
+}
+
+func toRecordDTO(record *DeductionRecord) DeductionRecordDTO {
+	return DeductionRecordDTO{
+		Id:           record.Id,
+		TenantId:     record.TenantId,
+		UserId:       record.UserId,
+		Year:         record.Year,
+		Category:     record.Category,
+		Variant:      record.Variant,
+		Dependent:    record.Dependent,
+		StartMonth:   record.StartMonth,
+		EndMonth:     record.EndMonth,
+		SharePercent: record.SharePercent,
+		Amount:       record.Amount,
+		SourceType:   record.SourceType,
+		SourceId:     record.SourceId,
+		Memo:         record.Memo,
+	}
+}
+
+// degreeMonthsBefore 按学历（学位）统计成员 year 之前各年度申报学历继续教育的月数
+func degreeMonthsBefore(records []DeductionRecord, userId string, year int) map[string]int {
+	months := map[string]map[int]bool{}
+	for _, record := range records {
+		if record.UserId != userId || record.Year >= year || !isDegree(record) || record.SharePercent <= 0 {
+			continue
+		}
+		if months[record.Dependent] == nil {
+			months[record.Dependent] = map[int]bool{}
+		}
+		for month := max(record.StartMonth, 1); month <= min(record.EndMonth, 12); month++ {
+			months[record.Dependent][record.Year*100+month] = true
+		}
+	}
+	used := make(map[string]int, len(months))
+	for program, set := range months {
+		used[program] = len(set)
+	}
+	return used
+}
+
+func yearOrCurrent(year int) int {
+	if year == 0 {
+		return time.Now().Year()
+	}
+	return year
+}
+
+func (s *service) findRecordById(ctx context.Context, tenantId string, id string) (*DeductionRecord, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{
+		query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String"),
+		query.NewDbQueryFilter("id", []interface{}{id}, query.EQ, "String"),
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1,
+		PageNumber:  1,
+	}
+	records, err := s.recordRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrRecordNotFound
+	}
+	return &records[0], nil
+}
+
+func (s *service) findRecords(ctx context.Context, tenantId string, year int, userId string) ([]DeductionRecord, error) {
+	records, err := s.findTenantRecords(ctx, tenantId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return recordsOfYear(records, year), nil
+}
+
+func recordsOfYear(records []DeductionRecord, year int) []DeductionRecord {
+	filtered := []DeductionRecord{}
+	for _, record := range records {
+		if record.Year == year {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// findTenantRecords 返回租户全部年度的扣除记录，userId 不为空时只返回该成员的记录
+func (s *service) findTenantRecords(ctx context.Context, tenantId string, userId string) ([]DeductionRecord, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	if len(userId) > 0 {
+		filters = append(filters, query.NewDbQueryFilter("user_id", []interface{}{userId}, query.EQ, "String"))
+	}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    10000,
+		PageNumber:  1,
+	}
+	return s.recordRepo.Query(ctx, query)
+}
+
+func (s *service) findRules(ctx context.Context, tenantId string, year int) ([]DeductionRule, error) {
+	wheres := []query.DbQueryWhere{}
+	filters := []query.DbQueryFilter{query.NewDbQueryFilter("tenant_id", []interface{}{tenantId}, query.EQ, "String")}
+	wheres = append(wheres, query.NewDbQueryWhere(filters, query.AND))
+	query := &query.DbQuery{
+		QueryWheres: wheres,
+		PageSize:    1000,
+		PageNumber:  1,
+	}
+	rules, err := s.ruleRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	filtered := []DeductionRule{}
+	for _, rule := range rules {
+		if rule.Year == year {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered, nil
+}
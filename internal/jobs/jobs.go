@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/loongkirin/gdk/database/gorm/repository"
+	"github.com/loongkirin/gdk/logger"
+	"github.com/loongkirin/go-family-finance/internal/app"
+	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/bill"
+	"github.com/loongkirin/go-family-finance/internal/domain/insurance"
+	"github.com/loongkirin/go-family-finance/internal/domain/networth"
+	"github.com/loongkirin/go-family-finance/internal/domain/recurring"
+	"github.com/loongkirin/go-family-finance/internal/domain/reimbursement"
+	"github.com/loongkirin/go-family-finance/internal/scheduler"
+)
+
+// RegisterJobs 注册后台定时任务
+func RegisterJobs(s *scheduler.Scheduler) {
+	registerRecurringJob(s)
+	registerBillReminderJob(s)
+	registerNetWorthSnapshotJob(s)
+	registerInsuranceJob(s)
+}
+
+func registerRecurringJob(s *scheduler.Scheduler) {
+	recurringService := recurring.NewRecurringService(
+		repository.NewRepository[recurring.RecurringTemplate](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[recurring.RecurringOccurrence](app.AppContext.APP_DbContext.GetMasterDb()),
+	)
+	s.Register(scheduler.Job{
+		Name:     "recurring_occurrences",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			created, err := recurringService.GenerateDueOccurrences(ctx, time.Now())
+			if created > 0 {
+				app.AppContext.APP_LOGGER.Info("recurring occurrences generated", logger.Fields{"created": created})
+			}
+			return err
+		},
+	})
+}
+
+func registerBillReminderJob(s *scheduler.Scheduler) {
+	billService := bill.NewBillService(
+		repository.NewRepository[bill.Bill](app.AppContext.APP_DbContext.GetMasterDb()),
+		repository.NewRepository[bill.CalendarFeed](app.AppContext.APP_DbContext.GetMasterDb()),
+		app.AppContext.APP_NOTIFIER,
+		auth.NewMemberDirectory(repository.NewRepository[auth.User](app.AppContext.APP_DbContext.GetMasterDb())),
+	)
+	s.Register(scheduler.Job{
+		Name:     "bill_reminders",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			sent, err := billService.SendReminders(ctx, time.Now())
+			if sent > 0 {
+				app.AppContext.APP_LOGGER.Info("bill reminders sent", logger.Fields{"sent": sent})
+			}
+			return err
+		},
+	})
+}
+
+func registerNetWorthSnapshotJob(s *scheduler.Scheduler) {
+	netWorthService := networth.NewDefaultNetWorthService(app.AppContext.APP_DbContext.GetMasterDb())
+	s.Register(scheduler.Job{
+		Name:     "networth_snapshots",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			taken, err := netWorthService.TakeScheduledSnapshots(ctx, time.Now())
+			if taken > 0 {
+				app.AppContext.APP_LOGGER.Info("net worth snapshots taken", logger.Fields{"taken": taken})
+			}
+			return err
+		},
+	})
+}
+
+func registerInsuranceJob(s *scheduler.Scheduler) {
+	db := app.AppContext.APP_DbContext.GetMasterDb()
+	members := auth.NewMemberDirectory(repository.NewRepository[auth.User](db))
+	insuranceService := insurance.NewInsuranceService(
+		repository.NewRepository[insurance.Policy](db),
+		repository.NewRepository[insurance.PolicyClaim](db),
+		bill.NewBillService(
+			repository.NewRepository[bill.Bill](db),
+			repository.NewRepository[bill.CalendarFeed](db),
+			app.AppContext.APP_NOTIFIER,
+			members,
+		),
+		reimbursement.NewReimbursementService(
+			repository.NewRepository[reimbursement.Claim](db),
+			repository.NewRepository[reimbursement.Receipt](db),
+		),
+		app.AppContext.APP_NOTIFIER,
+		members,
+	)
+	s.Register(scheduler.Job{
+		Name:     "insurance_premiums",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			created, err := insuranceService.GeneratePremiumBills(ctx, time.Now())
+			if created > 0 {
+				app.AppContext.APP_LOGGER.Info("insurance premium bills generated", logger.Fields{"created": created})
+			}
+			// 保费账单失败时仍发送续保提醒
+			sent, remindErr := insuranceService.SendRenewalReminders(ctx, time.Now())
+			if sent > 0 {
+				app.AppContext.APP_LOGGER.Info("insurance renewal reminders sent", logger.Fields{"sent": sent})
+			}
+			return errors.Join(err, remindErr)
+		},
+	})
+}
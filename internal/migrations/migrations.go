@@ -1,11 +1,41 @@
 package migrations
 
 import (
+	"github.com/loongkirin/go-family-finance/internal/domain/attachment"
 	"github.com/loongkirin/go-family-finance/internal/domain/auth"
+	"github.com/loongkirin/go-family-finance/internal/domain/bill"
+	"github.com/loongkirin/go-family-finance/internal/domain/currency"
+	"github.com/loongkirin/go-family-finance/internal/domain/fixedasset"
+	"github.com/loongkirin/go-family-finance/internal/domain/goal"
+	"github.com/loongkirin/go-family-finance/internal/domain/insurance"
+	"github.com/loongkirin/go-family-finance/internal/domain/investment"
+	"github.com/loongkirin/go-family-finance/internal/domain/iou"
+	"github.com/loongkirin/go-family-finance/internal/domain/loan"
+	"github.com/loongkirin/go-family-finance/internal/domain/networth"
+	"github.com/loongkirin/go-family-finance/internal/domain/notification"
+	"github.com/loongkirin/go-family-finance/internal/domain/payee"
+	"github.com/loongkirin/go-family-finance/internal/domain/recurring"
+	"github.com/loongkirin/go-family-finance/internal/domain/reimbursement"
+	"github.com/loongkirin/go-family-finance/internal/domain/tax"
 	"gorm.io/gorm"
 )
 
 // Migrate 执行数据库迁移
 func Migrate(db *gorm.DB) {
 	auth.Migrate(db)
+	payee.Migrate(db)
+	attachment.Migrate(db)
+	currency.Migrate(db)
+	notification.Migrate(db)
+	recurring.Migrate(db)
+	bill.Migrate(db)
+	goal.Migrate(db)
+	loan.Migrate(db)
+	iou.Migrate(db)
+	investment.Migrate(db)
+	fixedasset.Migrate(db)
+	reimbursement.Migrate(db)
+	insurance.Migrate(db)
+	tax.Migrate(db)
+	networth.Migrate(db)
 }
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Locker 带过期时间的互斥锁
+type Locker interface {
+	// TryLock 尝试获取锁，成功后锁在 ttl 后自动释放
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Refresh 将自己持有的锁改为 ttl 后过期，ttl 不大于 0 时立即释放
+	Refresh(ctx context.Context, key string, ttl time.Duration) error
+}
+
+type redisLocker struct {
+	client redis.Cmdable
+	owner  string
+}
+
+// NewRedisLocker 基于 Redis SETNX 的分布式锁
+func NewRedisLocker(client redis.Cmdable) Locker {
+	host, _ := os.Hostname()
+	return &redisLocker{
+		client: client,
+		owner:  host + "-" + strconv.Itoa(os.Getpid()),
+	}
+}
+
+func (l *redisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, key, l.owner, ttl).Result()
+}
+
+// refreshScript 只修改自己持有的锁，避免锁已过期被其他副本获取后被误改
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[2]) <= 0 then
+	return redis.call("DEL", KEYS[1])
+end
+return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+`)
+
+func (l *redisLocker) Refresh(ctx context.Context, key string, ttl time.Duration) error {
+	return refreshScript.Run(ctx, l.client, []string{key}, l.owner, ttl.Milliseconds()).Err()
+}
+
+type localLocker struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+// NewLocalLocker 进程内锁，仅适用于单副本部署或 Redis 不可用时
+func NewLocalLocker() Locker {
+	return &localLocker{expires: map[string]time.Time{}}
+}
+
+func (l *localLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if expire, ok := l.expires[key]; ok && now.Before(expire) {
+		return false, nil
+	}
+	l.expires[key] = now.Add(ttl)
+	return true, nil
+}
+
+func (l *localLocker) Refresh(ctx context.Context, key string, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if ttl <= 0 {
+		delete(l.expires, key)
+		return nil
+	}
+	l.expires[key] = time.Now().Add(ttl)
+	return nil
+}
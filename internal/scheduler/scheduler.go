@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/loongkirin/gdk/logger"
+)
+
+const (
+	lockKeyPrefix = "scheduler_lock_"
+	// lockMargin 锁在执行超时之外多保留的时间，覆盖任务响应取消的耗时
+	lockMargin = 30 * time.Second
+)
+
+// Job 后台定时任务，Run 需保证幂等
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler 在服务进程内周期执行后台任务。
+// 每次执行前以任务名获取分布式锁，执行期间锁不会过期；执行结束后锁保留到本周期结束前，
+// 多副本部署时同一周期只有一个副本执行
+type Scheduler struct {
+	jobs   []Job
+	locker Locker
+	logger logger.Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(locker Locker, log logger.Logger) *Scheduler {
+	return &Scheduler{
+		locker: locker,
+		logger: log,
+	}
+}
+
+// Register 注册任务，需在 Start 之前调用
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Stop 停止调度并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		s.runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	key := lockKeyPrefix + job.Name
+	locked, err := s.locker.TryLock(ctx, key, job.Interval+lockMargin)
+	if err != nil {
+		s.logger.Error("failed to acquire job lock", logger.Fields{"job": job.Name, "error": err})
+		return
+	}
+	if !locked {
+		return
+	}
+
+	start := time.Now()
+	defer func() {
+		// 执行结束后把锁缩短到本周期结束前，下个周期各副本重新竞争
+		if err := s.locker.Refresh(context.WithoutCancel(ctx), key, job.Interval*9/10-time.Since(start)); err != nil {
+			s.logger.Error("failed to refresh job lock", logger.Fields{"job": job.Name, "error": err})
+		}
+	}()
+
+	runCtx, cancel := context.WithTimeout(ctx, job.Interval)
+	defer cancel()
+	if err := job.Run(runCtx); err != nil {
+		s.logger.Error("job failed", logger.Fields{"job": job.Name, "error": err})
+		return
+	}
+	s.logger.Info("job finished", logger.Fields{"job": job.Name, "elapsed": time.Since(start).String()})
+}